@@ -0,0 +1,313 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	"io"
+	"net/url"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/bitqiu/pix-gen/handler"
+	"github.com/bitqiu/pix-gen/pkg/encoder"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// renderRequest 是可以生成图片的请求参数
+type renderRequest interface {
+	Render() (image.Image, error)
+	Options(accept string) (encoder.Options, error)
+}
+
+func newCaptcha() renderRequest { return handler.NewCaptchaRequest() }
+func newQRCode() renderRequest  { return handler.NewQRCodeRequest() }
+func newBarcode() renderRequest { return handler.NewBarcodeRequest() }
+func newImage() renderRequest   { return handler.NewImageRequest() }
+func newAvatar() renderRequest  { return handler.NewAvatarRequest() }
+
+// generateCommand 返回生成单张图片的子命令
+// 命令行参数与 HTTP 查询参数同名，绑定到相同的请求结构体并使用相同的校验规则
+func generateCommand(name string, newRequest func() renderRequest) func(args []string) error {
+	return func(args []string) error {
+		req := newRequest()
+		fs := flag.NewFlagSet(name, flag.ExitOnError)
+		output := fs.String("o", "-", "output file, - for stdout; the extension selects the format when -format is not set")
+		formFlags(fs, req)
+		fs.Usage = func() {
+			fmt.Fprintf(fs.Output(), "usage: pix-gen %s [flags]\n\nflags have the same names and defaults as the query parameters of GET /%s:\n", name, name)
+			fs.PrintDefaults()
+		}
+		if rest := parseArgs(fs, args); len(rest) > 0 {
+			return fmt.Errorf("unexpected argument %q", rest[0])
+		}
+		if err := applyFlags(fs, req); err != nil {
+			return err
+		}
+		if err := handler.Validate(req); err != nil {
+			return err
+		}
+
+		// 未指定 format 时根据输出文件的扩展名选择格式，不支持的扩展名返回错误
+		accept := ""
+		formatSet := false
+		fs.Visit(func(f *flag.Flag) { formatSet = formatSet || f.Name == "format" })
+		if ext := filepath.Ext(*output); ext != "" && !formatSet {
+			f, err := encoder.ParseFormat(strings.TrimPrefix(ext, "."))
+			if err != nil {
+				return fmt.Errorf("unsupported output extension %q, use -format to choose png, jpeg, gif, webp or bmp", ext)
+			}
+			accept = f.ContentType()
+		}
+		opts, err := req.Options(accept)
+		if err != nil {
+			return err
+		}
+		img, err := req.Render()
+		if err != nil {
+			return err
+		}
+		data, err := encoder.EncodeBytes(img, opts)
+		if err != nil {
+			return err
+		}
+		return writeOutput(*output, data)
+	}
+}
+
+// formFlags 为请求结构体中每个带 form 标签的字段定义命令行参数
+// 布尔字段定义为布尔参数，其他字段以字符串接收，之后与查询参数一样转换类型
+func formFlags(fs *flag.FlagSet, req interface{}) {
+	var walk func(v reflect.Value)
+	walk = func(v reflect.Value) {
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field, value := t.Field(i), v.Field(i)
+			if field.Anonymous && field.Type.Kind() == reflect.Struct {
+				walk(value)
+				continue
+			}
+			name, _, _ := strings.Cut(field.Tag.Get("form"), ",")
+			if name == "" || name == "-" || !field.IsExported() {
+				continue
+			}
+			switch {
+			case field.Type.Kind() == reflect.Bool:
+				fs.Bool(name, value.Bool(), "")
+			case value.IsZero() || field.Type.Kind() == reflect.Struct:
+				fs.String(name, "", "")
+			default:
+				fs.String(name, fmt.Sprint(value.Interface()), "")
+			}
+		}
+	}
+	walk(reflect.ValueOf(req).Elem())
+}
+
+// applyFlags 将显式设置的命令行参数绑定到请求结构体，未设置的参数保留默认值
+func applyFlags(fs *flag.FlagSet, req interface{}) error {
+	form := map[string][]string{}
+	fs.Visit(func(fl *flag.Flag) {
+		form[fl.Name] = []string{fl.Value.String()}
+	})
+	return binding.MapFormWithTag(req, form, "form")
+}
+
+// parseArgs 解析参数，允许参数和位置参数交错出现，返回位置参数
+func parseArgs(fs *flag.FlagSet, args []string) []string {
+	var rest []string
+	for {
+		fs.Parse(args)
+		if fs.NArg() == 0 {
+			return rest
+		}
+		rest = append(rest, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// writeOutput 将数据写入文件，name 为 - 时写入标准输出
+func writeOutput(name string, data []byte) error {
+	if name == "-" {
+		_, err := os.Stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
+
+// runBatch 读取 CSV 或 JSON 任务文件并生成 ZIP 压缩包
+// CSV 的第一行为列名，必须包含 type 列，name 列为文件名，其他列与对应接口的查询参数同名
+// JSON 文件与 POST /batch 的请求体相同
+func runBatch(args []string) error {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	output := fs.String("o", "batch.zip", "output ZIP file, - for stdout")
+	name := fs.String("name", "", "file name template with {index}, {type} and {text}")
+	rest := parseArgs(fs, args)
+	if len(rest) != 1 {
+		return fmt.Errorf("usage: pix-gen batch [-o batch.zip] [-name template] jobs.csv|jobs.json")
+	}
+
+	var req *handler.BatchRequest
+	var err error
+	if strings.EqualFold(filepath.Ext(rest[0]), ".json") {
+		req, err = readBatchJSON(rest[0])
+	} else {
+		req, err = readBatchCSV(rest[0])
+	}
+	if err != nil {
+		return err
+	}
+	if *name != "" {
+		req.Name = *name
+	}
+	if err := handler.Validate(req); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var w io.Writer = os.Stdout
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	return handler.WriteBatch(ctx, w, req)
+}
+
+// readBatchJSON 读取与 POST /batch 请求体相同的 JSON 文件
+func readBatchJSON(path string) (*handler.BatchRequest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	req := handler.NewBatchRequest()
+	if err := json.Unmarshal(data, req); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return req, nil
+}
+
+// readBatchCSV 读取 CSV 任务文件，空单元格使用默认值
+func readBatchCSV(path string) (*handler.BatchRequest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("%s: empty file", path)
+	}
+	header := records[0]
+	typeCol := -1
+	for i, col := range header {
+		header[i] = strings.TrimSpace(col)
+		if header[i] == "type" {
+			typeCol = i
+		}
+	}
+	if typeCol < 0 {
+		return nil, fmt.Errorf("%s: missing type column", path)
+	}
+
+	req := handler.NewBatchRequest()
+	for n, record := range records[1:] {
+		var name string
+		form := map[string][]string{}
+		for i, value := range record {
+			switch {
+			case i == typeCol || value == "":
+			case header[i] == "name":
+				name = value
+			default:
+				form[header[i]] = []string{value}
+			}
+		}
+		job, err := handler.NewBatchJob(strings.TrimSpace(record[typeCol]), name, form)
+		if err != nil {
+			return nil, fmt.Errorf("%s line %d: %v", path, n+2, err)
+		}
+		req.Jobs = append(req.Jobs, job)
+	}
+	return req, nil
+}
+
+// runLabels 读取与 POST /labels 请求体相同的 JSON 文件并生成标签页 PDF
+func runLabels(args []string) error {
+	fs := flag.NewFlagSet("labels", flag.ExitOnError)
+	output := fs.String("o", "labels.pdf", "output PDF file, - for stdout")
+	rest := parseArgs(fs, args)
+	if len(rest) != 1 {
+		return fmt.Errorf("usage: pix-gen labels [-o labels.pdf] labels.json")
+	}
+
+	data, err := os.ReadFile(rest[0])
+	if err != nil {
+		return err
+	}
+	req := handler.NewLabelRequest()
+	if err := json.Unmarshal(data, req); err != nil {
+		return fmt.Errorf("%s: %v", rest[0], err)
+	}
+	if err := handler.Validate(req); err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := req.Render(&buf); err != nil {
+		return err
+	}
+	return writeOutput(*output, buf.Bytes())
+}
+
+// runSign 使用配置的第一个签名密钥为 URL 签名并输出
+// 指定 -body 时签名 POST 请求，请求体必须原样发送
+func runSign(args []string) error {
+	fs := flag.NewFlagSet("sign", flag.ExitOnError)
+	expires := fs.Duration("expires", 24*time.Hour, "link lifetime, 0 for a link that never expires")
+	bodyFile := fs.String("body", "", "sign a POST request with the JSON body in this file")
+	rest := parseArgs(fs, args)
+	if len(rest) != 1 {
+		return fmt.Errorf("usage: pix-gen sign [-expires 24h] [-body file.json] URL")
+	}
+
+	signer, err := cfg.Signing.Signer()
+	if err != nil {
+		return fmt.Errorf("signing.keys: %v", err)
+	}
+	var exp time.Time
+	if *expires != 0 {
+		exp = time.Now().Add(*expires)
+	}
+	u, err := url.Parse(rest[0])
+	if err != nil {
+		return err
+	}
+	method, body := "GET", []byte(nil)
+	if *bodyFile != "" {
+		if body, err = os.ReadFile(*bodyFile); err != nil {
+			return err
+		}
+		method = "POST"
+	}
+	u.RawQuery = signer.Sign(method, u.Path, u.Query(), body, exp).Encode()
+	fmt.Println(u.String())
+	return nil
+}
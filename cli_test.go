@@ -0,0 +1,79 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/handler"
+)
+
+// TestFormFlags 测试命令行参数绑定到请求结构体，未设置的参数保留默认值
+func TestFormFlags(t *testing.T) {
+	req := handler.NewImageRequest()
+	fs := flag.NewFlagSet("image", flag.ContinueOnError)
+	formFlags(fs, req)
+	rest := parseArgs(fs, []string{"--text", "0xabc", "extra", "-width", "300", "-fingerprint", "-format", "webp"})
+	if len(rest) != 1 || rest[0] != "extra" {
+		t.Errorf("rest = %v", rest)
+	}
+	if err := applyFlags(fs, req); err != nil {
+		t.Fatal(err)
+	}
+	if req.Text != "0xabc" || req.Width != 300 || req.Height != 100 || !req.Fingerprint || req.Format != "webp" {
+		t.Errorf("req = %+v", req)
+	}
+}
+
+// TestReadBatchCSV 测试 CSV 的每一行转换为一个批量任务
+func TestReadBatchCSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "jobs.csv")
+	data := "type,name,text,size\nqrcode,first,A-1,200\nbarcode,,12345,\n"
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	req, err := readBatchCSV(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(req.Jobs) != 2 || req.Jobs[0].Name != "first" || req.Jobs[1].Type != "barcode" {
+		t.Fatalf("jobs = %+v", req.Jobs)
+	}
+	if err := handler.Validate(req); err != nil {
+		t.Error(err)
+	}
+
+	if err := os.WriteFile(path, []byte("type,size\nqrcode,abc\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readBatchCSV(path); err == nil {
+		t.Error("expected error for invalid size")
+	}
+}
+
+// TestGenerateOutputExtension 测试输出文件的扩展名选择格式，不支持的扩展名返回错误
+func TestGenerateOutputExtension(t *testing.T) {
+	dir := t.TempDir()
+	run := generateCommand("qrcode", newQRCode)
+
+	if err := run([]string{"-text", "x", "-o", filepath.Join(dir, "out.JPG")}); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "out.JPG")); !bytes.HasPrefix(data, []byte("\xff\xd8")) {
+		t.Error("out.JPG: expected a JPEG file")
+	}
+
+	tiff := filepath.Join(dir, "out.tiff")
+	if err := run([]string{"-text", "x", "-o", tiff}); err == nil || !strings.Contains(err.Error(), "-format") {
+		t.Errorf("out.tiff: expected an error mentioning -format, got %v", err)
+	}
+	if _, err := os.Stat(tiff); !os.IsNotExist(err) {
+		t.Error("out.tiff: expected no file to be written")
+	}
+	if err := run([]string{"-text", "x", "-format", "png", "-o", tiff}); err != nil {
+		t.Errorf("out.tiff with -format png: %v", err)
+	}
+}
@@ -0,0 +1,279 @@
+package fonts
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+)
+
+// DefaultName 是未指定字体时使用的字体名称
+const DefaultName = "MiSans-Normal"
+
+// Font 是启动时解析好的一个字体
+type Font struct {
+	Name      string         `json:"name"`      // 字体名称，即不含扩展名的文件名
+	Family    string         `json:"family"`    // 字体族名称，读取自 name 表
+	Subfamily string         `json:"subfamily"` // 子族名称，如 Regular、Bold
+	Weight    int            `json:"weight"`    // 字重（100-900），读取自 OS/2 表
+	Style     string         `json:"style"`     // 样式：normal 或 italic
+	Source    string         `json:"source"`    // 来源：embedded 或字体文件路径
+	Font      *truetype.Font `json:"-"`         // 解析后的字体
+	Data      []byte         `json:"-"`         // 原始字体数据，用于嵌入 PDF
+}
+
+// Registry 是字体注册表，每个字体只解析一次
+type Registry struct {
+	mu      sync.RWMutex
+	fonts   map[string]*Font
+	names   []string
+	sums    map[string][sha256.Size]byte // 每个字体数据的哈希
+	version string
+}
+
+// NewRegistry 创建一个空的字体注册表
+func NewRegistry() *Registry {
+	return &Registry{fonts: map[string]*Font{}, sums: map[string][sha256.Size]byte{}}
+}
+
+// isFontFile 判断文件扩展名是否为支持的字体格式
+func isFontFile(name string) bool {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".ttf", ".otf":
+		return true
+	}
+	return false
+}
+
+// Add 解析字体数据并以 name 注册，同名字体会被覆盖
+func (r *Registry) Add(name, source string, data []byte) (*Font, error) {
+	parsed, err := freetype.ParseFont(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse font %s: %v", source, err)
+	}
+
+	f := &Font{
+		Name:      name,
+		Family:    parsed.Name(truetype.NameIDPreferredFamily),
+		Subfamily: parsed.Name(truetype.NameIDPreferredSubfamily),
+		Weight:    400,
+		Style:     "normal",
+		Source:    source,
+		Font:      parsed,
+		Data:      data,
+	}
+	if f.Family == "" {
+		f.Family = parsed.Name(truetype.NameIDFontFamily)
+	}
+	if f.Subfamily == "" {
+		f.Subfamily = parsed.Name(truetype.NameIDFontSubfamily)
+	}
+	if weight, italic, ok := parseOS2(data); ok {
+		f.Weight = weight
+		if italic {
+			f.Style = "italic"
+		}
+	} else if sub := strings.ToLower(f.Subfamily); strings.Contains(sub, "italic") || strings.Contains(sub, "oblique") {
+		f.Style = "italic"
+	}
+
+	key := strings.ToLower(name)
+	sum := sha256.Sum256(data)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.fonts[key]; !ok {
+		r.names = append(r.names, key)
+		sort.Strings(r.names)
+	}
+	r.fonts[key] = f
+	r.sums[key] = sum
+	r.version = ""
+	return f, nil
+}
+
+// Version 返回由已注册字体的名称和数据计算的版本，增删或替换字体后改变
+func (r *Registry) Version() string {
+	r.mu.RLock()
+	v := r.version
+	r.mu.RUnlock()
+	if v != "" {
+		return v
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h := sha256.New()
+	for _, name := range r.names {
+		sum := r.sums[name]
+		h.Write([]byte(name))
+		h.Write(sum[:])
+	}
+	r.version = hex.EncodeToString(h.Sum(nil)[:8])
+	return r.version
+}
+
+// LoadFS 注册文件系统根目录下的所有 .ttf 和 .otf 字体，跳过无法解析的字体
+func (r *Registry) LoadFS(fsys fs.FS, source string) error {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !isFontFile(entry.Name()) {
+			continue
+		}
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		src := source
+		if src == "" {
+			src = entry.Name()
+		}
+		r.addFile(name, src, data)
+	}
+	return nil
+}
+
+// LoadDir 注册目录下的所有 .ttf 和 .otf 字体，跳过无法解析的字体
+func (r *Registry) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("read font dir: %v", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !isFontFile(entry.Name()) {
+			continue
+		}
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(entry.Name(), filepath.Ext(entry.Name()))
+		r.addFile(name, path, data)
+	}
+	return nil
+}
+
+// addFile 注册目录中的字体文件，无法解析的字体（如 CFF 轮廓的 .otf）记录警告后跳过，不影响其他字体
+func (r *Registry) addFile(name, source string, data []byte) {
+	if _, err := r.Add(name, source, data); err != nil {
+		slog.Warn("skip font", slog.String("source", source), slog.String("error", err.Error()))
+	}
+}
+
+// Get 按名称查找字体，名称不区分大小写
+// name 为空时返回默认字体，默认字体不存在时返回第一个已注册的字体
+func (r *Registry) Get(name string) (*Font, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if name != "" {
+		if f, ok := r.fonts[strings.ToLower(name)]; ok {
+			return f, nil
+		}
+		return nil, errcode.Errorf(errcode.FontMissing, "unknown font: %s", name)
+	}
+	if f, ok := r.fonts[strings.ToLower(DefaultName)]; ok {
+		return f, nil
+	}
+	if len(r.names) > 0 {
+		return r.fonts[r.names[0]], nil
+	}
+	return nil, errcode.New(errcode.Internal, "no fonts available")
+}
+
+// List 返回所有已注册的字体，按名称排序
+func (r *Registry) List() []*Font {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]*Font, 0, len(r.names))
+	for _, name := range r.names {
+		list = append(list, r.fonts[name])
+	}
+	return list
+}
+
+// parseOS2 从字体数据的 OS/2 表中读取字重和斜体标志
+func parseOS2(data []byte) (weight int, italic bool, ok bool) {
+	if len(data) < 12 {
+		return 0, false, false
+	}
+	numTables := int(binary.BigEndian.Uint16(data[4:6]))
+	for i := 0; i < numTables; i++ {
+		rec := 12 + 16*i
+		if rec+16 > len(data) {
+			return 0, false, false
+		}
+		if string(data[rec:rec+4]) != "OS/2" {
+			continue
+		}
+		offset := int(binary.BigEndian.Uint32(data[rec+8 : rec+12]))
+		// usWeightClass 位于偏移 4，fsSelection 位于偏移 62
+		if offset+64 > len(data) {
+			return 0, false, false
+		}
+		weight = int(binary.BigEndian.Uint16(data[offset+4 : offset+6]))
+		fsSelection := binary.BigEndian.Uint16(data[offset+62 : offset+64])
+		return weight, fsSelection&0x1 != 0, true
+	}
+	return 0, false, false
+}
+
+// registry 是进程内的全局字体注册表
+var registry = NewRegistry()
+
+// loaded 表示 Load 是否已成功完成
+var loaded atomic.Bool
+
+// Load 注册所有内嵌字体以及 dirs 目录下的字体
+func Load(dirs ...string) error {
+	if err := registry.LoadFS(FontsFS, "embedded"); err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		if err := registry.LoadDir(dir); err != nil {
+			return err
+		}
+	}
+	loaded.Store(true)
+	return nil
+}
+
+// Loaded 返回字体是否已全部解析
+func Loaded() bool {
+	return loaded.Load()
+}
+
+// Get 从全局注册表中按名称查找字体
+func Get(name string) (*Font, error) {
+	return registry.Get(name)
+}
+
+// List 返回全局注册表中的所有字体
+func List() []*Font {
+	return registry.List()
+}
+
+// Version 返回全局注册表的字体版本
+func Version() string {
+	return registry.Version()
+}
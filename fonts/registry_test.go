@@ -0,0 +1,89 @@
+package fonts
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// TestRegistryLoadFS 测试从文件系统加载字体并读取元数据，跳过无法解析的字体
+func TestRegistryLoadFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Go-Regular.ttf": {Data: goregular.TTF},
+		"Go-Bold.ttf":    {Data: gobold.TTF},
+		"Go-Italic.otf":  {Data: goitalic.TTF},
+		"CFF.otf":        {Data: []byte("OTTO\x00\x09\x00\x80")},
+		"readme.txt":     {Data: []byte("not a font")},
+	}
+
+	r := NewRegistry()
+	if err := r.LoadFS(fsys, "test"); err != nil {
+		t.Fatalf("LoadFS: %v", err)
+	}
+	if got := len(r.List()); got != 3 {
+		t.Fatalf("List: expected 3 fonts, got %d", got)
+	}
+
+	tests := []struct {
+		name   string
+		weight int
+		style  string
+	}{
+		{"go-regular", 400, "normal"},
+		{"Go-Bold", 600, "normal"},
+		{"GO-ITALIC", 400, "italic"},
+	}
+	for _, tt := range tests {
+		f, err := r.Get(tt.name)
+		if err != nil {
+			t.Fatalf("Get(%q): %v", tt.name, err)
+		}
+		if f.Family != "Go" {
+			t.Errorf("Get(%q): expected family Go, got %q", tt.name, f.Family)
+		}
+		if f.Weight != tt.weight || f.Style != tt.style {
+			t.Errorf("Get(%q): expected %d/%s, got %d/%s", tt.name, tt.weight, tt.style, f.Weight, f.Style)
+		}
+	}
+}
+
+// TestRegistryGetDefault 测试默认字体的回退逻辑
+func TestRegistryGetDefault(t *testing.T) {
+	r := NewRegistry()
+	if _, err := r.Get(""); err == nil {
+		t.Errorf("Get: expected error on empty registry")
+	}
+
+	if _, err := r.Add("Go-Regular", "test", goregular.TTF); err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	f, err := r.Get("")
+	if err != nil || f.Name != "Go-Regular" {
+		t.Errorf("Get: expected fallback to Go-Regular, got %v, %v", f, err)
+	}
+	if _, err := r.Get("missing"); err == nil {
+		t.Errorf("Get: expected error for unknown font")
+	}
+}
+
+// TestRegistryVersion 测试字体版本在替换或增加字体后改变
+func TestRegistryVersion(t *testing.T) {
+	r := NewRegistry()
+	r.Add("Go", "test", goregular.TTF)
+	v1 := r.Version()
+	if v1 == "" || r.Version() != v1 {
+		t.Fatalf("Version: expected a stable version, got %q", v1)
+	}
+	r.Add("Go", "test", gobold.TTF)
+	v2 := r.Version()
+	if v2 == v1 {
+		t.Error("Version: expected a new version after replacing a font")
+	}
+	r.Add("Go-Italic", "test", goitalic.TTF)
+	if r.Version() == v2 {
+		t.Error("Version: expected a new version after adding a font")
+	}
+}
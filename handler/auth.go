@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // 解码 JPEG 标志
+	_ "image/png"  // 解码 PNG 标志
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// tenantKey 是请求上下文中保存当前租户的键
+type tenantKey struct{}
+
+// endpointKey 是请求上下文中保存计入用量的接口名称的键
+type endpointKey struct{}
+
+// tenant 是加载后的租户
+type tenant struct {
+	config.Tenant
+	endpoints map[string]bool // 允许的接口，为 nil 时允许全部接口
+	logo      image.Image     // 解码后的标志
+
+	mu    sync.Mutex
+	usage Usage // 本月的用量
+}
+
+// Usage 是租户一个月的用量
+type Usage struct {
+	Tenant    string         `json:"tenant"`              // 租户名称
+	Month     string         `json:"month"`               // 月份，如 2024-05
+	Renders   int            `json:"renders"`             // 本月生成次数
+	Quota     int            `json:"quota,omitempty"`     // 每月生成次数上限，为 0 时不限
+	Endpoints map[string]int `json:"endpoints,omitempty"` // 各接口的生成次数
+}
+
+// rollover 跨月时重新计数，调用方需要持有锁
+func (t *tenant) rollover(now time.Time) {
+	if month := now.UTC().Format("2006-01"); t.usage.Month != month {
+		t.usage = Usage{Tenant: t.Name, Month: month, Quota: t.MonthlyQuota, Endpoints: map[string]int{}}
+	}
+}
+
+// allows 判断租户本月剩余的配额是否还够 n 次生成
+func (t *tenant) allows(n int, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover(now)
+	return t.MonthlyQuota <= 0 || t.usage.Renders+n <= t.MonthlyQuota
+}
+
+// add 记录租户在接口上的 n 次生成
+// 用量保存在内存中，重启后清零
+func (t *tenant) add(endpoint string, n int, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover(now)
+	t.usage.Renders += n
+	t.usage.Endpoints[endpoint] += n
+}
+
+// snapshot 返回用量的副本
+func (t *tenant) snapshot(now time.Time) Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rollover(now)
+	u := t.usage
+	u.Endpoints = map[string]int{}
+	for k, v := range t.usage.Endpoints {
+		u.Endpoints[k] = v
+	}
+	return u
+}
+
+var (
+	// ErrMissingKey 表示请求没有携带 API key
+	ErrMissingKey = errcode.New(errcode.MissingKey, "missing API key")
+	// ErrInvalidKey 表示 API key 不属于任何租户
+	ErrInvalidKey = errcode.New(errcode.InvalidKey, "invalid API key")
+	// ErrEndpointDenied 表示租户无权使用接口
+	ErrEndpointDenied = errcode.New(errcode.EndpointDenied, "endpoint not allowed")
+	// ErrQuotaExceeded 表示租户超出每月配额
+	ErrQuotaExceeded = errcode.New(errcode.QuotaExceeded, "monthly quota exceeded")
+)
+
+// tenantError 是带有具体说明的租户错误，可以用 errors.Is 判断类别
+type tenantError struct {
+	kind error
+	msg  string
+}
+
+func (e *tenantError) Error() string { return e.msg }
+func (e *tenantError) Unwrap() error { return e.kind }
+
+// Tenants 是按 API key 索引的租户，HTTP 和 gRPC 共用同一份用量
+type Tenants struct {
+	byKey map[[sha256.Size]byte]*tenant
+}
+
+// NewTenants 加载租户及其标志
+func NewTenants(tenants []config.Tenant) (*Tenants, error) {
+	ts := &Tenants{byKey: map[[sha256.Size]byte]*tenant{}}
+	for _, tc := range tenants {
+		t := &tenant{Tenant: tc}
+		if len(tc.Endpoints) > 0 {
+			t.endpoints = map[string]bool{}
+			for _, e := range tc.Endpoints {
+				t.endpoints[e] = true
+			}
+		}
+		if tc.Defaults.Logo != "" {
+			logo, err := loadLogo(tc.Defaults.Logo)
+			if err != nil {
+				return nil, fmt.Errorf("tenant %s: %v", tc.Name, err)
+			}
+			t.logo = logo
+		}
+		for _, k := range tc.Keys {
+			ts.byKey[sha256.Sum256([]byte(k))] = t
+		}
+	}
+	return ts, nil
+}
+
+// lookup 返回 API key 所属的租户，key 未知时返回 nil
+func (ts *Tenants) lookup(key string) *tenant {
+	return ts.byKey[sha256.Sum256([]byte(key))]
+}
+
+// Authorize 校验 API key、租户对接口的权限和剩余配额，返回带有租户和接口名称的上下文
+// 配额在生成成功后由 ChargeQuota 扣除；endpoint 为 usage 时只校验 API key
+func (ts *Tenants) Authorize(ctx context.Context, key, endpoint string) (context.Context, error) {
+	if key == "" {
+		return ctx, ErrMissingKey
+	}
+	t := ts.lookup(key)
+	if t == nil {
+		return ctx, ErrInvalidKey
+	}
+	ctx = context.WithValue(ctx, tenantKey{}, t)
+	ctx = context.WithValue(ctx, endpointKey{}, endpoint)
+	if endpoint == "usage" {
+		return ctx, nil
+	}
+	if t.endpoints != nil && !t.endpoints[endpoint] {
+		return ctx, &tenantError{ErrEndpointDenied, fmt.Sprintf("endpoint %s is not allowed for tenant %s", endpoint, t.Name)}
+	}
+	return ctx, CheckQuota(ctx, 1)
+}
+
+// Authenticate 返回校验 API key 的中间件
+// API key 来自 Authorization: Bearer 请求头或 key 查询参数，缺少或未知时返回 401，
+// 租户无权使用接口时返回 403，超出每月配额时返回 429
+func (ts *Tenants) Authenticate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx, err := ts.Authorize(c.Request.Context(), apiKey(c), endpointName(c.FullPath()))
+		c.Request = c.Request.WithContext(ctx)
+		switch {
+		case errors.Is(err, ErrMissingKey):
+			c.Header("WWW-Authenticate", `Bearer realm="pix-gen"`)
+			writeError(c, err)
+		case errors.Is(err, ErrInvalidKey):
+			c.Header("WWW-Authenticate", `Bearer realm="pix-gen", error="invalid_token"`)
+			writeError(c, err)
+		case err != nil:
+			writeError(c, err)
+		default:
+			c.Next()
+		}
+	}
+}
+
+// Authenticate 加载租户并返回校验 API key 的中间件
+func Authenticate(tenants []config.Tenant) (gin.HandlerFunc, error) {
+	ts, err := NewTenants(tenants)
+	if err != nil {
+		return nil, err
+	}
+	return ts.Authenticate(), nil
+}
+
+// loadLogo 读取并解码标志文件
+func loadLogo(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("decode logo %s: %v", path, err)
+	}
+	if img.Bounds().Empty() {
+		return nil, fmt.Errorf("logo %s is empty", path)
+	}
+	return img, nil
+}
+
+// endpointName 返回路由路径的第一段作为接口名称，如 /placeholder/:w/:h 为 placeholder
+func endpointName(route string) string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(route, "/"), "/")
+	return name
+}
+
+// tenantOf 返回请求上下文中的租户，未启用认证时返回 nil
+func tenantOf(ctx context.Context) *tenant {
+	t, _ := ctx.Value(tenantKey{}).(*tenant)
+	return t
+}
+
+// TenantName 返回上下文中租户的名称，未启用认证时返回空字符串
+func TenantName(ctx context.Context) string {
+	if t := tenantOf(ctx); t != nil {
+		return t.Name
+	}
+	return ""
+}
+
+// CheckQuota 检查上下文中的租户本月剩余的配额是否还够 n 次生成，未启用认证时不限制
+// 只检查不扣除，并发的请求可能使用量略微超过配额
+func CheckQuota(ctx context.Context, n int) error {
+	t := tenantOf(ctx)
+	if t == nil || n <= 0 {
+		return nil
+	}
+	if !t.allows(n, time.Now()) {
+		return &tenantError{ErrQuotaExceeded, fmt.Sprintf("monthly quota of %d renders exceeded", t.MonthlyQuota)}
+	}
+	return nil
+}
+
+// ChargeQuota 在生成成功后为上下文中的租户记录 n 次生成，计入 Authorize 时的接口，未启用认证时不记录
+func ChargeQuota(ctx context.Context, n int) {
+	if t := tenantOf(ctx); t != nil && n > 0 {
+		endpoint, _ := ctx.Value(endpointKey{}).(string)
+		t.add(endpoint, n, time.Now())
+	}
+}
+
+// checkQuota 检查请求的租户剩余的配额是否还够 n 次生成，不够时返回 429 并返回 false
+func checkQuota(c *gin.Context, n int) bool {
+	if err := CheckQuota(c.Request.Context(), n); err != nil {
+		writeError(c, err)
+		return false
+	}
+	return true
+}
+
+// ApplyTenantDefaults 用租户的默认参数覆盖全局默认值，请求中的参数仍然优先
+func ApplyTenantDefaults(ctx context.Context, req interface{}) {
+	t := tenantOf(ctx)
+	if t == nil {
+		return
+	}
+	d := t.Defaults
+	set := func(dst *string, v string) {
+		if v != "" {
+			*dst = v
+		}
+	}
+	switch r := req.(type) {
+	case *QRCodeRequest:
+		set(&r.Color, d.Color)
+		r.logo = t.logo
+	case *BarcodeRequest:
+		set(&r.Color, d.Color)
+		set(&r.Background, d.Background)
+	case *PlaceholderRequest:
+		set(&r.Fg, d.Color)
+		set(&r.Bg, d.Background)
+	case *ImageRequest:
+		set(&r.TipText, d.TipText)
+	}
+}
+
+// HandleUsage 返回当前租户本月的用量
+func HandleUsage(c *gin.Context) {
+	t := tenantOf(c.Request.Context())
+	if t == nil {
+		writeError(c, errcode.New(errcode.NotFound, "auth is not enabled"))
+		return
+	}
+	c.JSON(http.StatusOK, t.snapshot(time.Now()))
+}
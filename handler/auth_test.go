@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+// TestAuthenticate 测试 API key、接口权限、每月配额和租户默认参数，配额只在生成成功后扣除
+func TestAuthenticate(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth, err := Authenticate([]config.Tenant{{
+		Name:         "payments",
+		Keys:         []string{"pay-0123456789abcdef"},
+		Endpoints:    []string{"image"},
+		MonthlyQuota: 2,
+		Defaults:     config.TenantDefaults{TipText: "请核对收款地址"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := gin.New()
+	r.Use(auth)
+	var tipText string
+	r.GET("/image", func(c *gin.Context) {
+		req := NewImageRequest()
+		if bindRequest(c, req) {
+			tipText = req.TipText
+			ChargeQuota(c.Request.Context(), 1)
+		}
+	})
+	r.GET("/captcha", func(c *gin.Context) {})
+	r.GET("/usage", HandleUsage)
+
+	get := func(target, auth string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if auth != "" {
+			req.Header.Set("Authorization", auth)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	if w := get("/image", ""); w.Code != http.StatusUnauthorized || w.Header().Get("WWW-Authenticate") == "" {
+		t.Errorf("missing key: %d", w.Code)
+	}
+	if w := get("/image?key=wrong", ""); w.Code != http.StatusUnauthorized {
+		t.Errorf("wrong key: %d", w.Code)
+	}
+	if w := get("/captcha", "Bearer pay-0123456789abcdef"); w.Code != http.StatusForbidden {
+		t.Errorf("endpoint not allowed: %d", w.Code)
+	}
+	if w := get("/image?key=pay-0123456789abcdef&width=x", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid parameters: %d", w.Code)
+	}
+	if w := get("/image", "bearer pay-0123456789abcdef"); w.Code != http.StatusOK || tipText != "请核对收款地址" {
+		t.Errorf("tenant defaults: %d tipText %q", w.Code, tipText)
+	}
+	if get("/image?key=pay-0123456789abcdef&tipText=x", ""); tipText != "x" {
+		t.Errorf("request parameters must override tenant defaults, got %q", tipText)
+	}
+	if w := get("/image?key=pay-0123456789abcdef", ""); w.Code != http.StatusTooManyRequests {
+		t.Errorf("over quota: %d", w.Code)
+	}
+	w := get("/usage?key=pay-0123456789abcdef", "")
+	if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"renders":2`) || !strings.Contains(w.Body.String(), `"image":2`) {
+		t.Errorf("usage: %d %s", w.Code, w.Body.String())
+	}
+}
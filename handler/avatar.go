@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"image"
+
+	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/bitqiu/pix-gen/pkg/avatar"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleAvatar 是处理头像生成请求的处理程序
+// GET 从查询参数读取参数，POST 从 JSON 请求体读取参数
+func HandleAvatar(c *gin.Context) {
+	req := NewAvatarRequest()
+	if !bindRequest(c, req) {
+		return
+	}
+
+	writeCached(c, req, req.Render, req.OutputRequest)
+}
+
+// Render 生成头像图片
+func (r *AvatarRequest) Render() (image.Image, error) {
+	palette, err := avatar.ParsePalette(r.Palette)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := avatar.Options{Size: r.Size, Type: r.Type, Shape: r.Shape, Palette: palette, Text: r.Name}
+	if r.Type == "initials" {
+		f, err := fonts.Get(r.Font)
+		if err != nil {
+			return nil, err
+		}
+		opts.Font = f.Font
+	}
+	return avatar.Render(r.Seed, opts)
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"image"
+
+	"github.com/bitqiu/pix-gen/pkg/barcode"
+	"github.com/bitqiu/pix-gen/pkg/colors"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleBarcode 是处理生成 Code 128 条码请求的处理程序
+// GET 从查询参数读取参数，POST 从 JSON 请求体读取参数
+func HandleBarcode(c *gin.Context) {
+	req := NewBarcodeRequest()
+	if !bindRequest(c, req) {
+		return
+	}
+
+	writeCached(c, req, req.Render, req.OutputRequest)
+}
+
+// Render 生成条码图片
+func (r *BarcodeRequest) Render() (image.Image, error) {
+	fg, err := colors.Parse(r.Color)
+	if err != nil {
+		return nil, err
+	}
+	bg, err := colors.Parse(r.Background)
+	if err != nil {
+		return nil, err
+	}
+	b, err := barcode.Code128(r.Text)
+	if err != nil {
+		return nil, err
+	}
+	return b.Image(r.Width, r.Height, fg, bg), nil
+}
@@ -0,0 +1,301 @@
+package handler
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/bitqiu/pix-gen/pkg/encoder"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// batchWorkers 是批量生成的并发数
+var batchWorkers = runtime.NumCPU()
+
+// batchRequests 是批量任务类型到默认请求参数的映射
+var batchRequests = map[string]func() Renderer{
+	"qrcode":  func() Renderer { return NewQRCodeRequest() },
+	"barcode": func() Renderer { return NewBarcodeRequest() },
+	"image":   func() Renderer { return NewImageRequest() },
+}
+
+// NewBatchJob 根据表单形式的参数创建批量任务，参数名与 GET 查询参数相同
+func NewBatchJob(kind, name string, form map[string][]string) (BatchJob, error) {
+	newRequest, ok := batchRequests[kind]
+	if !ok {
+		return BatchJob{}, errcode.Errorf(errcode.InvalidRequest, "unknown job type %q", kind)
+	}
+	req := newRequest()
+	if err := binding.MapFormWithTag(req, form, "form"); err != nil {
+		return BatchJob{}, err
+	}
+	params, err := json.Marshal(req)
+	if err != nil {
+		return BatchJob{}, err
+	}
+	return BatchJob{Type: kind, Name: name, Params: params}, nil
+}
+
+// BatchJob 是批量生成中的单个任务
+type BatchJob struct {
+	Type   string          `json:"type" binding:"required" doc:"任务类型：qrcode、barcode、image"`
+	Name   string          `json:"name,omitempty" doc:"文件名，优先于批量请求的文件名模板"`
+	Params json.RawMessage `json:"params" doc:"与对应 POST 接口相同的请求体"`
+}
+
+// BatchRequest 是批量生成的参数
+type BatchRequest struct {
+	Name string     `json:"name,omitempty" doc:"文件名模板，支持 {index}、{type}、{text}"`
+	Jobs []BatchJob `json:"jobs" binding:"required,min=1,max=10000,dive" doc:"任务列表"`
+}
+
+// batchEntry 是清单中的单个任务结果
+type batchEntry struct {
+	Index int          `json:"index"`
+	Type  string       `json:"type"`
+	Name  string       `json:"name,omitempty"`
+	Code  errcode.Code `json:"code,omitempty"`
+	Error string       `json:"error,omitempty"`
+}
+
+// batchManifest 是压缩包中的 manifest.json
+type batchManifest struct {
+	Total     int          `json:"total"`
+	Succeeded int          `json:"succeeded"`
+	Files     []batchEntry `json:"files"`
+	Failed    []batchEntry `json:"failed"`
+}
+
+// BatchResult 是单个任务的渲染结果
+type BatchResult struct {
+	Data   []byte
+	Format encoder.Format
+	Err    error
+}
+
+// HandleBatch 是处理批量生成请求的处理程序
+// 任务由固定数量的 worker 并发渲染，按任务顺序写入 ZIP 流，失败的任务记录在 manifest.json 中
+func HandleBatch(c *gin.Context) {
+	req := NewBatchRequest()
+	if err := c.ShouldBindJSON(req); err != nil {
+		bindError(c, err)
+		return
+	}
+	// 剩余的配额需要够所有任务使用，成功生成的任务各计一次
+	if !checkQuota(c, len(req.Jobs)) {
+		return
+	}
+
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", `attachment; filename="batch.zip"`)
+	c.Status(http.StatusOK)
+	// ZIP 流的总时长可能超过写超时，每次写入前延长
+	if err := WriteBatch(c.Request.Context(), deadlineWriter{c}, req); err != nil {
+		// 响应已经开始，写入失败通常是客户端已断开
+		c.Error(err)
+	}
+}
+
+// NewBatchRequest 返回带有默认值的批量生成参数
+func NewBatchRequest() *BatchRequest {
+	return &BatchRequest{Name: "{index}-{type}"}
+}
+
+// RenderBatch 用固定数量的 worker 并发渲染 n 个任务，并按任务顺序对每个结果调用 emit
+// ctx 取消后不再分发任务，剩余任务的结果为 ctx 的错误；emit 返回错误时停止并返回该错误
+func RenderBatch(ctx context.Context, n int, render func(ctx context.Context, i int) BatchResult, emit func(i int, res BatchResult) error) error {
+	// 先取消再等待 worker 退出，emit 出错时不再渲染剩余的任务
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	results := make([]BatchResult, n)
+	done := make([]chan struct{}, n)
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	// 按顺序分发任务，ctx 取消后不再分发
+	// 已分发但未写出的任务不超过 worker 数的两倍，避免写出慢时渲染结果堆积在内存中
+	workers := max(1, batchWorkers)
+	ahead := make(chan struct{}, 2*workers)
+	queue := make(chan int)
+	go func() {
+		defer close(queue)
+		// cancelFrom 将第 i 个及之后的任务记为 ctx 的错误
+		cancelFrom := func(i int) {
+			for ; i < n; i++ {
+				results[i].Err = ctx.Err()
+				close(done[i])
+			}
+		}
+		for i := 0; i < n; i++ {
+			select {
+			case ahead <- struct{}{}:
+			case <-ctx.Done():
+				cancelFrom(i)
+				return
+			}
+			select {
+			case queue <- i:
+			case <-ctx.Done():
+				<-ahead
+				cancelFrom(i)
+				return
+			}
+		}
+	}()
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				results[i] = safeRender(ctx, i, render)
+				close(done[i])
+			}
+		}()
+	}
+
+	for i := 0; i < n; i++ {
+		<-done[i]
+		if err := emit(i, results[i]); err != nil {
+			return err
+		}
+		results[i] = BatchResult{}
+		// 释放已写出任务的名额，ctx 取消后未分发的任务没有占用名额
+		select {
+		case <-ahead:
+		default:
+		}
+	}
+	return nil
+}
+
+// safeRender 渲染单个任务，worker 协程中的 panic 不会被 gin 恢复，这里转换为任务错误
+func safeRender(ctx context.Context, i int, render func(ctx context.Context, i int) BatchResult) (res BatchResult) {
+	defer func() {
+		if r := recover(); r != nil {
+			res = BatchResult{Err: errcode.Errorf(errcode.Internal, "render failed: %v", r)}
+		}
+	}()
+	return render(ctx, i)
+}
+
+// WriteBatch 并发渲染批量任务并按任务顺序写入 ZIP 压缩包
+// 单个任务的失败记录在 manifest.json 中，只有写入 w 失败时返回错误
+func WriteBatch(ctx context.Context, w io.Writer, req *BatchRequest) error {
+	zw := zip.NewWriter(w)
+	manifest := batchManifest{Total: len(req.Jobs), Files: []batchEntry{}, Failed: []batchEntry{}}
+	used := map[string]bool{}
+	render := func(ctx context.Context, i int) BatchResult {
+		return renderJob(ctx, req.Jobs[i])
+	}
+	err := RenderBatch(ctx, len(req.Jobs), render, func(i int, res BatchResult) error {
+		job := req.Jobs[i]
+		entry := batchEntry{Index: i + 1, Type: job.Type}
+		if res.Err != nil {
+			entry.Code = errcode.Of(res.Err)
+			entry.Error = res.Err.Error()
+			manifest.Failed = append(manifest.Failed, entry)
+			return nil
+		}
+
+		entry.Name = uniqueName(used, batchFileName(req.Name, job, i+1, len(req.Jobs)), res.Format.Extension())
+		// 图片已经压缩过，直接存储
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: entry.Name, Method: zip.Store})
+		if err == nil {
+			_, err = fw.Write(res.Data)
+		}
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, entry)
+		manifest.Succeeded++
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	fw, err := zw.Create("manifest.json")
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(fw)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(manifest); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// renderJob 解析并渲染单个任务
+func renderJob(ctx context.Context, job BatchJob) BatchResult {
+	newRequest, ok := batchRequests[job.Type]
+	if !ok {
+		return BatchResult{Err: errcode.Errorf(errcode.InvalidRequest, "unknown job type %q", job.Type)}
+	}
+	req := newRequest()
+	ApplyDefaults(ctx, req)
+	if len(job.Params) > 0 {
+		if err := json.Unmarshal(job.Params, req); err != nil {
+			return BatchResult{Err: validationError(err)}
+		}
+	}
+	data, opts, err := Encode(ctx, job.Type, req)
+	return BatchResult{Data: data, Format: opts.Format, Err: err}
+}
+
+// batchFileName 生成任务的文件名，不含扩展名
+// 任务未指定文件名时使用模板，{index} 按任务总数补零
+func batchFileName(pattern string, job BatchJob, index, total int) string {
+	name := job.Name
+	if name == "" {
+		var params struct {
+			Text string `json:"text"`
+		}
+		_ = json.Unmarshal(job.Params, &params)
+		width := len(strconv.Itoa(total))
+		name = strings.NewReplacer(
+			"{index}", fmt.Sprintf("%0*d", width, index),
+			"{type}", job.Type,
+			"{text}", params.Text,
+		).Replace(pattern)
+	}
+
+	// 文件名不能包含目录，避免解压到其他位置
+	name = strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r < 0x20 {
+			return '_'
+		}
+		return r
+	}, name)
+	name = strings.Trim(name, " .")
+	if name == "" {
+		name = strconv.Itoa(index)
+	}
+	return name
+}
+
+// uniqueName 为文件名补上扩展名，重名时添加序号
+func uniqueName(used map[string]bool, name, ext string) string {
+	if strings.EqualFold(path.Ext(name), ext) {
+		name = strings.TrimSuffix(name, path.Ext(name))
+	}
+	candidate := name + ext
+	for n := 2; used[candidate]; n++ {
+		candidate = fmt.Sprintf("%s-%d%s", name, n, ext)
+	}
+	used[candidate] = true
+	return candidate
+}
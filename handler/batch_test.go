@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// TestHandleBatch 测试批量生成按顺序写入文件并在清单中记录失败的任务
+func TestHandleBatch(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.POST("/batch", HandleBatch)
+
+	body := `{"name":"tag-{text}","jobs":[
+		{"type":"qrcode","params":{"text":"A-1"}},
+		{"type":"qrcode","params":{"text":"A-1","format":"webp"}},
+		{"type":"qrcode","params":{"text":"A-1"}},
+		{"type":"barcode","name":"../code","params":{"text":"12345678"}},
+		{"type":"video"},
+		{"type":"qrcode","params":{"text":"x","size":0}},
+		{"type":"image","params":{"markup":"<size=3000>a</size>"}},
+		{"type":"image","params":{"width":4096,"height":4096,"markup":"<size=2>a</size>"}}
+	]}`
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/batch", strings.NewReader(body)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(w.Body.Bytes()), int64(w.Body.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	var manifest batchManifest
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+		if f.Name == "manifest.json" {
+			rc, _ := f.Open()
+			data, _ := io.ReadAll(rc)
+			rc.Close()
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	want := "tag-A-1.png,tag-A-1.webp,tag-A-1-2.png,_code.png,manifest.json"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("files = %s, want %s", got, want)
+	}
+	if manifest.Total != 8 || manifest.Succeeded != 4 || len(manifest.Failed) != 4 {
+		t.Fatalf("manifest = %+v", manifest)
+	}
+	if manifest.Failed[0].Index != 5 || manifest.Failed[1].Index != 6 || manifest.Failed[2].Index != 7 || manifest.Failed[3].Index != 8 {
+		t.Errorf("failed = %+v", manifest.Failed)
+	}
+	// 字号倍数超出范围和字号超限的任务在渲染前失败
+	if failed := manifest.Failed[2]; failed.Code != errcode.InvalidMarkup {
+		t.Errorf("size multiplier job: %+v", failed)
+	}
+	if failed := manifest.Failed[3]; failed.Code != errcode.SizeTooLarge {
+		t.Errorf("font size job: %+v", failed)
+	}
+}
+
+// TestRenderBatchLookAhead 测试写出阻塞时已渲染但未写出的任务不超过 worker 数的两倍
+func TestRenderBatchLookAhead(t *testing.T) {
+	defer func(n int) { batchWorkers = n }(batchWorkers)
+	batchWorkers = 2
+
+	var rendered atomic.Int32
+	release := make(chan struct{})
+	render := func(ctx context.Context, i int) BatchResult {
+		rendered.Add(1)
+		return BatchResult{}
+	}
+	emit := func(i int, res BatchResult) error {
+		if i == 0 {
+			<-release
+		}
+		return nil
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- RenderBatch(context.Background(), 100, render, emit) }()
+
+	time.Sleep(50 * time.Millisecond)
+	if got := rendered.Load(); got > 4 {
+		t.Errorf("RenderBatch: expected at most 4 jobs ahead of the writer, got %d", got)
+	}
+	close(release)
+	if err := <-errc; err != nil {
+		t.Fatalf("RenderBatch: %v", err)
+	}
+	if got := rendered.Load(); got != 100 {
+		t.Errorf("RenderBatch: expected 100 jobs rendered, got %d", got)
+	}
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"net/http"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/bitqiu/pix-gen/pkg/cache"
+	"github.com/bitqiu/pix-gen/pkg/encoder"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// responseCache 缓存编码后的响应，键为规范化的请求
+var responseCache = cache.New(settings.Cache.MaxBytes)
+
+// buildVersion 是程序的模块版本和构建时的 VCS 修订号
+var buildVersion = readBuildVersion()
+
+// readBuildVersion 从构建信息中读取模块版本和 VCS 修订号，没有构建信息时返回空字符串
+func readBuildVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return ""
+	}
+	v := info.Main.Version
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" || s.Key == "vcs.modified" {
+			v += " " + s.Value
+		}
+	}
+	return v
+}
+
+// cacheKey 根据程序和字体的版本、请求路径、租户、绑定后的参数和协商后的输出格式生成缓存键
+// 参数已填充默认值，因此省略参数和显式传入默认值的请求使用同一个键；升级程序或更换字体后键随之改变
+func cacheKey(scope string, req interface{}, opts encoder.Options, asJSON bool) (string, error) {
+	params, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s\x00%s\x00%s\x00%s/%d/%v/%t", buildVersion, fonts.Version(), scope, params, opts.Format, opts.Quality, opts.Compression, asJSON), nil
+}
+
+// etag 返回缓存键对应的强 ETag，同一版本下相同的参数总是生成相同的图片
+func etag(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// etagMatch 判断 If-None-Match 请求头是否包含 tag，按弱比较忽略 W/ 前缀
+func etagMatch(header, tag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == "*" || v == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// writeCached 返回可缓存的图片，相同参数生成的图片相同，按参数缓存编码后的响应
+// 请求携带匹配的 If-None-Match 时返回 304，缓存命中时直接返回编码后的内容，否则渲染、编码并缓存
+func writeCached(c *gin.Context, req interface{}, render func() (image.Image, error), out OutputRequest) {
+	opts, err := outputOptions(c, out)
+	if err != nil {
+		writeError(c, err)
+		return
+	}
+	asJSON := wantsJSON(c, out)
+	// 租户的标志不在参数中，按租户区分缓存；租户的响应只允许客户端缓存，不能由共享缓存返回给其他租户
+	scope, visibility := c.Request.URL.Path, "public"
+	if t := tenantOf(c.Request.Context()); t != nil {
+		scope, visibility = t.Name+" "+scope, "private"
+	}
+	key, err := cacheKey(scope, req, opts, asJSON)
+	if err != nil {
+		writeError(c, errcode.Errorf(errcode.Internal, "cache key: %w", err))
+		return
+	}
+
+	tag := etag(key)
+	cacheHeaders := func() {
+		c.Header("ETag", tag)
+		c.Header("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, int(time.Duration(settings.Cache.MaxAge).Seconds())))
+	}
+	if etagMatch(c.GetHeader("If-None-Match"), tag) {
+		cacheHeaders()
+		c.Status(http.StatusNotModified)
+		return
+	}
+	if e, ok := responseCache.Get(key); ok {
+		cacheHeaders()
+		c.Header("X-Cache", "HIT")
+		c.Data(http.StatusOK, e.ContentType, e.Data)
+		return
+	}
+
+	contentType, data, ok := renderResponse(c, render, opts, asJSON)
+	if !ok {
+		return
+	}
+	responseCache.Add(key, cache.Entry{ContentType: contentType, Data: data})
+	cacheHeaders()
+	c.Header("X-Cache", "MISS")
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// HandleCacheStats 返回响应缓存的命中和未命中次数
+func HandleCacheStats(c *gin.Context) {
+	c.JSON(http.StatusOK, responseCache.Stats())
+}
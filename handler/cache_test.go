@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/encoder"
+	"github.com/gin-gonic/gin"
+)
+
+// TestHandleQrcodeCache 测试二维码响应的缓存、ETag 和 304
+func TestHandleQrcodeCache(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Configure(config.Default())
+	r := gin.New()
+	r.GET("/qrcode", HandleQrcode)
+	get := func(target, ifNoneMatch string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := get("/qrcode?text=cache", "")
+	if first.Code != http.StatusOK || first.Header().Get("X-Cache") != "MISS" {
+		t.Fatalf("first request: %d %s", first.Code, first.Header().Get("X-Cache"))
+	}
+	tag := first.Header().Get("ETag")
+	if tag == "" || first.Header().Get("Cache-Control") != "public, max-age=86400" {
+		t.Errorf("missing cache headers: %v", first.Header())
+	}
+
+	// 显式传入默认值与省略参数是同一个请求
+	second := get("/qrcode?text=cache&size=300&level=H", "")
+	if second.Header().Get("X-Cache") != "HIT" || second.Header().Get("ETag") != tag || second.Body.String() != first.Body.String() {
+		t.Errorf("second request should hit the cache with the same ETag")
+	}
+
+	if w := get("/qrcode?text=cache", `W/"other", `+tag); w.Code != http.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("If-None-Match: got %d with %d bytes, want 304", w.Code, w.Body.Len())
+	}
+	if w := get("/qrcode?text=other", tag); w.Code != http.StatusOK || w.Header().Get("ETag") == tag {
+		t.Errorf("different text must not match the ETag")
+	}
+
+	if s := responseCache.Stats(); s.Hits != 1 || s.Misses != 2 {
+		t.Errorf("stats = %+v", s)
+	}
+
+	// 租户的响应不能由共享缓存保存
+	authed := gin.New()
+	authed.Use(func(c *gin.Context) {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), tenantKey{}, &tenant{Tenant: config.Tenant{Name: "acme"}}))
+	})
+	authed.GET("/qrcode", HandleQrcode)
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		authed.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/qrcode?text=cache", nil))
+		if got := w.Header().Get("Cache-Control"); w.Code != http.StatusOK || got != "private, max-age=86400" {
+			t.Errorf("tenant request %d: %d Cache-Control %q", i, w.Code, got)
+		}
+	}
+}
+
+// TestCacheKeyVersion 测试缓存键随程序和字体的版本改变
+func TestCacheKeyVersion(t *testing.T) {
+	defer func(v string) { buildVersion = v }(buildVersion)
+	key := func() string {
+		k, err := cacheKey("/qrcode", NewQRCodeRequest(), encoder.Options{Format: encoder.PNG}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return k
+	}
+
+	before := key()
+	if !strings.Contains(before, fonts.Version()) {
+		t.Errorf("cache key %q does not contain the font version", before)
+	}
+	buildVersion = "v2.0.0"
+	if key() == before {
+		t.Error("cache key must change with the build version")
+	}
+}
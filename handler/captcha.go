@@ -1,71 +1,91 @@
 package handler
 
 import (
-	"bytes"
-	"fmt"
+	"context"
+	"image"
+	"time"
+
 	"github.com/bitqiu/pix-gen/fonts"
 	"github.com/bitqiu/pix-gen/pkg/captcha"
+	"github.com/bitqiu/pix-gen/pkg/encoder"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
 	"github.com/gin-gonic/gin"
-	"github.com/spf13/cast"
-	"image/png"
-	"net/http"
 )
 
 // HandleCaptcha 处理验证码生成请求的处理程序
+// GET 从查询参数读取参数，POST 从 JSON 请求体读取参数，避免验证码内容出现在访问日志中
 func HandleCaptcha(c *gin.Context) {
+	// 每次生成的验证码都不同，禁止浏览器和代理缓存
+	c.Header("Cache-Control", "no-store")
 
-	// 获取并解析 width 和 height 参数，如果不存在则使用默认值
-	width := cast.ToInt(c.DefaultQuery("width", "120"))
-	height := cast.ToInt(c.DefaultQuery("height", "30"))
-	code := c.Query("code")
-
-	// 调用 captcha 包生成验证码
-	captchaImage, err := generateCaptcha(width, height, code)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	// 解析请求参数，未指定的参数使用默认值
+	req := NewCaptchaRequest()
+	if !bindRequest(c, req) {
 		return
 	}
 
-	// 返回验证码图像
-	c.Data(http.StatusOK, "image/png", captchaImage)
-
+	// 调用 captcha 包生成验证码
+	// 按请求的输出格式返回验证码图像
+	if writeImage(c, req.Render, req.OutputRequest) {
+		captchaIssued.Inc()
+	}
 }
 
-// generateCaptcha 生成验证码图片
-func generateCaptcha(width, height int, code string) ([]byte, error) {
+// Render 生成验证码图片
+func (r *CaptchaRequest) Render() (image.Image, error) {
 	// 初始化验证码生成器
 	cap := captcha.New()
 	// 设置干扰模式
 	cap.SetDisturbance(captcha.NORMAL)
 
-	// 读取字体文件
-	fontBytes, err := fonts.FontsFS.ReadFile("MiSans-Normal.ttf")
+	// 从字体注册表中获取字体
+	f, err := fonts.Get(r.Font)
 	if err != nil {
-		return nil, fmt.Errorf("invalid font file")
+		return nil, err
 	}
 
 	// 添加字体到验证码生成器
-	err = cap.AddFontFromBytes(fontBytes)
-	if err != nil {
-		return nil, fmt.Errorf("invalid font")
-	}
-
-	// 检查 width 和 height 的边界条件
-	if width <= 0 || height <= 0 {
-		return nil, fmt.Errorf("width and height must be positive integers")
-	}
+	cap.AddParsedFont(f.Font)
 
 	// 设置验证码图片的大小
-	cap.SetSize(width, height)
+	cap.SetSize(r.Width, r.Height)
 
 	// 生成新的验证码
-	img := cap.CreateCustom(code)
+	img, err := cap.CreateCustom(r.Code)
+	if err != nil {
+		return nil, err
+	}
+	return img, nil
+}
 
-	// 将图像编码为 PNG 并输出二进制图像数据
-	buffer := new(bytes.Buffer)
-	if err := png.Encode(buffer, img); err != nil {
-		return nil, fmt.Errorf("failed to encode image")
+// IssueCaptcha 生成随机的验证码图片并将答案保存到 store，返回验证码 ID、编码后的图片和编码参数
+// req.Code 为空时生成 length 个随机字符，答案在 ttl 后过期
+func IssueCaptcha(ctx context.Context, store captcha.Store, req *CaptchaRequest, length int, ttl time.Duration) (string, []byte, encoder.Options, error) {
+	if req.Code == "" {
+		req.Code = captcha.RandomCode(length)
+	}
+	data, opts, err := Encode(ctx, "captcha", req)
+	if err != nil {
+		return "", nil, opts, err
 	}
+	id := captcha.NewID()
+	if err := store.Set(ctx, id, req.Code, ttl); err != nil {
+		return "", nil, opts, errcode.Errorf(errcode.Internal, "save captcha: %w", err)
+	}
+	captchaIssued.Inc()
+	return id, data, opts, nil
+}
 
-	return buffer.Bytes(), nil
+// VerifyCaptcha 校验验证码，每个验证码只能校验一次
+func VerifyCaptcha(ctx context.Context, store captcha.Store, id, code string) (bool, error) {
+	ok, err := captcha.Verify(ctx, store, id, code)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		captchaVerified.Inc()
+	} else {
+		captchaFailed.Inc()
+	}
+	return ok, nil
 }
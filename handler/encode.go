@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"image"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bitqiu/pix-gen/pkg/encoder"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// Options 将输出参数转换为编码参数
+// 未指定 format 时根据 accept 协商，accept 为空时为 PNG
+func (o OutputRequest) Options(accept string) (encoder.Options, error) {
+	opts := encoder.Options{
+		Quality:     o.Quality,                          // JPEG 质量 1-100
+		Compression: encoder.Compression(o.Compression), // PNG 压缩级别
+	}
+	if o.Format != "" {
+		f, err := encoder.ParseFormat(o.Format)
+		if err != nil {
+			return opts, err
+		}
+		opts.Format = f
+	} else {
+		opts.Format = encoder.Negotiate(accept, encoder.PNG)
+	}
+	return opts, opts.Validate()
+}
+
+// outputOptions 将输出参数转换为编码参数
+// 未指定 format 参数时根据 Accept 请求头协商，默认为 PNG
+func outputOptions(c *gin.Context, out OutputRequest) (encoder.Options, error) {
+	if out.Format == "" {
+		varyAccept(c)
+	}
+	return out.Options(c.GetHeader("Accept"))
+}
+
+// varyAccept 在响应取决于 Accept 请求头时添加 Vary: Accept，多次调用只添加一次
+func varyAccept(c *gin.Context) {
+	for _, v := range c.Writer.Header().Values("Vary") {
+		if v == "Accept" {
+			return
+		}
+	}
+	c.Writer.Header().Add("Vary", "Accept")
+}
+
+// wantsJSON 判断是否以 JSON 返回 data URI
+// output=json 参数或 Accept 请求头首选 application/json 时返回 true
+func wantsJSON(c *gin.Context, out OutputRequest) bool {
+	if out.Output == "json" {
+		return true
+	}
+	varyAccept(c)
+	accept := strings.TrimSpace(c.GetHeader("Accept"))
+	return strings.HasPrefix(accept, "application/json")
+}
+
+// writeImage 在渲染限制下生成图片，按请求的输出格式编码并返回，失败时写入错误响应并返回 false
+func writeImage(c *gin.Context, render func() (image.Image, error), out OutputRequest) bool {
+	opts, err := outputOptions(c, out)
+	if err != nil {
+		writeError(c, err)
+		return false
+	}
+	contentType, data, ok := renderResponse(c, render, opts, wantsJSON(c, out))
+	if !ok {
+		return false
+	}
+	c.Data(http.StatusOK, contentType, data)
+	return true
+}
+
+// encodeResponse 编码图像并记录编码耗时和大小，asJSON 为 true 时返回包含 data URI 的 JSON
+func encodeResponse(generator string, img image.Image, opts encoder.Options, asJSON bool) (string, []byte, error) {
+	start := time.Now()
+	data, err := encoder.EncodeBytes(img, opts)
+	if err != nil {
+		return "", nil, err
+	}
+	observeEncode(generator, string(opts.Format), start, len(data))
+	if !asJSON {
+		return opts.Format.ContentType(), data, nil
+	}
+	body, err := json.Marshal(gin.H{
+		"format": opts.Format,
+		"width":  img.Bounds().Dx(),
+		"height": img.Bounds().Dy(),
+		"data":   encoder.DataURI(data, opts.Format),
+	})
+	return "application/json; charset=utf-8", body, err
+}
+
+// Renderer 是可以生成图片的请求参数，HTTP 批量生成和 gRPC 共用
+type Renderer interface {
+	Render() (image.Image, error)
+	Options(accept string) (encoder.Options, error)
+}
+
+// Encode 校验、渲染并编码请求，返回编码后的图片和编码参数，generator 是指标中的生成器名称
+// 渲染和编码受并发数和渲染时限限制，渲染中的 panic 转换为错误；成功时计入租户的配额
+func Encode(ctx context.Context, generator string, req Renderer) ([]byte, encoder.Options, error) {
+	if err := Validate(req); err != nil {
+		return nil, encoder.Options{}, err
+	}
+	opts, err := req.Options("")
+	if err != nil {
+		return nil, opts, err
+	}
+	// 编码与渲染占用同一个渲染槽位
+	var data []byte
+	err = LimitRender(ctx, func() error {
+		start := time.Now()
+		img, err := req.Render()
+		if err != nil {
+			return err
+		}
+		observeRender(generator, start)
+		start = time.Now()
+		if data, err = encoder.EncodeBytes(img, opts); err != nil {
+			return errcode.Errorf(errcode.Internal, "failed to encode image: %w", err)
+		}
+		observeEncode(generator, string(opts.Format), start, len(data))
+		return nil
+	})
+	if err != nil {
+		return nil, opts, err
+	}
+	ChargeQuota(ctx, 1)
+	return data, opts, nil
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+// TestVaryAccept 测试输出格式或 JSON 输出取决于 Accept 请求头时响应带有一次 Vary: Accept
+func TestVaryAccept(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Configure(config.Default())
+	r := gin.New()
+	r.GET("/barcode", HandleBarcode)
+	tests := []struct {
+		target string
+		accept string
+		vary   bool
+	}{
+		{"/barcode?text=12345678", "image/webp", true},
+		{"/barcode?text=12345678&format=png", "application/json", true},
+		{"/barcode?text=12345678&format=png&output=json", "application/json", false},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		req.Header.Set("Accept", tt.accept)
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: %d %s", tt.target, w.Code, w.Body.String())
+		}
+		n := 0
+		for _, v := range w.Header().Values("Vary") {
+			if v == "Accept" {
+				n++
+			}
+		}
+		if n > 1 || (n == 1) != tt.vary {
+			t.Errorf("%s (Accept: %s): Vary = %q", tt.target, tt.accept, w.Header().Values("Vary"))
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// statusCodes 是错误码对应的 HTTP 状态码，未列出的错误码返回 400
+var statusCodes = map[errcode.Code]int{
+	errcode.PayloadTooLong:   http.StatusRequestEntityTooLarge,
+	errcode.SizeTooLarge:     http.StatusUnprocessableEntity,
+	errcode.RenderTimeout:    http.StatusUnprocessableEntity,
+	errcode.TemplateNotFound: http.StatusNotFound,
+	errcode.NotFound:         http.StatusNotFound,
+	errcode.Busy:             http.StatusServiceUnavailable,
+	errcode.RateLimited:      http.StatusTooManyRequests,
+	errcode.MissingKey:       http.StatusUnauthorized,
+	errcode.InvalidKey:       http.StatusUnauthorized,
+	errcode.EndpointDenied:   http.StatusForbidden,
+	errcode.QuotaExceeded:    http.StatusTooManyRequests,
+	errcode.InvalidSignature: http.StatusForbidden,
+	errcode.Internal:         http.StatusInternalServerError,
+}
+
+// StatusCode 返回错误对应的 HTTP 状态码
+func StatusCode(err error) int {
+	if status, ok := statusCodes[errcode.Of(err)]; ok {
+		return status
+	}
+	return http.StatusBadRequest
+}
+
+// fieldsError 是列出各个字段原因的参数错误
+type fieldsError struct {
+	err    *errcode.Error
+	fields []FieldError
+}
+
+func (e *fieldsError) Error() string { return e.err.Error() }
+func (e *fieldsError) Unwrap() error { return e.err }
+
+// newFieldsError 返回字段错误，错误信息逐个列出字段和原因
+func newFieldsError(code errcode.Code, fields []FieldError) error {
+	reasons := make([]string, len(fields))
+	for i, f := range fields {
+		reasons[i] = f.Field + " " + f.Reason
+	}
+	return &fieldsError{errcode.New(code, strings.Join(reasons, "; ")), fields}
+}
+
+// writeError 按错误码返回状态码和 JSON 错误响应并中止请求，错误同时记录在访问日志中
+// error 为按请求的语言翻译的错误信息，detail 为原始的错误信息，字段错误的原因同样翻译
+// 响应格式为 {"code": "INVALID_COLOR", "error": "...", "detail": "...", "fields": [...]}
+func writeError(c *gin.Context, err error) {
+	// 请求体超出上限的错误可能被包装为参数错误，这里统一返回 413
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		err = errcode.Errorf(errcode.PayloadTooLong, "request body must be at most %d bytes", tooLarge.Limit)
+	}
+	lang := Lang(c.Request.Context())
+	code := errcode.Of(err)
+	msg := lang.Text(string(code))
+	body := gin.H{"code": code, "error": msg}
+	var fe *fieldsError
+	if errors.As(err, &fe) {
+		fields := make([]FieldError, len(fe.fields))
+		for i, f := range fe.fields {
+			fields[i] = f.localize(lang)
+		}
+		body["fields"] = fields
+	} else if detail := err.Error(); detail != msg {
+		body["detail"] = detail
+	}
+	switch code {
+	case errcode.RenderTimeout:
+		body["limit"] = time.Duration(settings.Limits.RenderTimeout).String()
+	case errcode.Busy:
+		c.Header("Retry-After", "1")
+	}
+	c.Error(err)
+	c.AbortWithStatusJSON(StatusCode(err), body)
+}
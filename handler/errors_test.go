@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// TestErrorEnvelope 测试错误响应包含稳定的错误码并使用对应的状态码
+func TestErrorEnvelope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		method, target, body string
+		status               int
+		code                 errcode.Code
+	}{
+		{http.MethodGet, "/qrcode?color=nope", "", http.StatusBadRequest, errcode.InvalidColor},
+		{http.MethodGet, "/qrcode?level=Z&color=nope", "", http.StatusBadRequest, errcode.InvalidRequest},
+		{http.MethodGet, "/qrcode?text=" + strings.Repeat("x", 3000), "", http.StatusRequestEntityTooLarge, errcode.PayloadTooLong},
+		{http.MethodGet, "/qrcode?size=100000", "", http.StatusUnprocessableEntity, errcode.SizeTooLarge},
+		{http.MethodPost, "/qrcode", "{", http.StatusBadRequest, errcode.InvalidRequest},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(tt.method, tt.target, strings.NewReader(tt.body))
+		if bindRequest(c, NewQRCodeRequest()) {
+			t.Errorf("%s %s: expected rejection", tt.method, tt.target)
+			continue
+		}
+		var resp struct {
+			Code  errcode.Code `json:"code"`
+			Error string       `json:"error"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if w.Code != tt.status || resp.Code != tt.code || resp.Error == "" {
+			t.Errorf("%s %s: got %d %s, want %d %s", tt.method, tt.target, w.Code, w.Body.String(), tt.status, tt.code)
+		}
+	}
+}
+
+// TestStatusCode 测试库中的错误码转换为 HTTP 状态码
+func TestStatusCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{errcode.New(errcode.FontMissing, "missing"), http.StatusBadRequest},
+		{ErrBusy, http.StatusServiceUnavailable},
+		{ErrQuotaExceeded, http.StatusTooManyRequests},
+		{errcode.Errorf(errcode.Internal, "encode: %w", ErrBusy), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		if got := StatusCode(tt.err); got != tt.want {
+			t.Errorf("StatusCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
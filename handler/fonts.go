@@ -0,0 +1,15 @@
+package handler
+
+import (
+	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+// HandleFonts 是列出可用字体的处理程序
+func HandleFonts(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"default": fonts.DefaultName,
+		"fonts":   fonts.List(),
+	})
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/gin-gonic/gin"
+)
+
+// readinessTimeout 是单次就绪检查的最长时间
+const readinessTimeout = 2 * time.Second
+
+// readinessCheck 是一项就绪检查
+type readinessCheck struct {
+	name  string
+	check func(ctx context.Context) error
+}
+
+var (
+	readinessMu     sync.Mutex
+	readinessChecks = []readinessCheck{{"fonts", checkFonts}}
+	draining        atomic.Bool
+)
+
+// RegisterReadinessCheck 注册一项就绪检查，例如验证码存储的连通性
+// 任一检查返回错误时 /readyz 返回 503
+func RegisterReadinessCheck(name string, check func(ctx context.Context) error) {
+	readinessMu.Lock()
+	defer readinessMu.Unlock()
+	readinessChecks = append(readinessChecks, readinessCheck{name, check})
+}
+
+// Drain 将服务标记为正在停止，之后 /readyz 返回 503，负载均衡不再转发新的请求
+func Drain() {
+	draining.Store(true)
+}
+
+// checkFonts 检查字体是否已解析
+func checkFonts(ctx context.Context) error {
+	if !fonts.Loaded() {
+		return errors.New("fonts not loaded")
+	}
+	return nil
+}
+
+// HandleLivez 是存活检查的处理程序，进程能处理请求即返回 200
+func HandleLivez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// HandleReadyz 是就绪检查的处理程序
+// 字体解析完成且所有注册的检查通过时返回 200，否则返回 503 和未通过的检查
+func HandleReadyz(c *gin.Context) {
+	if draining.Load() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "draining"})
+		return
+	}
+	readinessMu.Lock()
+	checks := append([]readinessCheck(nil), readinessChecks...)
+	readinessMu.Unlock()
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), readinessTimeout)
+	defer cancel()
+	status, results := http.StatusOK, gin.H{}
+	for _, rc := range checks {
+		if err := rc.check(ctx); err != nil {
+			status = http.StatusServiceUnavailable
+			results[rc.name] = err.Error()
+			continue
+		}
+		results[rc.name] = "ok"
+	}
+	if status != http.StatusOK {
+		c.JSON(status, gin.H{"status": "unavailable", "checks": results})
+		return
+	}
+	c.JSON(status, gin.H{"status": "ok", "checks": results})
+}
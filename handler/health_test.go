@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/gin-gonic/gin"
+)
+
+// TestHandleReadyz 测试就绪检查在字体解析前、依赖不可用和停止时返回 503
+func TestHandleReadyz(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/livez", HandleLivez)
+	r.GET("/readyz", HandleReadyz)
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+
+	storeErr := errors.New("captcha store unreachable")
+	steps := []struct {
+		name   string
+		before func()
+		status int
+		body   string
+	}{
+		{"fonts not loaded", func() {}, http.StatusServiceUnavailable, "fonts not loaded"},
+		{"fonts loaded", func() {
+			if err := fonts.Load(); err != nil {
+				t.Fatal(err)
+			}
+		}, http.StatusOK, `"fonts":"ok"`},
+		{"store down", func() {
+			RegisterReadinessCheck("captchaStore", func(ctx context.Context) error { return storeErr })
+		}, http.StatusServiceUnavailable, storeErr.Error()},
+		{"store up", func() { storeErr = nil }, http.StatusOK, `"captchaStore":"ok"`},
+		{"draining", Drain, http.StatusServiceUnavailable, "draining"},
+	}
+	for _, step := range steps {
+		step.before()
+		w := get("/readyz")
+		if w.Code != step.status || !strings.Contains(w.Body.String(), step.body) {
+			t.Errorf("%s: readyz = %d %s, want %d containing %s", step.name, w.Code, w.Body, step.status, step.body)
+		}
+		if w := get("/livez"); w.Code != http.StatusOK {
+			t.Errorf("%s: livez = %d, want 200", step.name, w.Code)
+		}
+	}
+}
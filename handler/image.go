@@ -1,116 +1,67 @@
 package handler
 
 import (
-	"bytes"
-	"fmt"
+	"image"
+
 	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/richtext"
+	"github.com/bitqiu/pix-gen/pkg/textimage"
 	"github.com/gin-gonic/gin"
-	"github.com/golang/freetype"
-	"github.com/golang/freetype/truetype"
-	"github.com/spf13/cast"
-	"golang.org/x/image/font"
-	"image"
-	"image/color"
-	"image/draw"
-	"image/png"
-	"math"
-	"net/http"
 )
 
-// HandleImage 是处理生成二维码请求的处理程序
+// HandleImage 是处理生成文字图片请求的处理程序
+// GET 从查询参数读取参数，POST 从 JSON 请求体读取参数
 func HandleImage(c *gin.Context) {
-	text := c.DefaultQuery("text", "null")                       // 获取二维码的内容，默认为 "null"
-	width := cast.ToInt(c.DefaultQuery("width", "500"))          // 获取图片宽度，默认为 800
-	height := cast.ToInt(c.DefaultQuery("height", "100"))        // 获取图片高度，默认为 600
-	tipText := c.DefaultQuery("tipText", "请通过图片和复制的地址核对一样后进行转账") // 获取自定义文字，默认为 "Custom Text"
-
-	// 调用 generateImage 函数生成图像
-	imageData, err := generateImage(text, tipText, width, height)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	// 解析请求参数，未指定的参数使用默认值
+	req := NewImageRequest()
+	if !bindRequest(c, req) {
 		return
 	}
 
-	// 返回生成的图像
-	c.Data(http.StatusOK, "image/png", imageData)
+	writeCached(c, req, req.Render, req.OutputRequest)
 }
 
-// generateImage 生成带有指定文字的图像
-func generateImage(text, tipText string, width, height int) ([]byte, error) {
-	// 读取字体数据
-	fontBytes, err := fonts.FontsFS.ReadFile("MiSans-Normal.ttf")
-	if err != nil {
-		return nil, fmt.Errorf("读取字体文件出错: %v", err)
+// Render 生成文字图片
+func (r *ImageRequest) Render() (image.Image, error) {
+	// 解析富文本，spans 优先于 markup，未指定时使用黑色主文字加红色提示文字
+	// 在获取字体之前解析，标记错误不受字体配置影响
+	var lines []richtext.Line
+	switch {
+	case r.Mode == "address":
+	case len(r.Spans.Lines) > 0:
+		lines = r.Spans.Lines
+	case r.Markup != "":
+		var err error
+		if lines, err = richtext.ParseMarkup(r.Markup); err != nil {
+			return nil, err
+		}
+	default:
+		lines = []richtext.Line{
+			{{Text: r.Text}},
+			{{Text: r.TipText, Color: "ff0000"}},
+		}
 	}
 
-	// 解析字体
-	parsedFont, err := freetype.ParseFont(fontBytes)
+	// 从字体注册表中获取字体
+	f, err := fonts.Get(r.Font)
 	if err != nil {
-		return nil, fmt.Errorf("解析字体出错: %v", err)
-	}
-
-	// 根据图像尺寸动态计算字体大小
-	area := float64(width * height)
-	fontSize := math.Sqrt(area / float64(100))
-
-	// 创建一个新的 RGBA 图像，背景为白色
-	img := image.NewRGBA(image.Rect(0, 0, width, height))
-	bgColor := color.RGBA{255, 255, 255, 255}
-	draw.Draw(img, img.Bounds(), &image.Uniform{bgColor}, image.Point{}, draw.Src)
-
-	// 初始化 freetype 上下文
-	ctx := freetype.NewContext()
-	ctx.SetDPI(72)
-	ctx.SetFont(parsedFont)
-	ctx.SetFontSize(fontSize)
-	ctx.SetClip(img.Bounds())
-	ctx.SetDst(img)
-	ctx.SetSrc(image.Black)
-
-	// 计算文字的尺寸
-	opts := &truetype.Options{
-		Size: fontSize,
-		DPI:  72,
+		return nil, errcode.Errorf(errcode.Of(err), "failed to load font: %w", err)
 	}
-	face := truetype.NewFace(parsedFont, opts)
-	textWidth, textHeight := getTextDimensions(face, text)
-	tipTextWidth, tipTextHeight := getTextDimensions(face, tipText)
-
-	// 计算文本块的总高度
-	totalTextHeight := textHeight + tipTextHeight + 10 // 两行文字间距 10 像素
-
-	// 计算起始点，使文本水平和垂直居中
-	x := (width - textWidth) / 2
-	tipX := (width - tipTextWidth) / 2
-	y := (height - totalTextHeight) / 2
-
-	// 绘制主文字
-	pt := freetype.Pt(x, y+int(ctx.PointToFixed(fontSize)>>6))
-	if _, err := ctx.DrawString(text, pt); err != nil {
-		return nil, fmt.Errorf("绘制文字出错: %v", err)
+	opts := textimage.Options{
+		Width:       r.Width,
+		Height:      r.Height,
+		Font:        f.Font,
+		TipText:     r.TipText,
+		Group:       r.Group,       // 每组字符数
+		Highlight:   r.Highlight,   // 首尾高亮字符数
+		Fingerprint: r.Fingerprint, // 是否绘制地址指纹图标
 	}
 
-	// 用红色绘制自定义提示文字
-	ctx.SetSrc(image.NewUniform(color.RGBA{255, 0, 0, 255}))
-	tipY := y + textHeight + 10
-	tipPt := freetype.Pt(tipX, tipY+int(ctx.PointToFixed(fontSize)>>6))
-	if _, err := ctx.DrawString(tipText, tipPt); err != nil {
-		return nil, fmt.Errorf("绘制自定义文字出错: %v", err)
+	// 地址核对模式：地址分组等宽排列，首尾字符高亮
+	if r.Mode == "address" {
+		return textimage.DrawAddress(r.Text, opts)
 	}
 
-	// 将图像编码为 PNG 格式
-	var buf bytes.Buffer
-	if err := png.Encode(&buf, img); err != nil {
-		return nil, fmt.Errorf("编码图像出错: %v", err)
-	}
-
-	return buf.Bytes(), nil
-}
-
-// getTextDimensions 计算给定文字的宽度和高度
-func getTextDimensions(face font.Face, text string) (int, int) {
-	bounds, _ := font.BoundString(face, text)
-	width := (bounds.Max.X - bounds.Min.X).Ceil()
-	height := (bounds.Max.Y - bounds.Min.Y).Ceil()
-	return width, height
+	return textimage.DrawLines(lines, opts)
 }
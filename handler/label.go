@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/label"
+	"github.com/bitqiu/pix-gen/pkg/qrcode"
+	"github.com/gin-gonic/gin"
+)
+
+// HandleLabels 是处理标签页生成请求的处理程序
+// 请求体为 JSON，返回可直接打印的 PDF
+func HandleLabels(c *gin.Context) {
+	req := NewLabelRequest()
+	if err := c.ShouldBindJSON(req); err != nil {
+		bindError(c, err)
+		return
+	}
+	if !withinLimits(c, req) {
+		return
+	}
+
+	// PDF 的排版、绘制和写入在一步中完成，整体计入渲染阶段
+	var buf bytes.Buffer
+	start := time.Now()
+	if err := LimitRender(c.Request.Context(), func() error { return req.Render(&buf) }); err != nil {
+		renderError(c, err)
+		return
+	}
+	observeRender("labels", start)
+	ChargeQuota(c.Request.Context(), 1)
+	outputBytes.WithLabelValues("labels", "pdf").Observe(float64(buf.Len()))
+
+	extendWriteDeadline(c)
+	c.Header("Content-Disposition", `inline; filename="labels.pdf"`)
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
+
+// Render 将标签页 PDF 写入 w
+func (r *LabelRequest) Render(w io.Writer) error {
+	var sheet label.Sheet
+	if r.Sheet != nil {
+		sheet = *r.Sheet
+	} else {
+		preset, ok := label.Presets[r.Preset]
+		if !ok {
+			return errcode.Errorf(errcode.InvalidRequest, "unknown label preset %q", r.Preset)
+		}
+		sheet = preset
+	}
+
+	f, err := fonts.Get(r.Font)
+	if err != nil {
+		return err
+	}
+
+	return label.Render(w, r.Items, label.Options{
+		Sheet:   sheet,
+		Font:    f.Data,
+		Level:   qrcode.Level(r.Level),
+		Padding: r.Padding,
+		Skip:    r.Skip,
+		Border:  r.Border,
+	})
+}
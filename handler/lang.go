@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// langKey 是请求上下文中保存语言的键
+type langKey struct{}
+
+// defaultTipText 是内置的文字图片提示文字，配置中没有修改时按请求的语言翻译
+var defaultTipText = config.Default().Defaults.Image.TipText
+
+// Lang 返回请求上下文中的语言，没有时返回 i18n.Default
+func Lang(ctx context.Context) i18n.Lang {
+	if lang, ok := ctx.Value(langKey{}).(i18n.Lang); ok {
+		return lang
+	}
+	return i18n.Default
+}
+
+// WithLang 返回带有语言的上下文
+func WithLang(ctx context.Context, lang i18n.Lang) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+// NegotiateLang 按 lang 参数或 Accept-Language 请求头选择语言，有效的 lang 参数优先
+func NegotiateLang(param, accept string) i18n.Lang {
+	if lang, ok := i18n.Parse(param); ok {
+		return lang
+	}
+	return i18n.Match(accept)
+}
+
+// Localize 返回选择语言的中间件，错误信息和默认文字按 lang 查询参数或 Accept-Language 请求头选择语言
+// 响应带有 Content-Language，只有图片内容随语言变化时才由 varyLang 添加 Vary: Accept-Language
+func Localize() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := NegotiateLang(c.Query("lang"), c.GetHeader("Accept-Language"))
+		c.Header("Content-Language", string(lang))
+		c.Request = c.Request.WithContext(WithLang(c.Request.Context(), lang))
+		c.Next()
+	}
+}
+
+// varyLang 在文字图片使用按 Accept-Language 翻译的默认提示文字时添加 Vary: Accept-Language
+// 其他生成接口的图片与语言无关，有效的 lang 参数已经在 URL 中区分
+func varyLang(c *gin.Context, req interface{}) {
+	r, ok := req.(*ImageRequest)
+	if !ok || r.TipText == "" || r.TipText != Lang(c.Request.Context()).Text("image.tipText") {
+		return
+	}
+	if _, ok := i18n.Parse(c.Query("lang")); !ok {
+		c.Writer.Header().Add("Vary", "Accept-Language")
+	}
+}
+
+// ApplyDefaults 用所选语言的默认文字和租户的默认参数覆盖全局默认值，请求中的参数仍然优先
+func ApplyDefaults(ctx context.Context, req interface{}) {
+	if r, ok := req.(*ImageRequest); ok && r.TipText == defaultTipText {
+		r.TipText = Lang(ctx).Text("image.tipText")
+	}
+	ApplyTenantDefaults(ctx, req)
+}
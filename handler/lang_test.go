@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// TestLocalizedErrors 测试错误信息和字段原因按 lang 参数或 Accept-Language 翻译
+func TestLocalizedErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Localize())
+	r.GET("/localized", func(c *gin.Context) { bindRequest(c, NewQRCodeRequest()) })
+
+	tests := []struct {
+		target, accept string
+		lang           i18n.Lang
+		error, reason  string
+	}{
+		{"/localized?size=0", "", i18n.ZhCN, "请求参数无效", "不能小于 1"},
+		{"/localized?size=0", "en-US,en;q=0.9", i18n.En, "invalid request", "must be at least 1"},
+		{"/localized?size=0&lang=zh", "en", i18n.ZhCN, "请求参数无效", "不能小于 1"},
+		{"/localized?size=100000&lang=en", "", i18n.En, "image size exceeds limits", "must be at most 4096"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		req.Header.Set("Accept-Language", tt.accept)
+		r.ServeHTTP(w, req)
+
+		var resp struct {
+			Error  string       `json:"error"`
+			Fields []FieldError `json:"fields"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if w.Header().Get("Content-Language") != string(tt.lang) || resp.Error != tt.error ||
+			len(resp.Fields) != 1 || resp.Fields[0].Reason != tt.reason {
+			t.Errorf("%s (%s): got %s %s", tt.target, tt.accept, w.Header().Get("Content-Language"), w.Body.String())
+		}
+	}
+}
+
+// TestLocalizedTipText 测试未修改的默认提示文字按语言翻译，请求中的提示文字不变
+func TestLocalizedTipText(t *testing.T) {
+	ctx := WithLang(context.Background(), i18n.En)
+	req := NewImageRequest()
+	ApplyDefaults(ctx, req)
+	if req.TipText != i18n.En.Text("image.tipText") {
+		t.Errorf("TipText = %q", req.TipText)
+	}
+
+	req = NewImageRequest()
+	ApplyDefaults(context.Background(), req)
+	if req.TipText != defaultTipText {
+		t.Errorf("default language: TipText = %q", req.TipText)
+	}
+
+	req = NewImageRequest()
+	req.TipText = "custom"
+	ApplyDefaults(ctx, req)
+	if req.TipText != "custom" {
+		t.Errorf("custom tip text replaced with %q", req.TipText)
+	}
+}
+
+// TestVaryLang 测试只有使用翻译后默认提示文字的文字图片带有 Vary: Accept-Language
+func TestVaryLang(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.Use(Localize())
+	r.GET("/text", func(c *gin.Context) { bindRequest(c, NewImageRequest()) })
+	r.GET("/code", func(c *gin.Context) { bindRequest(c, NewQRCodeRequest()) })
+
+	tests := []struct {
+		target string
+		vary   bool
+	}{
+		{"/text?text=hi", true},
+		{"/text?text=hi&tipText=custom", false},
+		{"/text?text=hi&lang=en", false},
+		{"/code?text=hi", false},
+		{"/code?size=0", false},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+		req.Header.Set("Accept-Language", "en")
+		r.ServeHTTP(w, req)
+		if got := w.Header().Get("Vary") == "Accept-Language"; got != tt.vary {
+			t.Errorf("%s: Vary = %q", tt.target, w.Header().Get("Vary"))
+		}
+	}
+}
@@ -0,0 +1,333 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bitqiu/pix-gen/pkg/avatar"
+	"github.com/bitqiu/pix-gen/pkg/cache"
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/encoder"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/richtext"
+	"github.com/bitqiu/pix-gen/pkg/textimage"
+	"github.com/gin-gonic/gin"
+)
+
+// settings 是当前生效的配置，提供各接口的默认参数和上限
+var settings = config.Default()
+
+// renderSlots 是全局渲染信号量，限制同时渲染的请求数
+var renderSlots = make(chan struct{}, settings.Limits.MaxConcurrency)
+
+// Configure 使用加载的配置替换默认参数、上限和缓存，需要在处理请求之前调用
+func Configure(cfg *config.Config) {
+	settings = cfg
+	renderSlots = make(chan struct{}, cfg.Limits.MaxConcurrency)
+	responseCache = cache.New(cfg.Cache.MaxBytes)
+	withImageLimits(renderer, cfg.Limits)
+}
+
+var (
+	// ErrBusy 表示在渲染时限内没有等到空闲的渲染槽位
+	ErrBusy = errcode.New(errcode.Busy, "server busy")
+	// ErrRenderTimeout 表示渲染超出时限
+	ErrRenderTimeout = errcode.New(errcode.RenderTimeout, "render time limit exceeded")
+)
+
+// LimitBody 返回限制请求体大小的中间件，请求体超过 limits.maxBodyBytes 时返回 413
+// Content-Length 已超出时直接拒绝，否则在读取请求体时截断，绑定参数和校验签名都不会读入超出的部分
+func LimitBody() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		max := int64(settings.Limits.MaxBodyBytes)
+		if c.Request.ContentLength > max {
+			writeError(c, &http.MaxBytesError{Limit: max})
+			return
+		}
+		if c.Request.Body != nil {
+			c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, max)
+		}
+		c.Next()
+	}
+}
+
+// extendWriteDeadline 将响应的写超时延长到从现在起的 server.writeTimeout
+// 批量生成和标签页的响应可能超过整个请求的写超时，在写入前延长，只限制每次写入的时间
+func extendWriteDeadline(c *gin.Context) {
+	if d := time.Duration(settings.Server.WriteTimeout); d > 0 {
+		// 测试用的 ResponseWriter 不支持设置超时，忽略错误
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Now().Add(d))
+	}
+}
+
+// deadlineWriter 在每次写入前延长写超时
+type deadlineWriter struct {
+	c *gin.Context
+}
+
+func (w deadlineWriter) Write(p []byte) (int, error) {
+	extendWriteDeadline(w.c)
+	return w.c.Writer.Write(p)
+}
+
+// limiter 是有上限的请求参数
+type limiter interface {
+	limits(l *limitCheck)
+}
+
+// limitCheck 收集超出上限的字段
+// 文字长度超限返回 413，尺寸超限返回 422
+type limitCheck struct {
+	status int
+	fields []FieldError
+}
+
+// fail 记录一个超限字段，key 为原因的消息名，413 优先于 422
+func (l *limitCheck) fail(status int, field, key string, args ...interface{}) {
+	if l.status != http.StatusRequestEntityTooLarge {
+		l.status = status
+	}
+	l.fields = append(l.fields, newFieldError(field, key, args...))
+}
+
+// size 检查宽高和像素数
+func (l *limitCheck) size(widthField string, width int, heightField string, height int) {
+	limits := settings.Limits
+	n := len(l.fields)
+	if width > limits.MaxWidth {
+		l.fail(http.StatusUnprocessableEntity, widthField, "reason.maxSize", limits.MaxWidth)
+	}
+	if height > limits.MaxHeight && heightField != widthField {
+		l.fail(http.StatusUnprocessableEntity, heightField, "reason.maxSize", limits.MaxHeight)
+	}
+	// 宽高已超限时不再重复报告像素数
+	if len(l.fields) == n && width > 0 && height > limits.MaxArea/width {
+		l.fail(http.StatusUnprocessableEntity, widthField, "reason.maxArea", width, height, limits.MaxArea)
+	}
+}
+
+// text 检查文字参数的字符数
+func (l *limitCheck) text(field, s string) {
+	if max := settings.Limits.MaxTextLength; utf8.RuneCountInString(s) > max {
+		l.fail(http.StatusRequestEntityTooLarge, field, "reason.maxChars", max)
+	}
+}
+
+// payload 检查二维码内容的字节数
+func (l *limitCheck) payload(field, s string) {
+	if max := settings.Limits.MaxQRCodeLength; len(s) > max {
+		l.fail(http.StatusRequestEntityTooLarge, field, "reason.maxBytes", max)
+	}
+}
+
+// fontSize 检查文字的像素字号，字体的字形缓存按字号的平方分配内存
+func (l *limitCheck) fontSize(field string, size float64) {
+	if max := settings.Limits.MaxFontSize; size > float64(max) {
+		l.fail(http.StatusUnprocessableEntity, field, "reason.maxFontSize", size, max)
+	}
+}
+
+// err 返回超限字段的错误，没有超限时返回 nil
+func (l *limitCheck) err() error {
+	if len(l.fields) == 0 {
+		return nil
+	}
+	return newFieldsError(l.code(), l.fields)
+}
+
+// code 返回超限的错误码，文字过长为 PAYLOAD_TOO_LONG，尺寸超限为 SIZE_TOO_LARGE
+func (l *limitCheck) code() errcode.Code {
+	if l.status == http.StatusRequestEntityTooLarge {
+		return errcode.PayloadTooLong
+	}
+	return errcode.SizeTooLarge
+}
+
+// reject 返回 413 或 422 和超限的字段，并计入被拒绝的请求
+func (l *limitCheck) reject(c *gin.Context) {
+	rejectedRequests.WithLabelValues(c.FullPath(), rejectReason(l.status)).Inc()
+	writeError(c, &fieldsError{errcode.New(l.code(), "request exceeds limits"), l.fields})
+}
+
+// checkLimits 检查请求参数是否超出配置的上限
+func checkLimits(req interface{}) *limitCheck {
+	l := &limitCheck{}
+	if r, ok := req.(limiter); ok {
+		r.limits(l)
+	}
+	return l
+}
+
+// withinLimits 检查请求参数，超出上限时返回 413 或 422 和超限的字段
+func withinLimits(c *gin.Context, req interface{}) bool {
+	l := checkLimits(req)
+	if len(l.fields) == 0 {
+		return true
+	}
+	l.reject(c)
+	return false
+}
+
+func (r *CaptchaRequest) limits(l *limitCheck) {
+	l.size("width", r.Width, "height", r.Height)
+	l.text("code", r.Code)
+}
+
+func (r *QRCodeRequest) limits(l *limitCheck) {
+	l.size("size", r.Size, "size", r.Size)
+	l.payload("text", r.Text)
+}
+
+func (r *BarcodeRequest) limits(l *limitCheck) {
+	l.size("width", r.Width, "height", r.Height)
+	l.text("text", r.Text)
+}
+
+func (r *ImageRequest) limits(l *limitCheck) {
+	l.size("width", r.Width, "height", r.Height)
+	l.text("text", r.Text)
+	l.text("tipText", r.TipText)
+	l.text("markup", r.Markup)
+	var spans string
+	for _, line := range r.Spans.Lines {
+		for _, span := range line {
+			spans += span.Text
+		}
+	}
+	l.text("spans", spans)
+
+	// 尺寸超限时不再检查按尺寸计算的字号
+	if len(l.fields) > 0 {
+		return
+	}
+	base := textimage.FontSize(r.Width, r.Height)
+	if r.Mode == "address" {
+		l.fontSize("width", base)
+		return
+	}
+	// 与 Render 相同，spans 优先于 markup，标记的语法错误由 Render 报告
+	field, lines := "spans", r.Spans.Lines
+	if len(lines) == 0 && r.Markup != "" {
+		field = "markup"
+		lines, _ = richtext.ParseMarkup(r.Markup)
+	}
+	if len(lines) == 0 {
+		field = "width"
+	}
+	scale := 1.0
+	for _, line := range lines {
+		for _, span := range line {
+			scale = math.Max(scale, span.Size)
+		}
+	}
+	l.fontSize(field, base*scale)
+}
+
+func (r *AvatarRequest) limits(l *limitCheck) {
+	l.size("size", r.Size, "size", r.Size)
+	l.text("seed", r.Seed)
+	l.text("name", r.Name)
+	if r.Type == "initials" {
+		l.fontSize("size", avatar.InitialsFontSize(r.Size))
+	}
+}
+
+func (r *PlaceholderRequest) limits(l *limitCheck) {
+	n := len(l.fields)
+	l.size("w", r.Width, "h", r.Height)
+	l.text("text", r.Text)
+	l.fontSize("fontSize", r.FontSize)
+	// 字号大于短边时文字放不进图片
+	if side := min(r.Width, r.Height); len(l.fields) == n && r.FontSize > float64(side) {
+		l.fail(http.StatusUnprocessableEntity, "fontSize", "reason.maxSize", side)
+	}
+}
+
+func (r *LabelRequest) limits(l *limitCheck) {
+	for i, item := range r.Items {
+		if item.Type == "barcode" {
+			l.text(fmt.Sprintf("items[%d].text", i), item.Text)
+		} else {
+			l.payload(fmt.Sprintf("items[%d].text", i), item.Text)
+		}
+		l.text(fmt.Sprintf("items[%d].caption", i), item.Caption)
+	}
+}
+
+// LimitRender 占用一个渲染槽位执行 fn，等待和执行的总时间不超过配置的渲染时限
+// 超时后 fn 仍在后台执行完毕才释放槽位，因此同时占用内存的渲染数不会超过槽位数
+func LimitRender(ctx context.Context, fn func() error) error {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(settings.Limits.RenderTimeout))
+	defer cancel()
+
+	slots := renderSlots
+	select {
+	case slots <- struct{}{}:
+	case <-ctx.Done():
+		return ErrBusy
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		defer func() { <-slots }()
+		defer func() {
+			if r := recover(); r != nil {
+				done <- errcode.Errorf(errcode.Internal, "render failed: %v", r)
+			}
+		}()
+		done <- fn()
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ErrRenderTimeout
+	}
+}
+
+// renderResponse 在渲染限制下生成并编码图片，记录渲染耗时并计入租户的配额，失败时写入错误响应并返回 false
+// 编码与渲染占用同一个渲染槽位，完整的图像只在槽位内存活
+func renderResponse(c *gin.Context, render func() (image.Image, error), opts encoder.Options, asJSON bool) (string, []byte, bool) {
+	generator := endpointName(c.FullPath())
+	var contentType string
+	var data []byte
+	var rendered time.Duration
+	err := LimitRender(c.Request.Context(), func() error {
+		start := time.Now()
+		img, err := render()
+		if err != nil {
+			return err
+		}
+		observeRender(generator, start)
+		rendered = time.Since(start)
+		if contentType, data, err = encodeResponse(generator, img, opts, asJSON); err != nil {
+			return errcode.Errorf(errcode.Internal, "failed to encode image: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		renderError(c, err)
+		return "", nil, false
+	}
+	c.Set(renderDurationKey, rendered)
+	ChargeQuota(c.Request.Context(), 1)
+	return contentType, data, true
+}
+
+// renderError 写入渲染失败的错误响应，资源限制导致的失败计入被拒绝的请求
+func renderError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, ErrBusy):
+		rejectedRequests.WithLabelValues(c.FullPath(), "busy").Inc()
+	case errors.Is(err, ErrRenderTimeout):
+		rejectedRequests.WithLabelValues(c.FullPath(), "render_timeout").Inc()
+	}
+	writeError(c, err)
+}
@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+// TestBindRequestLimits 测试尺寸超限返回 422，文字超限返回 413
+func TestBindRequestLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tests := []struct {
+		target string
+		status int
+		field  string
+	}{
+		{"/captcha?width=100000&height=100000", http.StatusUnprocessableEntity, "width"},
+		{"/captcha?width=4096&height=4096", http.StatusOK, ""},
+		{"/captcha?width=4096&height=4097", http.StatusUnprocessableEntity, "height"},
+		{"/captcha?code=" + strings.Repeat("x", 1001), http.StatusRequestEntityTooLarge, "code"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+		ok := bindRequest(c, NewCaptchaRequest())
+		if tt.status == http.StatusOK {
+			if !ok {
+				t.Errorf("%s: unexpected rejection %s", tt.target, w.Body.String())
+			}
+			continue
+		}
+		if ok || w.Code != tt.status || !strings.Contains(w.Body.String(), `"field":"`+tt.field+`"`) {
+			t.Errorf("%s: got %d %s, want %d for field %s", tt.target, w.Code, w.Body.String(), tt.status, tt.field)
+		}
+	}
+
+	// 二维码内容按字节数限制
+	req := NewQRCodeRequest()
+	req.Text = strings.Repeat("码", 1000)
+	if err := Validate(req); err == nil || !strings.Contains(err.Error(), "text must be at most 2953 bytes") {
+		t.Errorf("Validate() = %v, want a text length error", err)
+	}
+}
+
+// TestImageFontSizeLimits 测试按图片尺寸和富文本字号倍数计算的字号超过 limits.maxFontSize 时返回 422
+func TestImageFontSizeLimits(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Configure(config.Default())
+	tests := []struct {
+		target string
+		field  string
+	}{
+		{"/image?width=1000&height=1000&markup=%3Csize%3D4%3Ea%3C%2Fsize%3E", ""},
+		{"/image?width=2000&height=2000&markup=%3Csize%3D4%3Ea%3C%2Fsize%3E", "markup"},
+		{"/image?width=4096&height=4096&markup=%3Csize%3D2%3Ea%3C%2Fsize%3E", "markup"},
+		{"/image?width=4096&height=4096&spans=%5B%7B%22text%22%3A%22a%22%2C%22size%22%3A2%7D%5D", "spans"},
+		{"/image?width=4096&height=4096&text=a", ""},
+		{"/image?width=4096&height=4096&mode=address&text=a", ""},
+		{"/avatar?seed=a&size=4096&type=initials", "size"},
+		{"/avatar?seed=a&size=4096", ""},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodGet, tt.target, nil)
+		var req interface{} = NewImageRequest()
+		if strings.HasPrefix(tt.target, "/avatar") {
+			req = NewAvatarRequest()
+		}
+		ok := bindRequest(c, req)
+		if tt.field == "" {
+			if !ok {
+				t.Errorf("%s: unexpected rejection %s", tt.target, w.Body.String())
+			}
+			continue
+		}
+		if ok || w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"SIZE_TOO_LARGE"`) ||
+			!strings.Contains(w.Body.String(), `"field":"`+tt.field+`"`) {
+			t.Errorf("%s: got %d %s, want 422 for field %s", tt.target, w.Code, w.Body.String(), tt.field)
+		}
+	}
+}
+
+// TestLimitRender 测试渲染超时和渲染槽位用尽
+func TestLimitRender(t *testing.T) {
+	cfg := config.Default()
+	cfg.Limits.MaxConcurrency = 1
+	cfg.Limits.RenderTimeout = config.Duration(50 * time.Millisecond)
+	Configure(cfg)
+	defer Configure(config.Default())
+
+	release := make(chan struct{})
+	slow := func() error {
+		<-release
+		return nil
+	}
+	if err := LimitRender(context.Background(), slow); !errors.Is(err, ErrRenderTimeout) {
+		t.Errorf("slow render: got %v, want %v", err, ErrRenderTimeout)
+	}
+	// 超时的渲染仍占用唯一的槽位
+	if err := LimitRender(context.Background(), func() error { return nil }); !errors.Is(err, ErrBusy) {
+		t.Errorf("second render: got %v, want %v", err, ErrBusy)
+	}
+	close(release)
+	time.Sleep(10 * time.Millisecond)
+	if err := LimitRender(context.Background(), func() error { panic("boom") }); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("panicking render: got %v", err)
+	}
+}
+
+// TestLimitBody 测试请求体超过上限时返回 413，包括没有 Content-Length 的请求
+func TestLimitBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default()
+	cfg.Limits.MaxBodyBytes = 64
+	Configure(cfg)
+	defer Configure(config.Default())
+
+	r := gin.New()
+	r.Use(LimitBody())
+	r.POST("/body", func(c *gin.Context) {
+		if bindRequest(c, NewQRCodeRequest()) {
+			c.Status(http.StatusOK)
+		}
+	})
+	large := `{"text":"` + strings.Repeat("x", 100) + `"}`
+	tests := []struct {
+		body          string
+		contentLength int64
+		status        int
+	}{
+		{`{"text":"ok"}`, -1, http.StatusOK},
+		{large, int64(len(large)), http.StatusRequestEntityTooLarge},
+		{large, -1, http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/body", strings.NewReader(tt.body))
+		req.ContentLength = tt.contentLength
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status || (tt.status != http.StatusOK && !strings.Contains(w.Body.String(), `"PAYLOAD_TOO_LONG"`)) {
+			t.Errorf("%d bytes (Content-Length %d): got %d %s", len(tt.body), tt.contentLength, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"time"
+	"unicode/utf8"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/gin-gonic/gin"
+)
+
+// 上下文中保存日志信息的键
+const (
+	paramsKey         = "pix-gen/params"
+	renderDurationKey = "pix-gen/render-duration"
+)
+
+// requestIDKey 是请求上下文中保存请求 ID 的键
+type requestIDKey struct{}
+
+// validRequestID 是可以沿用的上游请求 ID
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// RequestID 返回请求上下文中的请求 ID
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// NewRequestID 沿用有效的上游请求 ID，id 为空或无效时生成随机的请求 ID
+func NewRequestID(id string) string {
+	if validRequestID.MatchString(id) {
+		return id
+	}
+	b := make([]byte, 12)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// WithRequestID 返回带有请求 ID 的上下文
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// Logger 返回记录结构化访问日志的中间件
+// 沿用上游传入的 X-Request-ID，没有时生成新的请求 ID，并在响应中返回 X-Request-ID
+// 日志包含路由、参数、渲染耗时、缓存命中和响应大小，验证码、地址类文字和 API key 会被脱敏
+func Logger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := NewRequestID(c.GetHeader("X-Request-ID"))
+		c.Header("X-Request-ID", id)
+		c.Request = c.Request.WithContext(WithRequestID(c.Request.Context(), id))
+
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []slog.Attr{
+			slog.String("request_id", id),
+			slog.String("method", c.Request.Method),
+			slog.String("route", c.FullPath()),
+			slog.String("path", c.Request.URL.Path),
+			slog.Int("status", status),
+			slog.Float64("duration_ms", msSince(start)),
+			slog.Int("bytes", max(c.Writer.Size(), 0)),
+			slog.String("client_ip", c.ClientIP()),
+		}
+		if t := tenantOf(c.Request.Context()); t != nil {
+			attrs = append(attrs, slog.String("tenant", t.Name))
+		}
+		if params := logParams(c); len(params) > 0 {
+			attrs = append(attrs, slog.Any("params", params))
+		}
+		if d, ok := c.Get(renderDurationKey); ok {
+			attrs = append(attrs, slog.Float64("render_ms", float64(d.(time.Duration).Microseconds())/1000))
+		}
+		if hit := c.Writer.Header().Get("X-Cache"); hit != "" {
+			attrs = append(attrs, slog.String("cache", hit))
+		}
+		if errs := c.Errors.String(); errs != "" {
+			attrs = append(attrs, slog.String("error", errs))
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		}
+		logger.LogAttrs(c.Request.Context(), level, "request", attrs...)
+	}
+}
+
+// Recovery 返回恢复 panic 的中间件，记录错误日志并返回 500
+func Recovery(logger *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, err any) {
+		logger.ErrorContext(c.Request.Context(), "panic", slog.String("request_id", RequestID(c.Request.Context())), slog.Any("error", err))
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"code": errcode.Internal, "error": Lang(c.Request.Context()).Text(string(errcode.Internal))})
+	})
+}
+
+// msSince 返回从 start 到现在的毫秒数
+func msSince(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
+
+// logParams 返回脱敏后的请求参数
+// 绑定过请求结构体时使用绑定后的参数，GET 和 POST 请求的格式相同；否则使用查询参数
+func logParams(c *gin.Context) map[string]any {
+	params := map[string]any{}
+	if req, ok := c.Get(paramsKey); ok {
+		data, err := json.Marshal(req)
+		if err != nil || json.Unmarshal(data, &params) != nil {
+			return nil
+		}
+	} else {
+		for name, values := range c.Request.URL.Query() {
+			if len(values) > 0 {
+				params[name] = values[0]
+			}
+		}
+	}
+	for name, v := range params {
+		params[name] = redact(name, v)
+	}
+	return params
+}
+
+// redact 脱敏单个参数
+// 验证码和 API key 完全隐藏；text 和 markup 可能是收款地址或链接，只保留首尾各 4 个字符便于排查
+func redact(name string, v any) any {
+	if v == nil {
+		return nil
+	}
+	switch name {
+	case "code", "key":
+		return "[redacted]"
+	case "spans":
+		return "[redacted]"
+	case "text", "markup":
+		s, ok := v.(string)
+		if !ok {
+			return "[redacted]"
+		}
+		if n := utf8.RuneCountInString(s); n > 12 {
+			r := []rune(s)
+			return string(r[:4]) + "…" + string(r[n-4:])
+		}
+		return "[redacted]"
+	}
+	return v
+}
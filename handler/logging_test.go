@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+// TestLogger 测试访问日志的请求 ID、参数脱敏、渲染耗时和响应大小
+func TestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Configure(config.Default())
+	var buf bytes.Buffer
+	r := gin.New()
+	r.Use(Logger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	r.GET("/qrcode", HandleQrcode)
+
+	address := "TQn9Y2khEsLJW1ChVWFMSMeRDow5KcbLSE"
+	tests := []struct {
+		requestID string
+		keepID    bool
+	}{
+		{"support-trace-1", true},
+		{"bad id\n", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodGet, "/qrcode?size=120&key=0123456789abcdef&text="+address, nil)
+		if tt.requestID != "" {
+			req.Header.Set("X-Request-ID", tt.requestID)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		id := w.Header().Get("X-Request-ID")
+		if tt.keepID != (id == tt.requestID) || id == "" {
+			t.Errorf("X-Request-ID for %q = %q", tt.requestID, id)
+		}
+		if strings.Contains(buf.String(), address) || strings.Contains(buf.String(), "0123456789abcdef") {
+			t.Errorf("log contains sensitive values: %s", buf.String())
+		}
+		var entry struct {
+			RequestID string         `json:"request_id"`
+			Route     string         `json:"route"`
+			Status    int            `json:"status"`
+			Bytes     int            `json:"bytes"`
+			RenderMS  *float64       `json:"render_ms"`
+			Cache     string         `json:"cache"`
+			Params    map[string]any `json:"params"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+			t.Fatalf("decode log %q: %v", buf.String(), err)
+		}
+		if entry.RequestID != id || entry.Route != "/qrcode" || entry.Status != http.StatusOK {
+			t.Errorf("log entry = %+v", entry)
+		}
+		// 只有第一次请求渲染图片，之后命中缓存
+		if entry.Bytes != w.Body.Len() || (entry.RenderMS != nil) != (entry.Cache == "MISS") {
+			t.Errorf("bytes = %d, render_ms = %v, cache = %s, want %d bytes", entry.Bytes, entry.RenderMS, entry.Cache, w.Body.Len())
+		}
+		if entry.Params["text"] != "TQn9…bLSE" || entry.Params["size"] != float64(120) {
+			t.Errorf("params = %v", entry.Params)
+		}
+	}
+}
+
+// TestRedact 测试参数脱敏
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want any
+	}{
+		{"code", "a8Kx", "[redacted]"},
+		{"key", "0123456789abcdef", "[redacted]"},
+		{"text", "short", "[redacted]"},
+		{"text", "0x52908400098527886E0F7030069857D2E4169EE7", "0x52…9EE7"},
+		{"markup", "<b>0x52908400098527886E0F</b>", "<b>0…</b>"},
+		{"tipText", "核对地址", "核对地址"},
+		{"size", float64(300), float64(300)},
+	}
+	for _, tt := range tests {
+		if got := redact(tt.name, tt.in); got != tt.want {
+			t.Errorf("redact(%q, %v) = %v, want %v", tt.name, tt.in, got, tt.want)
+		}
+	}
+}
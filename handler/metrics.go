@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsRegistry 是 /metrics 输出的指标
+var metricsRegistry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pix_http_requests_total",
+		Help: "HTTP requests by route, method and status.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pix_http_request_duration_seconds",
+		Help:    "HTTP request latency by route, method and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	renderDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pix_render_duration_seconds",
+		Help:    "Render time by generator and phase (render, encode).",
+		Buckets: prometheus.ExponentialBuckets(0.0005, 2, 14),
+	}, []string{"generator", "phase"})
+
+	outputBytes = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "pix_output_bytes",
+		Help:    "Size of encoded images by generator and format.",
+		Buckets: prometheus.ExponentialBuckets(256, 4, 9),
+	}, []string{"generator", "format"})
+
+	captchaIssued = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pix_captcha_issued_total",
+		Help: "Captcha images issued.",
+	})
+
+	captchaVerified = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pix_captcha_verified_total",
+		Help: "Captcha answers verified successfully.",
+	})
+
+	captchaFailed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "pix_captcha_verify_failed_total",
+		Help: "Captcha answers that failed verification.",
+	})
+
+	rejectedRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pix_rejected_requests_total",
+		Help: "Requests rejected by resource limits, by route and reason (size, length, render_timeout, busy).",
+	}, []string{"route", "reason"})
+)
+
+func init() {
+	metricsRegistry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration, renderDuration, outputBytes,
+		captchaIssued, captchaVerified, captchaFailed, rejectedRequests,
+		// 缓存统计在采集时读取，Configure 替换缓存后读取新的缓存
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "pix_cache_hits_total",
+			Help: "Response cache hits.",
+		}, func() float64 { return float64(responseCache.Stats().Hits) }),
+		prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Name: "pix_cache_misses_total",
+			Help: "Response cache misses.",
+		}, func() float64 { return float64(responseCache.Stats().Misses) }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "pix_cache_bytes",
+			Help: "Bytes held by the response cache.",
+		}, func() float64 { return float64(responseCache.Stats().Bytes) }),
+	)
+}
+
+// Metrics 返回统计请求数和延迟的中间件，未匹配路由的请求记为 unmatched
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		status := strconv.Itoa(c.Writer.Status())
+		httpRequests.WithLabelValues(route, c.Request.Method, status).Inc()
+		httpDuration.WithLabelValues(route, c.Request.Method, status).Observe(time.Since(start).Seconds())
+	}
+}
+
+// HandleMetrics 以 Prometheus 文本格式输出指标
+var HandleMetrics = gin.WrapH(promhttp.HandlerFor(metricsRegistry, promhttp.HandlerOpts{}))
+
+// observeRender 记录渲染阶段的耗时，排版和绘制在各生成器内部交错进行，整体计入一个阶段
+func observeRender(generator string, start time.Time) {
+	renderDuration.WithLabelValues(generator, "render").Observe(time.Since(start).Seconds())
+}
+
+// observeEncode 记录编码阶段的耗时和输出大小
+func observeEncode(generator, format string, start time.Time, size int) {
+	renderDuration.WithLabelValues(generator, "encode").Observe(time.Since(start).Seconds())
+	outputBytes.WithLabelValues(generator, format).Observe(float64(size))
+}
+
+// rejectReason 返回超限响应的原因标签
+func rejectReason(status int) string {
+	if status == http.StatusRequestEntityTooLarge {
+		return "length"
+	}
+	return "size"
+}
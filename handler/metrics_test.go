@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+// TestMetrics 测试请求、渲染阶段、输出大小和超限拒绝的指标
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Configure(config.Default())
+	r := gin.New()
+	r.Use(Metrics())
+	r.GET("/qrcode", HandleQrcode)
+	r.GET("/metrics", HandleMetrics)
+
+	for _, target := range []string{"/qrcode?text=metrics", "/qrcode?text=metrics&size=99999"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body := w.Body.String()
+	for _, want := range []string{
+		`pix_http_requests_total{method="GET",route="/qrcode",status="200"} 1`,
+		`pix_http_requests_total{method="GET",route="/qrcode",status="422"} 1`,
+		`pix_render_duration_seconds_count{generator="qrcode",phase="render"}`,
+		`pix_render_duration_seconds_count{generator="qrcode",phase="encode"}`,
+		`pix_output_bytes_count{format="png",generator="qrcode"}`,
+		`pix_rejected_requests_total{reason="size",route="/qrcode"} 1`,
+		`pix_cache_misses_total`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics do not contain %s", want)
+		}
+	}
+}
@@ -0,0 +1,416 @@
+package handler
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/i18n"
+	"github.com/bitqiu/pix-gen/pkg/richtext"
+	"github.com/gin-gonic/gin"
+)
+
+// apiEndpoint 描述一个生成接口，OpenAPI 文档的参数由请求结构体的标签生成
+// form 和 uri 标签是查询参数和路径参数，json 标签是请求体字段，binding 标签是约束，doc 标签是说明
+// 构造函数返回的默认值即文档中的默认值
+type apiEndpoint struct {
+	path       string
+	summary    string
+	newRequest func() interface{}
+	enabled    func(f config.Features) bool
+	get        bool   // 是否支持 GET 查询参数
+	produces   string // 响应类型，为空时为图片
+	cached     bool   // 是否支持 ETag 缓存
+}
+
+// apiEndpoints 是所有生成接口
+var apiEndpoints = []apiEndpoint{
+	{path: "/captcha", summary: "生成验证码图片", get: true,
+		newRequest: func() interface{} { return NewCaptchaRequest() },
+		enabled:    func(f config.Features) bool { return f.Captcha }},
+	{path: "/qrcode", summary: "生成二维码", get: true, cached: true,
+		newRequest: func() interface{} { return NewQRCodeRequest() },
+		enabled:    func(f config.Features) bool { return f.QRCode }},
+	{path: "/barcode", summary: "生成 Code 128 条码", get: true, cached: true,
+		newRequest: func() interface{} { return NewBarcodeRequest() },
+		enabled:    func(f config.Features) bool { return f.Barcode }},
+	{path: "/image", summary: "生成文字图片", get: true, cached: true,
+		newRequest: func() interface{} { return NewImageRequest() },
+		enabled:    func(f config.Features) bool { return f.Image }},
+	{path: "/avatar", summary: "生成头像", get: true, cached: true,
+		newRequest: func() interface{} { return NewAvatarRequest() },
+		enabled:    func(f config.Features) bool { return f.Avatar }},
+	{path: "/placeholder/{w}/{h}", summary: "生成占位图", get: true, cached: true,
+		newRequest: func() interface{} { return NewPlaceholderRequest() },
+		enabled:    func(f config.Features) bool { return f.Placeholder }},
+	{path: "/batch", summary: "批量生成图片并打包为 ZIP", produces: "application/zip",
+		newRequest: func() interface{} { return &BatchRequest{} },
+		enabled:    func(f config.Features) bool { return f.Batch }},
+	{path: "/labels", summary: "生成标签页 PDF", produces: "application/pdf",
+		newRequest: func() interface{} { return NewLabelRequest() },
+		enabled:    func(f config.Features) bool { return f.Labels }},
+}
+
+// imageTypes 是生成接口可能返回的图片类型
+var imageTypes = []string{"image/png", "image/jpeg", "image/gif", "image/webp", "image/bmp"}
+
+// docsPage 是接口文档页面，页面根据 /openapi.json 生成每个生成接口的预览表单
+//
+//go:embed docs.html
+var docsPage []byte
+
+// HandleDocs 是接口文档页面的处理程序
+func HandleDocs(c *gin.Context) {
+	c.Data(http.StatusOK, "text/html; charset=utf-8", docsPage)
+}
+
+// HandleOpenAPI 返回当前配置下 OpenAPI 3 格式的接口文档，关闭的接口不会列出
+func HandleOpenAPI(c *gin.Context) {
+	c.JSON(http.StatusOK, openAPISpec(settings))
+}
+
+// openAPISpec 根据配置生成 OpenAPI 文档
+func openAPISpec(cfg *config.Config) gin.H {
+	schemas := gin.H{
+		"Error": gin.H{
+			"type":     "object",
+			"required": []string{"code", "error"},
+			"properties": gin.H{
+				"code":   gin.H{"type": "string", "description": "错误码"},
+				"error":  gin.H{"type": "string", "description": "按请求的语言翻译的错误信息"},
+				"detail": gin.H{"type": "string", "description": "原始的错误信息"},
+				"fields": gin.H{"type": "array", "items": gin.H{"$ref": "#/components/schemas/FieldError"}},
+				"limit":  gin.H{"type": "string", "description": "超出的资源限制"},
+			},
+		},
+		"FieldError": typeSchema(reflect.TypeOf(FieldError{})),
+		"ImageData": gin.H{
+			"type": "object",
+			"properties": gin.H{
+				"format": gin.H{"type": "string"},
+				"width":  gin.H{"type": "integer"},
+				"height": gin.H{"type": "integer"},
+				"data":   gin.H{"type": "string", "description": "data URI"},
+			},
+		},
+	}
+
+	paths := gin.H{}
+	for _, ep := range apiEndpoints {
+		if !ep.enabled(cfg.Features) {
+			continue
+		}
+		req := ep.newRequest()
+		name := reflect.TypeOf(req).Elem().Name()
+		schemas[name] = bodySchema(reflect.ValueOf(req).Elem())
+
+		item := gin.H{}
+		params := pathParams(reflect.ValueOf(req).Elem())
+		if ep.get {
+			get := operation(ep, "get", params)
+			get["parameters"] = append(get["parameters"].([]gin.H), queryParams(reflect.ValueOf(req).Elem())...)
+			item["get"] = get
+		}
+		post := operation(ep, "post", params)
+		post["requestBody"] = gin.H{
+			"required": true,
+			"content":  gin.H{"application/json": gin.H{"schema": gin.H{"$ref": "#/components/schemas/" + name}}},
+		}
+		item["post"] = post
+		paths[ep.path] = item
+	}
+	if cfg.Features.Render {
+		op := operation(apiEndpoint{path: "/render/{template}", summary: "使用模板渲染海报"}, "post", []gin.H{{
+			"name": "template", "in": "path", "required": true, "schema": gin.H{"type": "string"}, "description": "模板名称",
+		}})
+		op["parameters"] = append(op["parameters"].([]gin.H), queryParams(reflect.ValueOf(OutputRequest{}))...)
+		op["requestBody"] = gin.H{
+			"content": gin.H{"application/json": gin.H{"schema": gin.H{
+				"type": "object", "description": "模板变量", "additionalProperties": gin.H{"type": "string"},
+			}}},
+		}
+		paths["/render/{template}"] = gin.H{"post": op}
+	}
+	paths["/fonts"] = gin.H{"get": gin.H{"operationId": "getFonts", "summary": "列出可用字体", "tags": []string{"服务"},
+		"responses": gin.H{"200": jsonResponse("字体列表")}}}
+	paths["/livez"] = gin.H{"get": gin.H{"operationId": "getLivez", "summary": "存活检查", "tags": []string{"服务"},
+		"responses": gin.H{"200": jsonResponse("进程存活")}}}
+	paths["/readyz"] = gin.H{"get": gin.H{"operationId": "getReadyz", "summary": "就绪检查", "tags": []string{"服务"},
+		"responses": gin.H{"200": jsonResponse("可以处理请求"), "503": jsonResponse("未就绪或正在停止")}}}
+
+	spec := gin.H{
+		"openapi": "3.0.3",
+		"info": gin.H{
+			"title":       "pix-gen",
+			"version":     "1.0",
+			"description": apiDescription(cfg),
+		},
+		"paths":      paths,
+		"components": gin.H{"schemas": schemas},
+	}
+	if cfg.Auth.Enabled {
+		paths["/usage"] = gin.H{"get": gin.H{"operationId": "getUsage", "summary": "当前租户本月的用量", "tags": []string{"服务"},
+			"responses": gin.H{"200": jsonResponse("用量")}}}
+		spec["components"].(gin.H)["securitySchemes"] = gin.H{
+			"bearer":   gin.H{"type": "http", "scheme": "bearer", "description": "租户的 API key"},
+			"queryKey": gin.H{"type": "apiKey", "in": "query", "name": "key", "description": "租户的 API key"},
+		}
+		spec["security"] = []gin.H{{"bearer": []string{}}, {"queryKey": []string{}}}
+	}
+	return spec
+}
+
+// apiDescription 返回文档的说明，列出当前的资源限制
+func apiDescription(cfg *config.Config) string {
+	l := cfg.Limits
+	desc := fmt.Sprintf("图片宽高不超过 %dx%d、像素数不超过 %d 个，超出时返回 422；文字参数不超过 %d 个字符，二维码内容不超过 %d 字节，超出时返回 413。",
+		l.MaxWidth, l.MaxHeight, l.MaxArea, l.MaxTextLength, l.MaxQRCodeLength)
+	if cfg.Signing.Enabled {
+		desc += "生成接口需要签名参数 kid、exp 和 sig，见 pix-gen sign。"
+	}
+	return desc
+}
+
+// operation 返回生成接口的操作描述和通用的响应
+func operation(ep apiEndpoint, method string, params []gin.H) gin.H {
+	ok := gin.H{"description": "生成结果"}
+	switch ep.produces {
+	case "":
+		content := gin.H{}
+		for _, t := range imageTypes {
+			content[t] = gin.H{"schema": gin.H{"type": "string", "format": "binary"}}
+		}
+		content["application/json"] = gin.H{"schema": gin.H{"$ref": "#/components/schemas/ImageData"}}
+		ok["content"] = content
+	default:
+		ok["content"] = gin.H{ep.produces: gin.H{"schema": gin.H{"type": "string", "format": "binary"}}}
+	}
+	responses := gin.H{
+		"200": ok,
+		"400": errorResponse("参数错误"),
+		"413": errorResponse("文字超出长度限制"),
+		"422": errorResponse("尺寸超出限制或渲染超时"),
+		"429": errorResponse("请求过于频繁或超出配额"),
+		"503": errorResponse("服务繁忙，稍后重试"),
+	}
+	if ep.cached {
+		responses["304"] = gin.H{"description": "If-None-Match 与 ETag 相同"}
+	}
+	// 每个操作使用独立的参数列表，错误信息和默认文字的语言可以由 lang 参数指定
+	params = append(append([]gin.H{}, params...), param("lang", "query", false, gin.H{
+		"type": "string", "enum": i18n.Langs, "description": "语言，未指定时按 Accept-Language 选择",
+	}))
+	return gin.H{
+		"operationId": operationID(method, ep.path),
+		"summary":     ep.summary,
+		"tags":        []string{"生成"},
+		"parameters":  params,
+		"responses":   responses,
+	}
+}
+
+// operationID 由方法和路径生成操作 ID，例如 getPlaceholderWH
+func operationID(method, path string) string {
+	id := method
+	for _, seg := range strings.Split(path, "/") {
+		seg = strings.Trim(seg, "{}")
+		if seg != "" {
+			id += strings.ToUpper(seg[:1]) + seg[1:]
+		}
+	}
+	return id
+}
+
+// jsonResponse 返回 JSON 响应的描述
+func jsonResponse(desc string) gin.H {
+	return gin.H{"description": desc, "content": gin.H{"application/json": gin.H{"schema": gin.H{"type": "object"}}}}
+}
+
+// errorResponse 返回错误响应的描述
+func errorResponse(desc string) gin.H {
+	return gin.H{"description": desc, "content": gin.H{"application/json": gin.H{"schema": gin.H{"$ref": "#/components/schemas/Error"}}}}
+}
+
+// apiField 是请求结构体中的一个字段及其默认值
+type apiField struct {
+	reflect.StructField
+	value reflect.Value
+}
+
+// apiFields 返回结构体的导出字段，展开嵌入的结构体
+func apiFields(v reflect.Value) []apiField {
+	var fields []apiField
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		if f.Anonymous && f.Type.Kind() == reflect.Struct {
+			fields = append(fields, apiFields(v.Field(i))...)
+			continue
+		}
+		fields = append(fields, apiField{f, v.Field(i)})
+	}
+	return fields
+}
+
+// tagName 返回标签中的名称，未设置或为 - 时返回空字符串
+func tagName(f reflect.StructField, key string) string {
+	name, _, _ := strings.Cut(f.Tag.Get(key), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// queryParams 返回 GET 请求的查询参数
+func queryParams(v reflect.Value) []gin.H {
+	var params []gin.H
+	for _, f := range apiFields(v) {
+		name := tagName(f.StructField, "form")
+		if name == "" {
+			continue
+		}
+		s, required := fieldSchema(f)
+		// 查询参数中的数组和对象以 JSON 字符串传递
+		if t, _ := s["type"].(string); t == "array" || t == "object" {
+			s = gin.H{"type": "string", "description": appendDesc(s, "JSON 字符串")}
+		}
+		params = append(params, param(name, "query", required, s))
+	}
+	return params
+}
+
+// pathParams 返回路径参数
+func pathParams(v reflect.Value) []gin.H {
+	var params []gin.H
+	for _, f := range apiFields(v) {
+		if name := tagName(f.StructField, "uri"); name != "" {
+			s, _ := fieldSchema(f)
+			params = append(params, param(name, "path", true, s))
+		}
+	}
+	return params
+}
+
+// param 返回参数描述，说明从字段的 schema 中移到参数上
+func param(name, in string, required bool, s gin.H) gin.H {
+	p := gin.H{"name": name, "in": in, "required": required, "schema": s}
+	if desc, ok := s["description"]; ok {
+		p["description"] = desc
+		delete(s, "description")
+	}
+	return p
+}
+
+// bodySchema 返回 POST 请求体的 schema
+func bodySchema(v reflect.Value) gin.H {
+	props := gin.H{}
+	var required []string
+	for _, f := range apiFields(v) {
+		name := tagName(f.StructField, "json")
+		if name == "" {
+			continue
+		}
+		s, req := fieldSchema(f)
+		props[name] = s
+		if req {
+			required = append(required, name)
+		}
+	}
+	s := gin.H{"type": "object", "properties": props}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	return s
+}
+
+// fieldSchema 根据字段的类型、binding 标签、doc 标签和默认值返回 schema，并返回字段是否必填
+// 带有 required 约束但构造函数设置了默认值的字段不是必填的
+func fieldSchema(f apiField) (gin.H, bool) {
+	s := typeSchema(f.Type)
+	if doc := f.Tag.Get("doc"); doc != "" {
+		s["description"] = doc
+	}
+	required := false
+	for _, rule := range strings.Split(f.Tag.Get("binding"), ",") {
+		key, arg, _ := strings.Cut(rule, "=")
+		switch key {
+		case "required":
+			required = f.value.IsZero()
+		case "min", "max", "gt":
+			n, err := strconv.ParseFloat(arg, 64)
+			if err != nil {
+				continue
+			}
+			switch {
+			case key == "gt":
+				s["minimum"], s["exclusiveMinimum"] = n, true
+			case s["type"] == "array":
+				s[key+"Items"] = int(n)
+			case s["type"] == "string":
+				s[key+"Length"] = int(n)
+			case key == "min":
+				s["minimum"] = n
+			default:
+				s["maximum"] = n
+			}
+		case "oneof":
+			s["enum"] = strings.Fields(arg)
+		case "color":
+			s["format"] = "color"
+			s["description"] = appendDesc(s, "颜色名或不含 # 的 16 进制值")
+		}
+	}
+	if !f.value.IsZero() {
+		s["default"] = f.value.Interface()
+	}
+	return s, required
+}
+
+// appendDesc 在 schema 的说明后追加补充说明
+func appendDesc(s gin.H, extra string) string {
+	if desc, _ := s["description"].(string); desc != "" {
+		return desc + "，" + extra
+	}
+	return extra
+}
+
+// spanListType 是 SpanList 的类型，文档中为片段的二维数组
+var spanListType = reflect.TypeOf(SpanList{})
+
+// typeSchema 返回 Go 类型对应的 schema
+func typeSchema(t reflect.Type) gin.H {
+	switch {
+	case t == spanListType:
+		return typeSchema(reflect.TypeOf([]richtext.Line{}))
+	case t == reflect.TypeOf(json.RawMessage{}):
+		return gin.H{}
+	}
+	switch t.Kind() {
+	case reflect.Pointer:
+		return typeSchema(t.Elem())
+	case reflect.String:
+		return gin.H{"type": "string"}
+	case reflect.Bool:
+		return gin.H{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return gin.H{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return gin.H{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return gin.H{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return gin.H{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return bodySchema(reflect.New(t).Elem())
+	}
+	return gin.H{}
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/gin-gonic/gin"
+)
+
+// TestHandleOpenAPI 测试文档中的默认值和约束来自请求结构体，并且按文档发出的请求能被接受
+func TestHandleOpenAPI(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default()
+	cfg.Features.Avatar = false
+	Configure(cfg)
+	r := gin.New()
+	r.GET("/openapi.json", HandleOpenAPI)
+	r.GET("/qrcode", HandleQrcode)
+	r.GET("/barcode", HandleBarcode)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	var spec struct {
+		Paths map[string]map[string]struct {
+			Parameters []struct {
+				Name     string `json:"name"`
+				In       string `json:"in"`
+				Required bool   `json:"required"`
+				Schema   struct {
+					Type    string        `json:"type"`
+					Default interface{}   `json:"default"`
+					Enum    []interface{} `json:"enum"`
+				} `json:"schema"`
+			} `json:"parameters"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &spec); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := spec.Paths["/avatar"]; ok {
+		t.Error("disabled /avatar is documented")
+	}
+	if _, ok := spec.Paths["/placeholder/{w}/{h}"]["get"]; !ok {
+		t.Error("/placeholder/{w}/{h} is not documented")
+	}
+
+	for _, path := range []string{"/qrcode", "/barcode"} {
+		query := url.Values{}
+		for _, p := range spec.Paths[path]["get"].Parameters {
+			switch {
+			case p.Name == "color":
+				if p.Schema.Default != "000000" {
+					t.Errorf("%s color default = %v, want 000000", path, p.Schema.Default)
+				}
+			case p.Name == "level" && len(p.Schema.Enum) != 4:
+				t.Errorf("%s level enum = %v", path, p.Schema.Enum)
+			}
+			switch {
+			case p.Required:
+				query.Set(p.Name, "SPEC-1")
+			case len(p.Schema.Enum) > 0:
+				query.Set(p.Name, fmt.Sprint(p.Schema.Enum[len(p.Schema.Enum)-1]))
+			case p.Schema.Default != nil:
+				query.Set(p.Name, fmt.Sprint(p.Schema.Default))
+			}
+		}
+		if !strings.Contains(query.Encode(), "text=SPEC-1") && path == "/barcode" {
+			t.Errorf("%s text is not required: %s", path, query.Encode())
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path+"?"+query.Encode(), nil))
+		if w.Code != http.StatusOK {
+			t.Errorf("GET %s?%s = %d %s", path, query.Encode(), w.Code, w.Body)
+		}
+	}
+}
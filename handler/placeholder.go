@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"fmt"
+	"image"
+	"image/draw"
+	"math"
+	"strings"
+
+	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/bitqiu/pix-gen/pkg/colors"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/richtext"
+	"github.com/gin-gonic/gin"
+)
+
+// HandlePlaceholder 是处理占位图生成请求的处理程序
+// 宽高来自路径，其余参数 GET 从查询参数读取，POST 从 JSON 请求体读取
+func HandlePlaceholder(c *gin.Context) {
+	req := NewPlaceholderRequest()
+	if err := c.ShouldBindUri(req); err != nil {
+		bindError(c, err)
+		return
+	}
+	if !bindRequest(c, req) {
+		return
+	}
+
+	writeCached(c, req, req.Render, req.OutputRequest)
+}
+
+// Render 生成占位图
+func (r *PlaceholderRequest) Render() (image.Image, error) {
+	return generatePlaceholder(r.Width, r.Height, r.Bg, r.Fg, r.Text, r.Font, r.FontSize)
+}
+
+// generatePlaceholder 生成纯色背景、文字居中的占位图
+// text 为空时显示 "宽 × 高"，文字中的 \n 表示换行
+func generatePlaceholder(width, height int, bg, fg, text, fontName string, fontSize float64) (image.Image, error) {
+	if width <= 0 || height <= 0 {
+		return nil, errcode.New(errcode.InvalidSize, "width and height must be positive integers")
+	}
+	bgColor, err := colors.Parse(bg)
+	if err != nil {
+		return nil, errcode.New(errcode.InvalidColor, "invalid background color")
+	}
+	fgColor, err := colors.Parse(fg)
+	if err != nil {
+		return nil, errcode.New(errcode.InvalidColor, "invalid foreground color")
+	}
+	f, err := fonts.Get(fontName)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(bgColor), image.Point{}, draw.Src)
+
+	if text == "" {
+		text = fmt.Sprintf("%d × %d", width, height)
+	}
+	var lines []richtext.Line
+	for _, s := range strings.Split(strings.ReplaceAll(text, `\n`, "\n"), "\n") {
+		lines = append(lines, richtext.Line{{Text: s}})
+	}
+
+	// 未指定字号时从短边的四分之一开始缩小，直到文字宽度不超过图片宽度的 80%，起始字号不超过 limits.maxFontSize
+	opts := richtext.Options{Font: f.Font, FontSize: fontSize, Color: fgColor, LineGap: 4}
+	if fontSize <= 0 {
+		opts.FontSize = math.Min(float64(settings.Limits.MaxFontSize), math.Max(8, float64(min(width, height))/4))
+	}
+	layout, err := richtext.NewLayout(lines, opts)
+	if err != nil {
+		return nil, err
+	}
+	for fontSize <= 0 && opts.FontSize > 8 && (layout.Width > width*8/10 || layout.Height > height*8/10) {
+		opts.FontSize = math.Max(8, opts.FontSize*0.9)
+		if layout, err = richtext.NewLayout(lines, opts); err != nil {
+			return nil, err
+		}
+	}
+	layout.Draw(img, img.Bounds())
+	return img, nil
+}
@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// inkBounds 返回与左上角背景色不同的像素范围
+func inkBounds(img image.Image) image.Rectangle {
+	b := img.Bounds()
+	bg := color.RGBAModel.Convert(img.At(b.Min.X, b.Min.Y))
+	ink := image.Rectangle{}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)) != bg {
+				ink = ink.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return ink
+}
+
+// TestHandlePlaceholder 测试占位图的默认文字、换行、自动缩小字号和颜色参数
+func TestHandlePlaceholder(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Configure(config.Default())
+	// 内嵌字体之外注册 Go 字体，没有默认字体时使用第一个字体
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "Go-Regular.ttf"), goregular.TTF, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := fonts.Load(dir); err != nil {
+		t.Fatal(err)
+	}
+	r := gin.New()
+	r.GET("/placeholder/:w/:h", HandlePlaceholder)
+	get := func(target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		return w
+	}
+	decode := func(target string) image.Image {
+		t.Helper()
+		w := get(target)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: %d %s", target, w.Code, w.Body.String())
+		}
+		img, err := png.Decode(bytes.NewReader(w.Body.Bytes()))
+		if err != nil {
+			t.Fatalf("%s: %v", target, err)
+		}
+		return img
+	}
+
+	// 默认显示 "宽 × 高"
+	img := decode("/placeholder/300/150")
+	if b := img.Bounds(); b.Dx() != 300 || b.Dy() != 150 {
+		t.Errorf("size: %v", b)
+	}
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != (color.RGBA{0xcc, 0xcc, 0xcc, 0xff}) {
+		t.Errorf("default background: %v", got)
+	}
+	if get("/placeholder/300/150").Body.String() != get("/placeholder/300/150?text=300%20%C3%97%20150").Body.String() {
+		t.Error("default text: expected the same image as text=300 × 150")
+	}
+
+	// 字面的 \n 和真实的换行符都表示换行
+	escaped := get("/placeholder/300/150?text=Hero%5Cnbanner").Body.String()
+	if escaped != get("/placeholder/300/150?text=Hero%0Abanner").Body.String() {
+		t.Error(`text with \n: expected the same image as a newline`)
+	}
+	single := inkBounds(decode("/placeholder/300/150?text=Herobanner&fontSize=20"))
+	double := inkBounds(decode("/placeholder/300/150?text=Hero%5Cnbanner&fontSize=20"))
+	if double.Dy() <= single.Dy() || double.Dx() >= single.Dx() {
+		t.Errorf("two lines %v should be taller and narrower than one line %v", double, single)
+	}
+
+	// 未指定字号时缩小到文字宽度不超过图片宽度的 80%
+	auto := inkBounds(decode("/placeholder/60/60?text=Placeholder"))
+	if auto.Empty() || auto.Dx() > 48 {
+		t.Errorf("auto font size: text bounds %v exceed 80%% of the width", auto)
+	}
+	if fixed := inkBounds(decode("/placeholder/60/60?text=Placeholder&fontSize=15")); fixed.Dx() <= auto.Dx() {
+		t.Errorf("fontSize=15 bounds %v should be wider than the shrunk text %v", fixed, auto)
+	}
+
+	// 颜色支持简写的十六进制和颜色名
+	img = decode("/placeholder/100/50?bg=333&fg=white")
+	if got := color.RGBAModel.Convert(img.At(0, 0)); got != (color.RGBA{0x33, 0x33, 0x33, 0xff}) {
+		t.Errorf("bg=333: %v", got)
+	}
+	ink := inkBounds(img)
+	white := false
+	for y := ink.Min.Y; y < ink.Max.Y && !white; y++ {
+		for x := ink.Min.X; x < ink.Max.X; x++ {
+			if color.RGBAModel.Convert(img.At(x, y)) == (color.RGBA{0xff, 0xff, 0xff, 0xff}) {
+				white = true
+				break
+			}
+		}
+	}
+	if !white {
+		t.Error("fg=white: no white text pixels")
+	}
+	for _, target := range []string{"/placeholder/100/50?bg=nope", "/placeholder/100/50?fg=%23zzz"} {
+		if w := get(target); w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), "INVALID") {
+			t.Errorf("%s: %d %s", target, w.Code, w.Body.String())
+		}
+	}
+
+	// 字号超过 limits.maxFontSize 或图片短边时在渲染前拒绝
+	for _, target := range []string{"/placeholder/16/16?fontSize=40000", "/placeholder/100/50?fontSize=51"} {
+		if w := get(target); w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), `"field":"fontSize"`) {
+			t.Errorf("%s: %d %s", target, w.Code, w.Body.String())
+		}
+	}
+	decode("/placeholder/100/50?fontSize=50")
+}
@@ -1,26 +1,35 @@
 package handler
 
 import (
+	"github.com/bitqiu/pix-gen/pkg/colors"
 	qc "github.com/bitqiu/pix-gen/pkg/qrcode"
 	"github.com/gin-gonic/gin"
-	"net/http"
+	"image"
 )
 
 // HandleQrcode 是处理生成二维码请求的处理程序
+// GET 从查询参数读取参数，POST 从 JSON 请求体读取参数
 func HandleQrcode(c *gin.Context) {
-	text := c.DefaultQuery("text", "null")          // 获取二维码的内容，默认为 "null"
-	level := c.DefaultQuery("level", "H")           // 获取错误校验级别，默认为 "H"
-	sizeQuery := c.DefaultQuery("size", "300")      // 获取二维码大小，默认为 300
-	colorQuery := c.DefaultQuery("color", "000000") // 获取前景颜色，默认为黑色
-	marginQuery := c.DefaultQuery("margin", "0")    // 获取边距大小，默认为 0
-
-	// 调用 qc 包生成二维码
-	qrCode, err := qc.GenerateQRCode(text, level, sizeQuery, colorQuery, marginQuery)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	// 解析请求参数，未指定的参数使用默认值
+	req := NewQRCodeRequest()
+	if !bindRequest(c, req) {
 		return
 	}
 
-	// 返回二维码图像
-	c.Data(http.StatusOK, "image/png", qrCode)
+	writeCached(c, req, req.Render, req.OutputRequest)
+}
+
+// Render 生成二维码图片
+func (r *QRCodeRequest) Render() (image.Image, error) {
+	fg, err := colors.Parse(r.Color)
+	if err != nil {
+		return nil, err
+	}
+	return qc.Render(r.Text, qc.Options{
+		Level:  qc.Level(r.Level),
+		Size:   r.Size,
+		Margin: r.Margin,
+		Color:  fg,
+		Logo:   r.logo,
+	})
 }
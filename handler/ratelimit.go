@@ -0,0 +1,95 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrRateLimited 表示客户端的请求超出限流
+var ErrRateLimited = errcode.New(errcode.RateLimited, "rate limit exceeded")
+
+// Limiter 按路由和客户端限流，HTTP 中间件和 gRPC 拦截器共用同一个存储
+// 每个单独配置的路由有自己的令牌桶，其他路由共用默认的令牌桶
+type Limiter struct {
+	store   ratelimit.Store
+	keyBy   string
+	tenants *Tenants
+	def     ratelimit.Limit
+	routes  map[string]ratelimit.Limit
+}
+
+// NewLimiter 根据限流配置创建 Limiter，tenants 为启用认证时的租户
+func NewLimiter(store ratelimit.Store, cfg config.RateLimit, tenants *Tenants) *Limiter {
+	limitOf := func(l config.RouteLimit) ratelimit.Limit {
+		return ratelimit.Per(l.Requests, time.Duration(l.Per), l.Burst)
+	}
+	l := &Limiter{store: store, keyBy: cfg.KeyBy, tenants: tenants, def: limitOf(cfg.Default), routes: map[string]ratelimit.Limit{}}
+	for route, rl := range cfg.Routes {
+		l.routes[route] = limitOf(rl)
+	}
+	return l
+}
+
+// Take 为客户端在路由上占用一个令牌，key 为请求携带的 API key，ip 为客户端 IP
+// keyBy 为 key 且 API key 属于某个租户时按租户限流，同一租户的多个 API key 共用令牌桶；
+// 没有 API key 或 API key 未知时按客户端 IP 限流，避免客户端通过更换 API key 绕过限流
+func (l *Limiter) Take(ctx context.Context, route, key, ip string) (ratelimit.Result, error) {
+	limit, ok := l.routes[route]
+	if !ok {
+		route, limit = "*", l.def
+	}
+	client := "ip:" + ip
+	if l.keyBy == "key" && l.tenants != nil {
+		if t := l.tenants.lookup(key); t != nil {
+			client = "tenant:" + t.Name
+		}
+	}
+	return l.store.Take(ctx, route+" "+client, limit, time.Now())
+}
+
+// RateLimit 返回按客户端限流的中间件，存储出错时放行请求
+// 客户端 IP 只在请求来自可信代理时才取自 X-Forwarded-For
+func (l *Limiter) RateLimit() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		res, err := l.Take(c.Request.Context(), c.FullPath(), apiKey(c), c.ClientIP())
+		if err != nil {
+			c.Next()
+			return
+		}
+		c.Header("RateLimit-Limit", strconv.Itoa(res.Limit))
+		c.Header("RateLimit-Remaining", strconv.Itoa(res.Remaining))
+		c.Header("RateLimit-Reset", ceilSeconds(res.Reset))
+		if !res.Allowed {
+			c.Header("Retry-After", ceilSeconds(res.RetryAfter))
+			writeError(c, ErrRateLimited)
+			return
+		}
+		c.Next()
+	}
+}
+
+// RateLimit 返回按客户端限流的中间件，tenants 为启用认证时的租户
+func RateLimit(store ratelimit.Store, cfg config.RateLimit, tenants *Tenants) gin.HandlerFunc {
+	return NewLimiter(store, cfg, tenants).RateLimit()
+}
+
+// apiKey 从 Authorization: Bearer 请求头或 key 查询参数读取 API key
+func apiKey(c *gin.Context) string {
+	if auth := c.GetHeader("Authorization"); len(auth) > 7 && strings.EqualFold(auth[:7], "Bearer ") {
+		return strings.TrimSpace(auth[7:])
+	}
+	return c.Query("key")
+}
+
+// ceilSeconds 将时长向上取整为秒数
+func ceilSeconds(d time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(d.Seconds())))
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/ratelimit"
+	"github.com/gin-gonic/gin"
+)
+
+// TestRateLimit 测试按路由和客户端限流，以及可信代理的 X-Forwarded-For 和租户的 API key
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	cfg := config.Default().RateLimit
+	cfg.KeyBy = "key"
+	cfg.Routes = map[string]config.RouteLimit{"/captcha": {Requests: 1, Per: config.Duration(time.Minute), Burst: 2}}
+
+	r := gin.New()
+	r.SetTrustedProxies([]string{"10.0.0.1"})
+	tenants, err := NewTenants([]config.Tenant{{Name: "team-a", Keys: []string{"team-a-1", "team-a-2"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Use(RateLimit(ratelimit.NewMemoryStore(), cfg, tenants))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	r.GET("/captcha", ok)
+	r.GET("/qrcode", ok)
+
+	get := func(path, remoteAddr, forwardedFor, key string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remoteAddr + ":1234"
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		if key != "" {
+			req.Header.Set("Authorization", "Bearer "+key)
+		}
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	get("/captcha", "192.0.2.1", "", "")
+	get("/captcha", "192.0.2.1", "", "")
+	w := get("/captcha", "192.0.2.1", "198.51.100.7", "")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "60" || w.Header().Get("RateLimit-Limit") != "2" {
+		t.Errorf("third request: %d %v", w.Code, w.Header())
+	}
+	// 其他路由使用默认的令牌桶
+	if w := get("/qrcode", "192.0.2.1", "", ""); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "20" {
+		t.Errorf("default route: %d %v", w.Code, w.Header())
+	}
+	// 可信代理转发的不同客户端和带 API key 的请求各自计数
+	if w := get("/captcha", "10.0.0.1", "198.51.100.7", ""); w.Code != http.StatusOK {
+		t.Errorf("forwarded client: %d", w.Code)
+	}
+	if w := get("/captcha", "192.0.2.1", "", "team-a-1"); w.Code != http.StatusOK {
+		t.Errorf("API key client: %d", w.Code)
+	}
+	// 同一租户的 API key 共用令牌桶，未知的 API key 按 IP 计数
+	get("/captcha", "192.0.2.1", "", "team-a-2")
+	if w := get("/captcha", "192.0.2.1", "", "team-a-1"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("tenant with several keys: %d", w.Code)
+	}
+	if w := get("/captcha", "192.0.2.1", "", "unknown"); w.Code != http.StatusTooManyRequests {
+		t.Errorf("unknown API key: %d", w.Code)
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"image"
+	"os"
+
+	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/poster"
+	"github.com/gin-gonic/gin"
+	"github.com/golang/freetype/truetype"
+)
+
+// templates 是启动时加载的海报模板
+var templates = map[string]*poster.Template{}
+
+// renderer 渲染海报模板，模板目录同时作为图片素材目录
+var renderer = withImageLimits(&poster.Renderer{Font: lookupFont}, settings.Limits)
+
+// withImageLimits 按资源限制设置模板中图片的上限，图片文件不超过上限尺寸的 RGBA 像素字节数
+func withImageLimits(r *poster.Renderer, l config.Limits) *poster.Renderer {
+	r.MaxImageWidth = l.MaxWidth
+	r.MaxImageHeight = l.MaxHeight
+	r.MaxImageArea = l.MaxArea
+	r.MaxImageBytes = l.MaxArea * 4
+	return r
+}
+
+// lookupFont 从字体注册表中查找字体
+func lookupFont(name string) (*truetype.Font, error) {
+	f, err := fonts.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return f.Font, nil
+}
+
+// LoadTemplates 加载目录下的所有 JSON 模板
+func LoadTemplates(dir string) error {
+	loaded, err := poster.LoadDir(dir)
+	if err != nil {
+		return err
+	}
+	templates = loaded
+	renderer.Assets = os.DirFS(dir)
+	return nil
+}
+
+// HandleRender 是处理模板渲染请求的处理程序
+// 请求体为变量名到值的 JSON 对象
+func HandleRender(c *gin.Context) {
+	name := c.Param("template")
+	t, ok := templates[name]
+	if !ok {
+		writeError(c, errcode.Errorf(errcode.TemplateNotFound, "template %s not found", name))
+		return
+	}
+
+	// 输出参数来自查询参数，请求体为模板变量
+	var out OutputRequest
+	if err := c.ShouldBindQuery(&out); err != nil {
+		bindError(c, err)
+		return
+	}
+
+	vars := map[string]string{}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&vars); err != nil {
+			writeError(c, errcode.Errorf(errcode.InvalidRequest, "invalid variables: %w", err))
+			return
+		}
+	}
+
+	l := &limitCheck{}
+	for name, value := range vars {
+		l.text(name, value)
+	}
+	if len(l.fields) > 0 {
+		l.reject(c)
+		return
+	}
+
+	writeImage(c, func() (image.Image, error) { return renderer.Render(t, vars) }, out)
+}
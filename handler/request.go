@@ -0,0 +1,319 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"image"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/bitqiu/pix-gen/pkg/colors"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/i18n"
+	"github.com/bitqiu/pix-gen/pkg/label"
+	"github.com/bitqiu/pix-gen/pkg/richtext"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		// 错误信息中使用 JSON 字段名，路径参数使用 uri 名称
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			for _, key := range []string{"json", "uri"} {
+				name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+				if name != "" && name != "-" {
+					return name
+				}
+			}
+			return field.Name
+		})
+		_ = v.RegisterValidation("color", func(fl validator.FieldLevel) bool {
+			_, err := colors.Parse(fl.Field().String())
+			return err == nil
+		})
+	}
+}
+
+// FieldError 是单个字段的校验错误
+type FieldError struct {
+	Field  string `json:"field"`
+	Reason string `json:"reason"`
+
+	key  string        // 原因的消息名，响应时按请求的语言翻译
+	args []interface{} // 原因的参数
+}
+
+// newFieldError 返回英文原因的字段错误
+func newFieldError(field, key string, args ...interface{}) FieldError {
+	return FieldError{Field: field, Reason: i18n.En.Text(key, args...), key: key, args: args}
+}
+
+// localize 返回原因翻译为 lang 的字段错误
+func (f FieldError) localize(lang i18n.Lang) FieldError {
+	if f.key != "" {
+		f.Reason = lang.Text(f.key, f.args...)
+	}
+	return f
+}
+
+// validatorFieldError 将校验规则转换为可读的原因
+func validatorFieldError(fe validator.FieldError) FieldError {
+	switch fe.Tag() {
+	case "required", "color":
+		return newFieldError(fe.Field(), "reason."+fe.Tag())
+	case "min", "max", "gt":
+		return newFieldError(fe.Field(), "reason."+fe.Tag(), fe.Param())
+	case "oneof":
+		return newFieldError(fe.Field(), "reason.oneof", strings.ReplaceAll(fe.Param(), " ", ", "))
+	}
+	return newFieldError(fe.Field(), "reason.failed", fe.Tag())
+}
+
+// bindRequest 将请求绑定到 req 并校验
+// GET 请求从查询参数绑定，其他请求从 JSON 请求体绑定；req 中已有的值、所选语言的默认文字和租户的默认参数作为默认值
+// 绑定失败时返回 400 和结构化的错误信息，超出上限时返回 413 或 422，并返回 false
+func bindRequest(c *gin.Context, req interface{}) bool {
+	ApplyDefaults(c.Request.Context(), req)
+	var err error
+	if c.Request.Method == http.MethodGet {
+		err = c.ShouldBindQuery(req)
+	} else {
+		err = c.ShouldBindJSON(req)
+	}
+	if err != nil {
+		bindError(c, err)
+		return false
+	}
+	c.Set(paramsKey, req)
+	varyLang(c, req)
+	return withinLimits(c, req)
+}
+
+// fieldErrors 将绑定错误转换为字段错误列表，不是字段错误时返回 nil
+func fieldErrors(err error) []FieldError {
+	var verrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &verrs):
+		fields := make([]FieldError, 0, len(verrs))
+		for _, fe := range verrs {
+			fields = append(fields, validatorFieldError(fe))
+		}
+		return fields
+	case errors.As(err, &typeErr):
+		return []FieldError{newFieldError(typeErr.Field, "reason.type", typeErr.Type.String())}
+	}
+	return nil
+}
+
+// fieldsCode 返回校验错误的错误码，所有字段都是颜色错误时返回 INVALID_COLOR
+func fieldsCode(err error) errcode.Code {
+	var verrs validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return errcode.InvalidRequest
+	}
+	for _, fe := range verrs {
+		if fe.Tag() != "color" {
+			return errcode.InvalidRequest
+		}
+	}
+	return errcode.InvalidColor
+}
+
+// Validate 按 binding 标签校验请求参数，错误信息逐个列出字段和原因
+func Validate(req interface{}) error {
+	if err := binding.Validator.ValidateStruct(req); err != nil {
+		return validationError(err)
+	}
+	return checkLimits(req).err()
+}
+
+// validationError 将字段错误合并为一条错误信息，不是字段错误时原样返回
+func validationError(err error) error {
+	fields := fieldErrors(err)
+	if fields == nil {
+		return err
+	}
+	return newFieldsError(fieldsCode(err), fields)
+}
+
+// bindError 返回 400 和绑定错误，校验错误逐个列出字段和原因
+// 参数解析返回的错误码（如片段列表的 INVALID_MARKUP）保持不变
+func bindError(c *gin.Context, err error) {
+	if fields := fieldErrors(err); fields != nil {
+		writeError(c, &fieldsError{errcode.New(fieldsCode(err), "invalid request"), fields})
+		return
+	}
+	writeError(c, errcode.Errorf(errcode.Of(err), "invalid request: %w", err))
+}
+
+// OutputRequest 是所有生成接口共用的输出参数
+type OutputRequest struct {
+	Format      string `form:"format" json:"format,omitempty" binding:"omitempty,oneof=png jpeg jpg gif webp bmp" doc:"输出格式"`
+	Quality     int    `form:"quality" json:"quality,omitempty" binding:"min=0,max=100" doc:"JPEG 质量 1-100，未指定时为 90"`
+	Compression string `form:"compression" json:"compression,omitempty" binding:"omitempty,oneof=default none speed best" doc:"PNG 压缩级别"`
+	Output      string `form:"output" json:"output,omitempty" binding:"omitempty,oneof=image json" doc:"为 json 时返回包含 data URI 的 JSON"`
+}
+
+// CaptchaRequest 是生成验证码的参数
+type CaptchaRequest struct {
+	Code   string `form:"code" json:"code" doc:"验证码内容"`
+	Width  int    `form:"width" json:"width" binding:"min=1" doc:"图片宽度"`
+	Height int    `form:"height" json:"height" binding:"min=1" doc:"图片高度"`
+	Font   string `form:"font" json:"font,omitempty" doc:"字体名称"`
+	OutputRequest
+}
+
+// NewCaptchaRequest 返回带有默认值的验证码参数
+func NewCaptchaRequest() *CaptchaRequest {
+	d := settings.Defaults.Captcha
+	return &CaptchaRequest{Width: d.Width, Height: d.Height}
+}
+
+// QRCodeRequest 是生成二维码的参数
+type QRCodeRequest struct {
+	Text   string `form:"text" json:"text" binding:"required" doc:"二维码内容"`
+	Level  string `form:"level" json:"level" binding:"oneof=L M Q H" doc:"错误校验级别"`
+	Size   int    `form:"size" json:"size" binding:"min=1" doc:"图片大小"`
+	Color  string `form:"color" json:"color" binding:"color" doc:"前景颜色"`
+	Margin int    `form:"margin" json:"margin" binding:"min=0" doc:"四周的边距像素数，包含在 size 内"`
+	OutputRequest
+
+	logo image.Image // 租户的标志，不能通过请求设置
+}
+
+// NewQRCodeRequest 返回带有默认值的二维码参数
+func NewQRCodeRequest() *QRCodeRequest {
+	d := settings.Defaults.QRCode
+	return &QRCodeRequest{Text: "null", Level: d.Level, Size: d.Size, Color: d.Color, Margin: d.Margin}
+}
+
+// SpanList 是 JSON 片段列表，查询参数中为 JSON 字符串，请求体中为 JSON 数组
+// 使用结构体包装是因为 gin 不会对切片类型调用 UnmarshalParam
+type SpanList struct {
+	Lines []richtext.Line
+}
+
+// UnmarshalJSON 解析一维或二维的片段数组
+func (s *SpanList) UnmarshalJSON(data []byte) error {
+	lines, err := richtext.ParseJSON(data)
+	if err != nil {
+		return err
+	}
+	s.Lines = lines
+	return nil
+}
+
+// MarshalJSON 将片段列表编码为二维数组
+func (s SpanList) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Lines)
+}
+
+// UnmarshalParam 解析查询参数中的 JSON 字符串
+func (s *SpanList) UnmarshalParam(param string) error {
+	if param == "" {
+		return nil
+	}
+	return s.UnmarshalJSON([]byte(param))
+}
+
+// ImageRequest 是生成文字图片的参数
+type ImageRequest struct {
+	Text        string   `form:"text" json:"text" doc:"主文字内容"`
+	TipText     string   `form:"tipText" json:"tipText" doc:"提示文字"`
+	Width       int      `form:"width" json:"width" binding:"min=1" doc:"图片宽度"`
+	Height      int      `form:"height" json:"height" binding:"min=1" doc:"图片高度"`
+	Font        string   `form:"font" json:"font,omitempty" doc:"字体名称"`
+	Markup      string   `form:"markup" json:"markup,omitempty" doc:"标签语法的富文本"`
+	Spans       SpanList `form:"spans" json:"spans,omitempty" doc:"JSON 片段列表"`
+	Mode        string   `form:"mode" json:"mode,omitempty" binding:"omitempty,oneof=text address" doc:"图片模式"`
+	Group       int      `form:"group" json:"group,omitempty" binding:"min=1" doc:"地址模式每组字符数"`
+	Highlight   int      `form:"highlight" json:"highlight,omitempty" binding:"min=0" doc:"地址模式首尾高亮字符数"`
+	Fingerprint bool     `form:"fingerprint" json:"fingerprint,omitempty" doc:"地址模式是否绘制指纹图标"`
+	OutputRequest
+}
+
+// NewImageRequest 返回带有默认值的文字图片参数
+func NewImageRequest() *ImageRequest {
+	d := settings.Defaults.Image
+	return &ImageRequest{
+		Text:      "null",
+		TipText:   d.TipText,
+		Width:     d.Width,
+		Height:    d.Height,
+		Group:     4,
+		Highlight: 4,
+	}
+}
+
+// AvatarRequest 是生成头像的参数
+type AvatarRequest struct {
+	Seed    string `form:"seed" json:"seed" binding:"required" doc:"种子"`
+	Size    int    `form:"size" json:"size" binding:"min=1" doc:"头像边长"`
+	Type    string `form:"type" json:"type" binding:"oneof=identicon initials" doc:"头像类型"`
+	Shape   string `form:"shape" json:"shape" binding:"oneof=circle square rounded" doc:"头像形状"`
+	Palette string `form:"palette" json:"palette" doc:"调色板"`
+	Name    string `form:"name" json:"name,omitempty" doc:"initials 类型的姓名"`
+	Font    string `form:"font" json:"font,omitempty" doc:"字体名称"`
+	OutputRequest
+}
+
+// NewAvatarRequest 返回带有默认值的头像参数
+func NewAvatarRequest() *AvatarRequest {
+	d := settings.Defaults.Avatar
+	return &AvatarRequest{Size: d.Size, Type: d.Type, Shape: d.Shape, Palette: d.Palette}
+}
+
+// PlaceholderRequest 是生成占位图的参数，宽高来自路径
+type PlaceholderRequest struct {
+	Width    int     `uri:"w" json:"-" binding:"min=1" doc:"图片宽度"`
+	Height   int     `uri:"h" json:"-" binding:"min=1" doc:"图片高度"`
+	Bg       string  `form:"bg" json:"bg" binding:"color" doc:"背景颜色"`
+	Fg       string  `form:"fg" json:"fg" binding:"color" doc:"文字颜色"`
+	Text     string  `form:"text" json:"text,omitempty" doc:"文字内容"`
+	FontSize float64 `form:"fontSize" json:"fontSize,omitempty" binding:"min=0" doc:"字号，不能大于图片的短边"`
+	Font     string  `form:"font" json:"font,omitempty" doc:"字体名称"`
+	OutputRequest
+}
+
+// NewPlaceholderRequest 返回带有默认值的占位图参数
+func NewPlaceholderRequest() *PlaceholderRequest {
+	d := settings.Defaults.Placeholder
+	return &PlaceholderRequest{Bg: d.Bg, Fg: d.Fg}
+}
+
+// BarcodeRequest 是生成条码的参数
+type BarcodeRequest struct {
+	Text       string `form:"text" json:"text" binding:"required" doc:"条码内容，ASCII 32-126 字符"`
+	Width      int    `form:"width" json:"width" binding:"min=1" doc:"图片宽度"`
+	Height     int    `form:"height" json:"height" binding:"min=1" doc:"图片高度"`
+	Color      string `form:"color" json:"color" binding:"color" doc:"条的颜色"`
+	Background string `form:"background" json:"background" binding:"color" doc:"背景颜色"`
+	OutputRequest
+}
+
+// NewBarcodeRequest 返回带有默认值的条码参数
+func NewBarcodeRequest() *BarcodeRequest {
+	d := settings.Defaults.Barcode
+	return &BarcodeRequest{Width: d.Width, Height: d.Height, Color: d.Color, Background: d.Background}
+}
+
+// LabelRequest 是生成标签页 PDF 的参数
+type LabelRequest struct {
+	Preset  string       `json:"preset,omitempty" doc:"预设标签纸，如 avery-l7160"`
+	Sheet   *label.Sheet `json:"sheet,omitempty" doc:"自定义版式，优先于预设"`
+	Items   []label.Item `json:"items" binding:"required,min=1,max=10000" doc:"标签列表"`
+	Level   string       `json:"level" binding:"oneof=L M Q H" doc:"二维码错误校验级别"`
+	Padding float64      `json:"padding" binding:"min=0" doc:"标签内边距，毫米"`
+	Skip    int          `json:"skip" binding:"min=0" doc:"第一页跳过的标签数"`
+	Border  bool         `json:"border,omitempty" doc:"是否绘制标签边框"`
+	Font    string       `json:"font,omitempty" doc:"说明文字的字体名称"`
+}
+
+// NewLabelRequest 返回带有默认值的标签页参数
+func NewLabelRequest() *LabelRequest {
+	return &LabelRequest{Preset: "avery-l7160", Level: "M", Padding: 2}
+}
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestBindRequestFieldErrors 测试 POST 请求体的校验错误逐个列出字段
+func TestBindRequestFieldErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	body := `{"text":"hi","level":"Z","size":0,"color":"nope","format":"tiff"}`
+	c.Request = httptest.NewRequest(http.MethodPost, "/qrcode", strings.NewReader(body))
+
+	if bindRequest(c, NewQRCodeRequest()) {
+		t.Fatal("expected validation failure")
+	}
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", w.Code)
+	}
+	var resp struct {
+		Fields []FieldError `json:"fields"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]bool{}
+	for _, f := range resp.Fields {
+		got[f.Field] = true
+	}
+	for _, field := range []string{"level", "size", "color", "format"} {
+		if !got[field] {
+			t.Errorf("missing error for field %s in %s", field, w.Body.String())
+		}
+	}
+}
+
+// TestBindRequestQuery 测试 GET 查询参数与 POST 请求体绑定到相同的结构体
+func TestBindRequestQuery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	query := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(query)
+	c.Request = httptest.NewRequest(http.MethodGet, `/image?width=300&spans=[{"text":"a","bold":true}]`, nil)
+	fromQuery := NewImageRequest()
+	if !bindRequest(c, fromQuery) {
+		t.Fatalf("bind query: %s", query.Body.String())
+	}
+
+	body := httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(body)
+	c.Request = httptest.NewRequest(http.MethodPost, "/image", strings.NewReader(`{"width":300,"spans":[{"text":"a","bold":true}]}`))
+	fromBody := NewImageRequest()
+	if !bindRequest(c, fromBody) {
+		t.Fatalf("bind body: %s", body.Body.String())
+	}
+
+	for _, r := range []*ImageRequest{fromQuery, fromBody} {
+		if r.Width != 300 || r.Height != 100 {
+			t.Errorf("size = %dx%d, want 300x100", r.Width, r.Height)
+		}
+		if len(r.Spans.Lines) != 1 || len(r.Spans.Lines[0]) != 1 || !r.Spans.Lines[0][0].Bold {
+			t.Errorf("spans = %+v", r.Spans.Lines)
+		}
+	}
+}
+
+// TestBindRequestSpanSize 测试片段列表的字号倍数超出范围时返回 INVALID_MARKUP
+func TestBindRequestSpanSize(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, "/image?spans=%5B%7B%22text%22%3A%22a%22%2C%22size%22%3A9%7D%5D", nil),
+		httptest.NewRequest(http.MethodPost, "/image", strings.NewReader(`{"spans":[[{"text":"a"}],[{"text":"b","size":9}]]}`)),
+	} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		if bindRequest(c, NewImageRequest()) || w.Code != http.StatusBadRequest || !strings.Contains(w.Body.String(), `"INVALID_MARKUP"`) {
+			t.Errorf("%s %s: got %d %s", req.Method, req.URL, w.Code, w.Body.String())
+		}
+	}
+}
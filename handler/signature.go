@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/signature"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireSignature 返回校验签名链接的中间件，未签名、签名无效或已过期的请求返回 403
+// POST 请求的签名同时覆盖请求体
+func RequireSignature(signer *signature.Signer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body []byte
+		if c.Request.Body != nil && c.Request.Method != http.MethodGet {
+			var err error
+			if body, err = io.ReadAll(c.Request.Body); err != nil {
+				writeError(c, errcode.Errorf(errcode.InvalidRequest, "failed to read request body: %w", err))
+				return
+			}
+			c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		}
+		if err := signer.Verify(c.Request.Method, c.Request.URL.Path, c.Request.URL.Query(), body, time.Now()); err != nil {
+			writeError(c, err)
+			return
+		}
+		c.Next()
+	}
+}
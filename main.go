@@ -1,26 +1,101 @@
 package main
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/bitqiu/pix-gen/fonts"
 	"github.com/bitqiu/pix-gen/handler"
-	"github.com/gin-contrib/cors"
-	"github.com/gin-gonic/gin"
+	"github.com/bitqiu/pix-gen/pkg/config"
 )
 
+// commands 是所有子命令，不带子命令时启动 HTTP 服务
+var commands = map[string]func(args []string) error{
+	"serve":   runServe,
+	"captcha": generateCommand("captcha", newCaptcha),
+	"qrcode":  generateCommand("qrcode", newQRCode),
+	"barcode": generateCommand("barcode", newBarcode),
+	"image":   generateCommand("image", newImage),
+	"avatar":  generateCommand("avatar", newAvatar),
+	"batch":   runBatch,
+	"labels":  runLabels,
+	"sign":    runSign,
+}
+
+const usage = `usage: pix-gen [-config file] [command] [flags]
+
+commands:
+  serve     start the HTTP server (default)
+  captcha   generate a captcha image
+  qrcode    generate a QR code
+  barcode   generate a Code 128 barcode
+  image     generate a text image
+  avatar    generate an avatar
+  batch     render jobs from a CSV or JSON file into a ZIP archive
+  labels    render a label sheet PDF from a JSON file
+  sign      sign a URL with the configured signing key
+
+run "pix-gen <command> -h" for the flags of a command
+
+-config selects a YAML or TOML config file, defaulting to $PIX_CONFIG;
+PIX_* environment variables override the values in the file
+`
+
+// cfg 是启动时加载的配置
+var cfg *config.Config
+
 func main() {
-	r := gin.Default()
-	config := cors.DefaultConfig()
-	config.AllowCredentials = true
-	config.AllowOrigins = []string{"*"}
-	r.Use(cors.New(config))
-
-	r.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"message": "ok",
-		})
-	})
-
-	r.GET("/captcha", handler.HandleCaptcha)
-	r.GET("/qrcode", handler.HandleQrcode)
-	r.GET("/image", handler.HandleImage)
-	r.Run(":8080")
+	configPath, args := configFlag(os.Args[1:])
+	var err error
+	if cfg, err = config.Load(configPath); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	handler.Configure(cfg)
+
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if name == "help" {
+		fmt.Print(usage)
+		return
+	}
+	run, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
+	}
+
+	// 启动时解析内嵌字体以及配置的字体目录下的字体
+	// 服务在监听端口后再解析字体，解析完成前就绪检查不通过
+	if name != "serve" {
+		if err := fonts.Load(cfg.Fonts.Dirs...); err != nil {
+			fmt.Fprintf(os.Stderr, "load fonts: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if err := run(args); err != nil {
+		fmt.Fprintf(os.Stderr, "pix-gen %s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// configFlag 取出子命令之前的 -config 参数，未指定时使用 PIX_CONFIG 环境变量
+func configFlag(args []string) (string, []string) {
+	path := os.Getenv("PIX_CONFIG")
+	if len(args) == 0 {
+		return path, args
+	}
+	switch arg := strings.TrimPrefix(args[0], "-"); {
+	case arg == "-config" || arg == "config":
+		if len(args) > 1 {
+			return args[1], args[2:]
+		}
+	case strings.HasPrefix(arg, "-config=") || strings.HasPrefix(arg, "config="):
+		_, value, _ := strings.Cut(arg, "=")
+		return value, args[1:]
+	}
+	return path, args
 }
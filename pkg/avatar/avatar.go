@@ -0,0 +1,198 @@
+package avatar
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+	"unicode"
+
+	"github.com/bitqiu/pix-gen/pkg/captcha"
+	"github.com/bitqiu/pix-gen/pkg/colors"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/identicon"
+	"github.com/bitqiu/pix-gen/pkg/richtext"
+	"github.com/golang/freetype/truetype"
+)
+
+// Palettes 是内置的调色板，头像颜色由种子的哈希值从中选取
+var Palettes = map[string][]color.RGBA{
+	"default": {
+		{0xe5, 0x73, 0x73, 0xff}, {0xf0, 0x62, 0x92, 0xff}, {0xba, 0x68, 0xc8, 0xff}, {0x95, 0x75, 0xcd, 0xff},
+		{0x79, 0x86, 0xcb, 0xff}, {0x64, 0xb5, 0xf6, 0xff}, {0x4d, 0xb6, 0xac, 0xff}, {0x81, 0xc7, 0x84, 0xff},
+		{0xff, 0xb7, 0x4d, 0xff}, {0xff, 0x8a, 0x65, 0xff}, {0xa1, 0x88, 0x7f, 0xff}, {0x90, 0xa4, 0xae, 0xff},
+	},
+	"pastel": {
+		{0xff, 0xad, 0xad, 0xff}, {0xff, 0xd6, 0xa5, 0xff}, {0xfd, 0xff, 0xb6, 0xff}, {0xca, 0xff, 0xbf, 0xff},
+		{0x9b, 0xf6, 0xff, 0xff}, {0xa0, 0xc4, 0xff, 0xff}, {0xbd, 0xb2, 0xff, 0xff}, {0xff, 0xc6, 0xff, 0xff},
+	},
+	"vivid": {
+		{0xd3, 0x2f, 0x2f, 0xff}, {0xc2, 0x18, 0x5b, 0xff}, {0x7b, 0x1f, 0xa2, 0xff}, {0x30, 0x3f, 0x9f, 0xff},
+		{0x19, 0x76, 0xd2, 0xff}, {0x00, 0x79, 0x6b, 0xff}, {0x38, 0x8e, 0x3c, 0xff}, {0xf5, 0x7c, 0x00, 0xff},
+	},
+	"mono": {
+		{0x42, 0x42, 0x42, 0xff}, {0x61, 0x61, 0x61, 0xff}, {0x75, 0x75, 0x75, 0xff}, {0x9e, 0x9e, 0x9e, 0xff},
+	},
+}
+
+// ParsePalette 解析调色板，可以是内置调色板名称或逗号分隔的颜色列表
+func ParsePalette(s string) ([]color.RGBA, error) {
+	if s == "" {
+		s = "default"
+	}
+	if p, ok := Palettes[s]; ok {
+		return p, nil
+	}
+	var palette []color.RGBA
+	for _, part := range strings.Split(s, ",") {
+		c, err := colors.Parse(part)
+		if err != nil {
+			return nil, errcode.Errorf(errcode.InvalidColor, "invalid palette %q", s)
+		}
+		palette = append(palette, c)
+	}
+	return palette, nil
+}
+
+// Options 是生成头像的参数
+type Options struct {
+	Size    int            // 头像边长
+	Type    string         // 头像类型：identicon 或 initials
+	Shape   string         // 形状：circle、square、rounded
+	Palette []color.RGBA   // 调色板，为空时使用 default 调色板
+	Text    string         // initials 类型的姓名，为空时使用种子
+	Font    *truetype.Font // initials 类型使用的字体
+}
+
+// Render 根据种子生成确定性的头像，相同种子和参数总是得到相同的图片
+// 形状以外的区域为透明
+func Render(seed string, opts Options) (*image.RGBA, error) {
+	if opts.Size <= 0 {
+		return nil, errcode.New(errcode.InvalidSize, "size must be positive")
+	}
+	palette := opts.Palette
+	if len(palette) == 0 {
+		palette = Palettes["default"]
+	}
+	sum := sha256.Sum256([]byte(seed))
+	fg := palette[binary.BigEndian.Uint32(sum[:4])%uint32(len(palette))]
+
+	img := captcha.NewImage(opts.Size, opts.Size)
+	switch opts.Type {
+	case "", "identicon":
+		// 浅色背景上绘制与调色板颜色一致的对称图标
+		if err := fillShape(img, opts.Shape, color.RGBA{0xf0, 0xf0, 0xf0, 0xff}); err != nil {
+			return nil, err
+		}
+		icon := identicon.New([]byte(seed))
+		icon.Color = fg
+		// 圆形头像的图标绘制在内接正方形中，避免被裁掉
+		inset := 0
+		if opts.Shape == "circle" {
+			inset = opts.Size * 15 / 100
+		}
+		icon.Draw(img, img.Bounds().Inset(inset), nil)
+	case "initials":
+		if opts.Font == nil {
+			return nil, errcode.New(errcode.FontMissing, "font is required for initials avatar")
+		}
+		if err := fillShape(img, opts.Shape, fg); err != nil {
+			return nil, err
+		}
+		text := opts.Text
+		if text == "" {
+			text = seed
+		}
+		letters := Initials(text)
+		size := InitialsFontSize(opts.Size)
+		if len([]rune(letters)) > 1 {
+			size = float64(opts.Size) * 0.34
+		}
+		err := richtext.Draw(img, []richtext.Line{{{Text: letters}}}, richtext.Options{
+			Font:     opts.Font,
+			FontSize: size,
+			Color:    color.White,
+		})
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid avatar type %q", opts.Type)
+	}
+	return img.RGBA, nil
+}
+
+// InitialsFontSize 返回 size 像素的首字母头像的最大字号，两个字符时字号更小
+func InitialsFontSize(size int) float64 {
+	return float64(size) * 0.42
+}
+
+// fillShape 用 captcha 包的绘图方法填充头像形状
+func fillShape(img *captcha.Image, shape string, c color.Color) error {
+	size := img.Bounds().Dx()
+	r := size / 2
+	switch shape {
+	case "", "circle":
+		img.DrawCircle(r, r, r-1, true, c)
+	case "square":
+		draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	case "rounded":
+		// 两个交叉的矩形加四个角上的圆组成圆角矩形
+		radius := size / 6
+		src := image.NewUniform(c)
+		draw.Draw(img, image.Rect(radius, 0, size-radius, size), src, image.Point{}, draw.Src)
+		draw.Draw(img, image.Rect(0, radius, size, size-radius), src, image.Point{}, draw.Src)
+		for _, p := range []image.Point{{radius, radius}, {size - radius - 1, radius}, {radius, size - radius - 1}, {size - radius - 1, size - radius - 1}} {
+			img.DrawCircle(p.X, p.Y, radius, true, c)
+		}
+	default:
+		return fmt.Errorf("invalid avatar shape %q", shape)
+	}
+	return nil
+}
+
+// Initials 从姓名中提取一到两个字符
+// 中日韩姓名取最后两个字（两字姓名取全名，单字取本身），拉丁字母姓名取前两个单词的首字母
+func Initials(name string) string {
+	name = strings.TrimSpace(name)
+	runes := []rune(name)
+	if len(runes) == 0 {
+		return "?"
+	}
+
+	if isCJK(runes[0]) {
+		var cjk []rune
+		for _, r := range runes {
+			if isCJK(r) {
+				cjk = append(cjk, r)
+			}
+		}
+		if len(cjk) > 2 {
+			cjk = cjk[len(cjk)-2:]
+		}
+		return string(cjk)
+	}
+
+	var letters []rune
+	for _, word := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		letters = append(letters, unicode.ToUpper([]rune(word)[0]))
+		if len(letters) == 2 {
+			break
+		}
+	}
+	if len(letters) == 0 {
+		return string(unicode.ToUpper(runes[0]))
+	}
+	return string(letters)
+}
+
+// isCJK 判断字符是否为中日韩文字
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
@@ -0,0 +1,80 @@
+package avatar
+
+import (
+	"bytes"
+	"image/color"
+	"testing"
+
+	"github.com/golang/freetype"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// TestInitials 测试姓名缩写
+func TestInitials(t *testing.T) {
+	tests := []struct {
+		name, want string
+	}{
+		{"John Smith", "JS"},
+		{"alice", "A"},
+		{"jean-luc picard", "JL"},
+		{"张三", "张三"},
+		{"王小明", "小明"},
+		{"  ", "?"},
+		{"#1", "1"},
+	}
+	for _, tt := range tests {
+		if got := Initials(tt.name); got != tt.want {
+			t.Errorf("Initials(%q): expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestParsePalette 测试调色板解析
+func TestParsePalette(t *testing.T) {
+	p, err := ParsePalette("vivid")
+	if err != nil || len(p) != len(Palettes["vivid"]) {
+		t.Errorf("ParsePalette: expected vivid palette, got %v, %v", p, err)
+	}
+	p, err = ParsePalette("ff0000,#00ff00")
+	if err != nil || len(p) != 2 || p[1] != (color.RGBA{0, 255, 0, 255}) {
+		t.Errorf("ParsePalette: expected custom palette, got %v, %v", p, err)
+	}
+	if _, err := ParsePalette("nope,ff0000"); err == nil {
+		t.Errorf("ParsePalette: expected error")
+	}
+}
+
+// TestRender 测试头像的确定性和形状
+func TestRender(t *testing.T) {
+	f, err := freetype.ParseFont(goregular.TTF)
+	if err != nil {
+		t.Fatalf("failed to parse font: %v", err)
+	}
+
+	for _, kind := range []string{"identicon", "initials"} {
+		for _, shape := range []string{"circle", "square", "rounded"} {
+			opts := Options{Size: 64, Type: kind, Shape: shape, Font: f}
+			a, err := Render("user@example.com", opts)
+			if err != nil {
+				t.Fatalf("Render(%s, %s): %v", kind, shape, err)
+			}
+			b, _ := Render("user@example.com", opts)
+			if !bytes.Equal(a.Pix, b.Pix) {
+				t.Errorf("Render(%s, %s): expected deterministic output", kind, shape)
+			}
+
+			// 圆形和圆角头像的角落透明，方形头像的角落不透明
+			corner := a.RGBAAt(0, 0).A
+			if shape == "square" && corner == 0 || shape != "square" && corner != 0 {
+				t.Errorf("Render(%s, %s): unexpected corner alpha %d", kind, shape, corner)
+			}
+		}
+	}
+
+	if _, err := Render("x", Options{Size: 64, Type: "initials"}); err == nil {
+		t.Errorf("Render: expected error without font")
+	}
+	if _, err := Render("x", Options{Size: 64, Shape: "star"}); err == nil {
+		t.Errorf("Render: expected error for invalid shape")
+	}
+}
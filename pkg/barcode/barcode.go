@@ -0,0 +1,112 @@
+// Package barcode 生成一维条码，可以直接在其他 Go 程序中使用
+package barcode
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+)
+
+// Barcode 是编码后的一维条码
+type Barcode struct {
+	Text    string // 条码内容
+	Modules []bool // 每个模块是否为条，包含两侧空白区
+}
+
+// Bars 返回所有条的起始模块和宽度，便于矢量输出
+func (b *Barcode) Bars() [][2]int {
+	var bars [][2]int
+	for i := 0; i < len(b.Modules); i++ {
+		if !b.Modules[i] {
+			continue
+		}
+		start := i
+		for i < len(b.Modules) && b.Modules[i] {
+			i++
+		}
+		bars = append(bars, [2]int{start, i - start})
+	}
+	return bars
+}
+
+// Draw 将条码绘制到 dst 的 rect 范围内
+// 模块宽度取整数像素，条码在 rect 内水平居中；rect 太窄时按比例缩放
+func (b *Barcode) Draw(dst draw.Image, rect image.Rectangle, fg color.Color) {
+	n := len(b.Modules)
+	if n == 0 || rect.Dx() <= 0 {
+		return
+	}
+	src := image.NewUniform(fg)
+
+	module := rect.Dx() / n
+	if module == 0 {
+		for _, bar := range b.Bars() {
+			x0 := rect.Min.X + bar[0]*rect.Dx()/n
+			x1 := rect.Min.X + (bar[0]+bar[1])*rect.Dx()/n
+			draw.Draw(dst, image.Rect(x0, rect.Min.Y, max(x1, x0+1), rect.Max.Y), src, image.Point{}, draw.Src)
+		}
+		return
+	}
+
+	left := rect.Min.X + (rect.Dx()-module*n)/2
+	for _, bar := range b.Bars() {
+		x := left + bar[0]*module
+		draw.Draw(dst, image.Rect(x, rect.Min.Y, x+bar[1]*module, rect.Max.Y), src, image.Point{}, draw.Src)
+	}
+}
+
+// Image 生成 width x height 的条码图片
+func (b *Barcode) Image(width, height int, fg, bg color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.NewUniform(bg), image.Point{}, draw.Src)
+	b.Draw(img, img.Bounds(), fg)
+	return img
+}
+
+// Options 是生成条码图片的参数
+type Options struct {
+	Width      int         // 图片宽度
+	Height     int         // 图片高度
+	Color      color.Color // 条的颜色
+	Background color.Color // 背景颜色
+}
+
+// Option 设置生成条码图片的参数
+type Option func(*Options)
+
+// WithSize 设置图片尺寸，默认为 300x100
+func WithSize(width, height int) Option {
+	return func(o *Options) { o.Width, o.Height = width, height }
+}
+
+// WithColor 设置条的颜色，默认为黑色
+func WithColor(c color.Color) Option {
+	return func(o *Options) { o.Color = c }
+}
+
+// WithBackground 设置背景颜色，默认为白色
+func WithBackground(c color.Color) Option {
+	return func(o *Options) { o.Background = c }
+}
+
+// Encode 将文本编码为 Code 128 条码图片，未设置的选项使用默认值
+func Encode(ctx context.Context, text string, opts ...Option) (image.Image, error) {
+	o := Options{Width: 300, Height: 100, Color: color.Black, Background: color.White}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.Width <= 0 || o.Height <= 0 {
+		return nil, errcode.New(errcode.InvalidSize, "width and height must be positive")
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	b, err := Code128(text)
+	if err != nil {
+		return nil, err
+	}
+	return b.Image(o.Width, o.Height, o.Color, o.Background), nil
+}
@@ -0,0 +1,89 @@
+package barcode
+
+import (
+	"context"
+	"image/color"
+	"testing"
+)
+
+// TestCode128Patterns 测试码表中每个码值的模块数
+func TestCode128Patterns(t *testing.T) {
+	for code, pattern := range code128Patterns {
+		want := 11
+		if code == code128Stop {
+			want = 13
+		}
+		sum := 0
+		for _, w := range pattern {
+			sum += int(w - '0')
+		}
+		if sum != want {
+			t.Errorf("pattern %d: expected %d modules, got %d", code, want, sum)
+		}
+	}
+}
+
+// TestCode128 测试编码长度和码集切换
+func TestCode128(t *testing.T) {
+	tests := []struct {
+		text    string
+		symbols int // 不含起始符、校验码和终止符的码值个数
+	}{
+		{"A", 1},
+		{"123456", 3},    // 全部为 C 码集
+		{"AB123456", 6},  // B 码集 2 个字符，切换到 C 码集，3 对数字
+		{"1234567", 5},   // C 码集 3 对数字，切换到 B 码集，1 位数字
+		{"Asset-01", 8},  // 数字太少，不切换码集
+		{"x12345678", 6}, // B 码集 1 个字符，切换到 C 码集，4 对数字
+	}
+	for _, tt := range tests {
+		b, err := Code128(tt.text)
+		if err != nil {
+			t.Fatalf("Code128(%q): %v", tt.text, err)
+		}
+		want := 2*QuietZone + 11*(tt.symbols+2) + 13
+		if len(b.Modules) != want {
+			t.Errorf("Code128(%q): expected %d modules, got %d", tt.text, want, len(b.Modules))
+		}
+	}
+
+	for _, text := range []string{"", "tab\t", "中文"} {
+		if _, err := Code128(text); err == nil {
+			t.Errorf("Code128(%q): expected error", text)
+		}
+	}
+}
+
+// TestBarcodeImage 测试条码的绘制
+func TestBarcodeImage(t *testing.T) {
+	b, err := Code128("PIX")
+	if err != nil {
+		t.Fatalf("Code128: %v", err)
+	}
+	img := b.Image(len(b.Modules)*2, 20, color.Black, color.White)
+
+	for i, on := range b.Modules {
+		got := img.RGBAAt(i*2, 10)
+		want := color.RGBA{255, 255, 255, 255}
+		if on {
+			want = color.RGBA{0, 0, 0, 255}
+		}
+		if got != want {
+			t.Fatalf("Image: module %d expected %v, got %v", i, want, got)
+		}
+	}
+}
+
+// TestEncode 测试条码图片的默认尺寸和无效尺寸
+func TestEncode(t *testing.T) {
+	img, err := Encode(context.Background(), "PIX-0001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 300 || b.Dy() != 100 {
+		t.Errorf("default size: %v", b)
+	}
+	if _, err := Encode(context.Background(), "PIX", WithSize(0, 10)); err == nil {
+		t.Error("invalid size: expected an error")
+	}
+}
@@ -0,0 +1,105 @@
+// Package cache 提供按字节数限制大小的 LRU 缓存，用于缓存编码后的响应
+package cache
+
+import (
+	"container/list"
+	"sync"
+)
+
+// Entry 是一条缓存的响应
+type Entry struct {
+	ContentType string // 响应类型
+	Data        []byte // 响应内容
+}
+
+// Stats 是缓存的统计信息
+type Stats struct {
+	Hits     uint64 `json:"hits"`     // 命中次数
+	Misses   uint64 `json:"misses"`   // 未命中次数
+	Entries  int    `json:"entries"`  // 缓存条数
+	Bytes    int    `json:"bytes"`    // 缓存的字节数
+	MaxBytes int    `json:"maxBytes"` // 最大字节数
+}
+
+// LRU 是并发安全的 LRU 缓存，总字节数超过上限时淘汰最久未使用的条目
+type LRU struct {
+	mu       sync.Mutex
+	maxBytes int
+	bytes    int
+	hits     uint64
+	misses   uint64
+	order    *list.List               // 最近使用的条目在前
+	items    map[string]*list.Element // 键到链表节点
+}
+
+// item 是链表中保存的键值
+type item struct {
+	key   string
+	entry Entry
+}
+
+// New 创建最多缓存 maxBytes 字节的 LRU 缓存，maxBytes 为 0 时不缓存
+func New(maxBytes int) *LRU {
+	return &LRU{
+		maxBytes: maxBytes,
+		order:    list.New(),
+		items:    map[string]*list.Element{},
+	}
+}
+
+// size 返回条目占用的字节数，包括键的长度
+func size(key string, e Entry) int {
+	return len(key) + len(e.ContentType) + len(e.Data)
+}
+
+// Get 查找缓存，并记录命中或未命中
+func (c *LRU) Get(key string) (Entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		c.misses++
+		return Entry{}, false
+	}
+	c.hits++
+	c.order.MoveToFront(el)
+	return el.Value.(*item).entry, true
+}
+
+// Add 添加或替换缓存，超过单条上限的条目不缓存
+func (c *LRU) Add(key string, e Entry) {
+	n := size(key, e)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n > c.maxBytes {
+		return
+	}
+	if el, ok := c.items[key]; ok {
+		c.remove(el)
+	}
+	c.items[key] = c.order.PushFront(&item{key: key, entry: e})
+	c.bytes += n
+	for c.bytes > c.maxBytes {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove 删除一个条目
+func (c *LRU) remove(el *list.Element) {
+	it := c.order.Remove(el).(*item)
+	delete(c.items, it.key)
+	c.bytes -= size(it.key, it.entry)
+}
+
+// Stats 返回缓存的统计信息
+func (c *LRU) Stats() Stats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return Stats{
+		Hits:     c.hits,
+		Misses:   c.misses,
+		Entries:  c.order.Len(),
+		Bytes:    c.bytes,
+		MaxBytes: c.maxBytes,
+	}
+}
@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestLRU 测试按字节数淘汰最久未使用的条目
+func TestLRU(t *testing.T) {
+	entry := func(n int) Entry { return Entry{Data: []byte(strings.Repeat("x", n))} }
+	c := New(30)
+	c.Add("a", entry(9))
+	c.Add("b", entry(9))
+	c.Add("c", entry(9))
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("a should be cached")
+	}
+	// a 刚被使用，添加 d 时淘汰 b
+	c.Add("d", entry(9))
+	if _, ok := c.Get("b"); ok {
+		t.Error("b should have been evicted")
+	}
+	for _, key := range []string{"a", "c", "d"} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("%s should be cached", key)
+		}
+	}
+	// 超过上限的条目不缓存
+	c.Add("e", entry(100))
+	if _, ok := c.Get("e"); ok {
+		t.Error("oversized entry should not be cached")
+	}
+
+	s := c.Stats()
+	if s.Hits != 4 || s.Misses != 2 || s.Entries != 3 || s.Bytes != 30 {
+		t.Errorf("stats = %+v", s)
+	}
+}
@@ -1,6 +1,7 @@
 package captcha
 
 import (
+	"github.com/bitqiu/pix-gen/pkg/errcode"
 	"github.com/golang/freetype"
 	"github.com/golang/freetype/truetype"
 
@@ -13,6 +14,9 @@ import (
 	"time"
 )
 
+// ErrFontMissing 表示生成验证码前没有添加任何字体
+var ErrFontMissing = errcode.New(errcode.FontMissing, "no fonts added")
+
 // Captcha 结构体定义了验证码的属性
 type Captcha struct {
 	frontColors []color.Color    // 前景色
@@ -83,6 +87,14 @@ func (c *Captcha) AddFontFromBytes(contents []byte) error {
 	return nil
 }
 
+// AddParsedFont 添加一个已解析的字体
+func (c *Captcha) AddParsedFont(font *truetype.Font) {
+	if c.fonts == nil {
+		c.fonts = []*truetype.Font{}
+	}
+	c.fonts = append(c.fonts, font)
+}
+
 // SetFont 设置字体，可以设置多个
 func (c *Captcha) SetFont(paths ...string) error {
 	for _, v := range paths {
@@ -177,9 +189,6 @@ func (c *Captcha) drawNoises(img *Image) {
 
 // drawString 绘制文字
 func (c *Captcha) drawString(img *Image, str string) {
-	if c.fonts == nil {
-		panic("没有设置任何字体")
-	}
 	tmp := NewImage(c.size.X, c.size.Y)
 
 	// 文字大小为图片高度的 0.6
@@ -221,8 +230,11 @@ func (c *Captcha) drawString(img *Image, str string) {
 	draw.Draw(img, tmp.Bounds(), tmp, image.ZP, draw.Over)
 }
 
-// Create 生成一个验证码图片
-func (c *Captcha) Create(num int, t StrType) (*Image, string) {
+// Create 生成一个验证码图片，没有添加字体时返回 ErrFontMissing
+func (c *Captcha) Create(num int, t StrType) (*Image, string, error) {
+	if len(c.fonts) == 0 {
+		return nil, "", ErrFontMissing
+	}
 	if num <= 0 {
 		num = 4
 	}
@@ -233,11 +245,14 @@ func (c *Captcha) Create(num int, t StrType) (*Image, string) {
 	str := string(c.randStr(num, int(t)))
 	c.drawString(dst, str)
 
-	return dst, str
+	return dst, str, nil
 }
 
-// CreateCustom 生成自定义字符串的验证码图片
-func (c *Captcha) CreateCustom(str string) *Image {
+// CreateCustom 生成自定义字符串的验证码图片，没有添加字体时返回 ErrFontMissing
+func (c *Captcha) CreateCustom(str string) (*Image, error) {
+	if len(c.fonts) == 0 {
+		return nil, ErrFontMissing
+	}
 	if len(str) == 0 {
 		str = "unknown"
 	}
@@ -245,7 +260,7 @@ func (c *Captcha) CreateCustom(str string) *Image {
 	c.drawBkg(dst)
 	c.drawNoises(dst)
 	c.drawString(dst, str)
-	return dst
+	return dst, nil
 }
 
 var fontKinds = [][]int{
@@ -0,0 +1,17 @@
+package captcha
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestCreateWithoutFont 测试没有设置字体时返回错误而不是 panic
+func TestCreateWithoutFont(t *testing.T) {
+	c := New()
+	if _, err := c.CreateCustom("abcd"); !errors.Is(err, ErrFontMissing) {
+		t.Errorf("CreateCustom() error = %v, want %v", err, ErrFontMissing)
+	}
+	if _, _, err := c.Create(4, NUM); !errors.Is(err, ErrFontMissing) {
+		t.Errorf("Create() error = %v, want %v", err, ErrFontMissing)
+	}
+}
@@ -0,0 +1,117 @@
+package captcha
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound 表示验证码不存在、已使用或已过期
+var ErrNotFound = errors.New("captcha: not found or expired")
+
+// Store 保存已签发验证码的答案，多个实例共享验证码时可以实现基于 Redis 等的存储
+type Store interface {
+	// Set 保存 id 对应的答案，ttl 后过期
+	Set(ctx context.Context, id, answer string, ttl time.Duration) error
+	// Take 取出并删除 id 对应的答案，不存在或已过期时返回 ErrNotFound
+	Take(ctx context.Context, id string) (string, error)
+	// Ping 检查存储是否可用
+	Ping(ctx context.Context) error
+}
+
+// NewID 返回随机的验证码 ID
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// RandomCode 返回 n 个不易混淆的随机字符，使用加密安全的随机数
+func RandomCode(n int) string {
+	if n <= 0 {
+		n = 4
+	}
+	code := make([]byte, n)
+	max := big.NewInt(int64(len(letters)))
+	for i := range code {
+		k, _ := rand.Int(rand.Reader, max)
+		code[i] = letters[k.Int64()]
+	}
+	return string(code)
+}
+
+// Verify 取出 id 对应的答案并与 code 比较，不区分大小写
+// 每个验证码只能校验一次，无论是否正确；不存在或已过期时返回 false
+func Verify(ctx context.Context, s Store, id, code string) (bool, error) {
+	answer, err := s.Take(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	a, b := strings.ToLower(answer), strings.ToLower(code)
+	return subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1, nil
+}
+
+// MemoryStore 是进程内的验证码存储
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	swept   time.Time
+	now     func() time.Time
+}
+
+// memoryEntry 是一个验证码的答案和过期时间
+type memoryEntry struct {
+	answer  string
+	expires time.Time
+}
+
+// NewMemoryStore 创建进程内的验证码存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: map[string]memoryEntry{}, now: time.Now}
+}
+
+// Set 保存 id 对应的答案，每分钟清理一次过期的验证码
+func (s *MemoryStore) Set(_ context.Context, id, answer string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := s.now()
+	if now.Sub(s.swept) >= time.Minute {
+		for k, e := range s.entries {
+			if !now.Before(e.expires) {
+				delete(s.entries, k)
+			}
+		}
+		s.swept = now
+	}
+	s.entries[id] = memoryEntry{answer: answer, expires: now.Add(ttl)}
+	return nil
+}
+
+// Take 取出并删除 id 对应的答案
+func (s *MemoryStore) Take(_ context.Context, id string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.entries[id]
+	if !ok {
+		return "", ErrNotFound
+	}
+	delete(s.entries, id)
+	if !s.now().Before(e.expires) {
+		return "", ErrNotFound
+	}
+	return e.answer, nil
+}
+
+// Ping 检查存储是否可用，进程内存储总是可用
+func (s *MemoryStore) Ping(context.Context) error {
+	return nil
+}
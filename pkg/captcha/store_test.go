@@ -0,0 +1,58 @@
+package captcha
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestVerify 测试验证码只能校验一次、不区分大小写并且过期后失效
+func TestVerify(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	s := NewMemoryStore()
+	s.now = func() time.Time { return now }
+
+	s.Set(ctx, "a", "Kx7P", time.Minute)
+	s.Set(ctx, "b", "Kx7P", time.Minute)
+	s.Set(ctx, "c", "Kx7P", time.Minute)
+	tests := []struct {
+		id, code string
+		after    time.Duration
+		want     bool
+	}{
+		{"a", "kX7p", 0, true},
+		{"a", "Kx7P", 0, false}, // 已经校验过
+		{"b", "Kx7", 0, false},
+		{"b", "Kx7P", 0, false}, // 校验失败也会失效
+		{"c", "Kx7P", time.Minute, false},
+		{"missing", "", 0, false},
+	}
+	for _, tt := range tests {
+		now = now.Add(tt.after)
+		got, err := Verify(ctx, s, tt.id, tt.code)
+		if err != nil || got != tt.want {
+			t.Errorf("Verify(%s, %s) = %v, %v, want %v", tt.id, tt.code, got, err, tt.want)
+		}
+	}
+}
+
+// TestRandomCode 测试随机验证码的长度和字符集
+func TestRandomCode(t *testing.T) {
+	for _, n := range []int{0, 6} {
+		code := RandomCode(n)
+		want := n
+		if n == 0 {
+			want = 4
+		}
+		if len(code) != want {
+			t.Errorf("RandomCode(%d) = %q", n, code)
+		}
+		for _, r := range code {
+			if !strings.ContainsRune(string(letters), r) {
+				t.Errorf("RandomCode(%d) = %q contains %q", n, code, r)
+			}
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package colors
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+)
+
+// ErrInvalid 表示颜色无法解析
+var ErrInvalid = errcode.New(errcode.InvalidColor, "invalid color format")
+
+// names 是颜色名称到16进制颜色值的映射表
+var names = map[string]string{
+	"black":   "000000",
+	"white":   "ffffff",
+	"red":     "ff0000",
+	"green":   "00ff00",
+	"blue":    "0000ff",
+	"yellow":  "ffff00",
+	"cyan":    "00ffff",
+	"magenta": "ff00ff",
+	"gray":    "808080",
+	"purple":  "800080",
+	"orange":  "ffa500",
+}
+
+// Parse 根据颜色名字或16进制颜色值返回RGBA颜色
+// 支持 rgb、rrggbb 和 rrggbbaa 三种16进制格式，可带 # 前缀
+func Parse(input string) (color.RGBA, error) {
+	hex := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(input)), "#")
+
+	// 如果是颜色名字，转换为16进制颜色值
+	if hexValue, ok := names[hex]; ok {
+		hex = hexValue
+	}
+
+	// 3 位简写展开为 6 位
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+	if len(hex) != 8 {
+		return color.RGBA{}, ErrInvalid
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.RGBA{}, ErrInvalid
+	}
+	c := color.NRGBA{R: uint8(v >> 24), G: uint8(v >> 16), B: uint8(v >> 8), A: uint8(v)}
+	return color.RGBAModel.Convert(c).(color.RGBA), nil
+}
+
+// MustParse 与 Parse 相同，但颜色无效时 panic，用于程序中的常量颜色
+func MustParse(input string) color.RGBA {
+	c, err := Parse(input)
+	if err != nil {
+		panic(fmt.Sprintf("colors: %q: %v", input, err))
+	}
+	return c
+}
+
+// Hex 返回颜色的16进制表示，不透明颜色为 rrggbb，否则为 rrggbbaa
+func Hex(c color.Color) string {
+	n := color.NRGBAModel.Convert(c).(color.NRGBA)
+	if n.A == 0xff {
+		return fmt.Sprintf("%02x%02x%02x", n.R, n.G, n.B)
+	}
+	return fmt.Sprintf("%02x%02x%02x%02x", n.R, n.G, n.B, n.A)
+}
@@ -0,0 +1,58 @@
+package colors
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestParse 测试 Parse 方法
+func TestParse(t *testing.T) {
+	tests := []struct {
+		input string
+		want  color.RGBA
+	}{
+		{"red", color.RGBA{255, 0, 0, 255}},
+		{"#F00", color.RGBA{255, 0, 0, 255}},
+		{"549ecc", color.RGBA{0x54, 0x9e, 0xcc, 255}},
+		{"#00000000", color.RGBA{0, 0, 0, 0}},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.input)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("Parse(%q): expected %v, got %v", tt.input, tt.want, got)
+		}
+	}
+
+	for _, input := range []string{"", "12345", "zzzzzz", "notacolor"} {
+		if _, err := Parse(input); err == nil {
+			t.Errorf("Parse(%q): expected error", input)
+		}
+	}
+}
+
+// TestHex 测试 Hex 方法
+func TestHex(t *testing.T) {
+	if got := Hex(color.RGBA{0x54, 0x9e, 0xcc, 255}); got != "549ecc" {
+		t.Errorf("Hex: expected 549ecc, got %s", got)
+	}
+	if got := Hex(color.NRGBA{255, 0, 0, 0x80}); got != "ff000080" {
+		t.Errorf("Hex: expected ff000080, got %s", got)
+	}
+}
+
+// TestMustParse 测试无效颜色时 panic
+func TestMustParse(t *testing.T) {
+	if got := MustParse("0b6bcb"); got != (color.RGBA{0x0b, 0x6b, 0xcb, 255}) {
+		t.Errorf("MustParse: got %v", got)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Error("MustParse(\"notacolor\") did not panic")
+		}
+	}()
+	MustParse("notacolor")
+}
@@ -0,0 +1,598 @@
+// Package config 加载服务配置
+// 配置依次来自内置默认值、YAML 或 TOML 配置文件和 PIX_ 开头的环境变量，后者覆盖前者
+package config
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitqiu/pix-gen/pkg/avatar"
+	"github.com/bitqiu/pix-gen/pkg/colors"
+	"github.com/bitqiu/pix-gen/pkg/signature"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// Config 是服务配置
+type Config struct {
+	Server    Server    `yaml:"server" toml:"server"`
+	CORS      CORS      `yaml:"cors" toml:"cors"`
+	Fonts     Fonts     `yaml:"fonts" toml:"fonts"`
+	Templates Templates `yaml:"templates" toml:"templates"`
+	Defaults  Defaults  `yaml:"defaults" toml:"defaults"`
+	Limits    Limits    `yaml:"limits" toml:"limits"`
+	Cache     Cache     `yaml:"cache" toml:"cache"`
+	Signing   Signing   `yaml:"signing" toml:"signing"`
+	RateLimit RateLimit `yaml:"rateLimit" toml:"rateLimit"`
+	Auth      Auth      `yaml:"auth" toml:"auth"`
+	GRPC      GRPC      `yaml:"grpc" toml:"grpc"`
+	Log       Log       `yaml:"log" toml:"log"`
+	Features  Features  `yaml:"features" toml:"features"`
+}
+
+// Server 是 HTTP 服务配置，同时配置证书和私钥时启用 HTTPS
+type Server struct {
+	Addr    string `yaml:"addr" toml:"addr" env:"PIX_ADDR"`           // 监听地址
+	TLSCert string `yaml:"tlsCert" toml:"tlsCert" env:"PIX_TLS_CERT"` // 证书文件路径
+	TLSKey  string `yaml:"tlsKey" toml:"tlsKey" env:"PIX_TLS_KEY"`    // 私钥文件路径
+
+	// 可信代理的地址或网段，只有来自这些代理的 X-Forwarded-For 才用于识别客户端 IP
+	TrustedProxies []string `yaml:"trustedProxies" toml:"trustedProxies" env:"PIX_TRUSTED_PROXIES"`
+
+	// 超时为 0 时不限制
+	ReadTimeout     Duration `yaml:"readTimeout" toml:"readTimeout" env:"PIX_READ_TIMEOUT"`             // 读取整个请求的最长时间
+	WriteTimeout    Duration `yaml:"writeTimeout" toml:"writeTimeout" env:"PIX_WRITE_TIMEOUT"`          // 从读完请求头到写完响应的最长时间
+	IdleTimeout     Duration `yaml:"idleTimeout" toml:"idleTimeout" env:"PIX_IDLE_TIMEOUT"`             // keep-alive 连接的最长空闲时间
+	ShutdownTimeout Duration `yaml:"shutdownTimeout" toml:"shutdownTimeout" env:"PIX_SHUTDOWN_TIMEOUT"` // 收到 SIGTERM 后等待进行中请求完成的最长时间
+	DrainDelay      Duration `yaml:"drainDelay" toml:"drainDelay" env:"PIX_DRAIN_DELAY"`                // 收到 SIGTERM 后 /readyz 返回 503、继续接受请求的时间
+}
+
+// CORS 是跨域配置
+type CORS struct {
+	AllowOrigins     []string `yaml:"allowOrigins" toml:"allowOrigins" env:"PIX_CORS_ALLOW_ORIGINS"`             // 允许的来源，* 表示任意来源
+	AllowCredentials bool     `yaml:"allowCredentials" toml:"allowCredentials" env:"PIX_CORS_ALLOW_CREDENTIALS"` // 是否允许携带凭据
+}
+
+// Fonts 是字体配置
+type Fonts struct {
+	Dirs []string `yaml:"dirs" toml:"dirs" env:"PIX_FONT_DIR"` // 内嵌字体之外的字体目录
+}
+
+// Templates 是海报模板配置
+type Templates struct {
+	Dir string `yaml:"dir" toml:"dir" env:"PIX_TEMPLATE_DIR"` // 模板目录
+}
+
+// Defaults 是各接口参数的默认值
+type Defaults struct {
+	Captcha     CaptchaDefaults     `yaml:"captcha" toml:"captcha"`
+	QRCode      QRCodeDefaults      `yaml:"qrcode" toml:"qrcode"`
+	Barcode     BarcodeDefaults     `yaml:"barcode" toml:"barcode"`
+	Image       ImageDefaults       `yaml:"image" toml:"image"`
+	Avatar      AvatarDefaults      `yaml:"avatar" toml:"avatar"`
+	Placeholder PlaceholderDefaults `yaml:"placeholder" toml:"placeholder"`
+}
+
+// CaptchaDefaults 是验证码的默认参数
+type CaptchaDefaults struct {
+	Width  int `yaml:"width" toml:"width" env:"PIX_CAPTCHA_WIDTH"`
+	Height int `yaml:"height" toml:"height" env:"PIX_CAPTCHA_HEIGHT"`
+}
+
+// QRCodeDefaults 是二维码的默认参数
+type QRCodeDefaults struct {
+	Size   int    `yaml:"size" toml:"size" env:"PIX_QRCODE_SIZE"`
+	Level  string `yaml:"level" toml:"level" env:"PIX_QRCODE_LEVEL"`
+	Color  string `yaml:"color" toml:"color" env:"PIX_QRCODE_COLOR"`
+	Margin int    `yaml:"margin" toml:"margin" env:"PIX_QRCODE_MARGIN"`
+}
+
+// BarcodeDefaults 是条码的默认参数
+type BarcodeDefaults struct {
+	Width      int    `yaml:"width" toml:"width" env:"PIX_BARCODE_WIDTH"`
+	Height     int    `yaml:"height" toml:"height" env:"PIX_BARCODE_HEIGHT"`
+	Color      string `yaml:"color" toml:"color" env:"PIX_BARCODE_COLOR"`
+	Background string `yaml:"background" toml:"background" env:"PIX_BARCODE_BACKGROUND"`
+}
+
+// ImageDefaults 是文字图片的默认参数
+type ImageDefaults struct {
+	Width   int    `yaml:"width" toml:"width" env:"PIX_IMAGE_WIDTH"`
+	Height  int    `yaml:"height" toml:"height" env:"PIX_IMAGE_HEIGHT"`
+	TipText string `yaml:"tipText" toml:"tipText" env:"PIX_IMAGE_TIP_TEXT"`
+}
+
+// AvatarDefaults 是头像的默认参数
+type AvatarDefaults struct {
+	Size    int    `yaml:"size" toml:"size" env:"PIX_AVATAR_SIZE"`
+	Type    string `yaml:"type" toml:"type" env:"PIX_AVATAR_TYPE"`
+	Shape   string `yaml:"shape" toml:"shape" env:"PIX_AVATAR_SHAPE"`
+	Palette string `yaml:"palette" toml:"palette" env:"PIX_AVATAR_PALETTE"`
+}
+
+// PlaceholderDefaults 是占位图的默认参数
+type PlaceholderDefaults struct {
+	Bg string `yaml:"bg" toml:"bg" env:"PIX_PLACEHOLDER_BG"`
+	Fg string `yaml:"fg" toml:"fg" env:"PIX_PLACEHOLDER_FG"`
+}
+
+// Limits 是请求参数和渲染资源的上限
+type Limits struct {
+	MaxWidth        int      `yaml:"maxWidth" toml:"maxWidth" env:"PIX_MAX_WIDTH"`                       // 图片最大宽度
+	MaxHeight       int      `yaml:"maxHeight" toml:"maxHeight" env:"PIX_MAX_HEIGHT"`                    // 图片最大高度
+	MaxArea         int      `yaml:"maxArea" toml:"maxArea" env:"PIX_MAX_AREA"`                          // 图片最大像素数
+	MaxTextLength   int      `yaml:"maxTextLength" toml:"maxTextLength" env:"PIX_MAX_TEXT_LENGTH"`       // 文字参数的最大字符数
+	MaxQRCodeLength int      `yaml:"maxQRCodeLength" toml:"maxQRCodeLength" env:"PIX_MAX_QRCODE_LENGTH"` // 二维码内容的最大字节数
+	MaxFontSize     int      `yaml:"maxFontSize" toml:"maxFontSize" env:"PIX_MAX_FONT_SIZE"`             // 文字的最大字号（像素），含富文本的字号倍数
+	RenderTimeout   Duration `yaml:"renderTimeout" toml:"renderTimeout" env:"PIX_RENDER_TIMEOUT"`        // 单个请求等待和渲染的最长时间
+	MaxConcurrency  int      `yaml:"maxConcurrency" toml:"maxConcurrency" env:"PIX_MAX_CONCURRENCY"`     // 同时渲染的最大请求数
+	MaxBodyBytes    int      `yaml:"maxBodyBytes" toml:"maxBodyBytes" env:"PIX_MAX_BODY_BYTES"`          // 请求体的最大字节数
+}
+
+// Cache 是响应缓存配置
+type Cache struct {
+	MaxBytes int      `yaml:"maxBytes" toml:"maxBytes" env:"PIX_CACHE_MAX_BYTES"` // 内存缓存的最大字节数，为 0 时不缓存
+	MaxAge   Duration `yaml:"maxAge" toml:"maxAge" env:"PIX_CACHE_MAX_AGE"`       // Cache-Control 的 max-age
+}
+
+// Signing 是签名链接配置，启用后生成接口拒绝未签名或过期的请求
+type Signing struct {
+	Enabled bool     `yaml:"enabled" toml:"enabled" env:"PIX_SIGNING_ENABLED"` // 是否校验签名
+	Keys    []string `yaml:"keys" toml:"keys" env:"PIX_SIGNING_KEYS"`          // id:secret 形式的密钥，第一个用于签名
+}
+
+// Signer 根据配置的密钥创建签名器
+func (s Signing) Signer() (*signature.Signer, error) {
+	keys := make([]signature.Key, 0, len(s.Keys))
+	for i, k := range s.Keys {
+		id, secret, ok := strings.Cut(k, ":")
+		if !ok || id == "" {
+			return nil, fmt.Errorf("key %d must be id:secret", i+1)
+		}
+		if len(secret) < 16 {
+			return nil, fmt.Errorf("secret of key %q must be at least 16 characters", id)
+		}
+		keys = append(keys, signature.Key{ID: id, Secret: []byte(secret)})
+	}
+	return signature.New(keys...)
+}
+
+// RateLimit 是按客户端限流的配置
+type RateLimit struct {
+	Enabled bool                  `yaml:"enabled" toml:"enabled" env:"PIX_RATE_LIMIT_ENABLED"` // 是否限流
+	KeyBy   string                `yaml:"keyBy" toml:"keyBy" env:"PIX_RATE_LIMIT_KEY_BY"`      // ip 按客户端 IP，key 按 API key 所属的租户，需要启用认证
+	Default RouteLimit            `yaml:"default" toml:"default"`                              // 未单独配置的接口共用的限制
+	Routes  map[string]RouteLimit `yaml:"routes" toml:"routes"`                                // 按路由路径单独配置的限制，如 /captcha
+}
+
+// RouteLimit 是每 Per 时间允许 Requests 个请求、最多突发 Burst 个请求的限制
+type RouteLimit struct {
+	Requests int      `yaml:"requests" toml:"requests" env:"PIX_RATE_LIMIT_REQUESTS"`
+	Per      Duration `yaml:"per" toml:"per" env:"PIX_RATE_LIMIT_PER"`
+	Burst    int      `yaml:"burst" toml:"burst" env:"PIX_RATE_LIMIT_BURST"`
+}
+
+// Auth 是 API key 认证配置，启用后生成接口只接受租户的 API key
+type Auth struct {
+	Enabled     bool     `yaml:"enabled" toml:"enabled" env:"PIX_AUTH_ENABLED"`              // 是否要求 API key
+	TenantsFile string   `yaml:"tenantsFile" toml:"tenantsFile" env:"PIX_AUTH_TENANTS_FILE"` // 租户文件，顶层为 tenants 列表，与 tenants 合并
+	Tenants     []Tenant `yaml:"tenants" toml:"tenants"`                                     // 租户列表
+}
+
+// Endpoints 是可以分配给租户的接口名称
+var Endpoints = []string{"captcha", "qrcode", "barcode", "image", "avatar", "placeholder", "render", "batch", "labels"}
+
+// Tenant 是共用同一个部署的团队
+type Tenant struct {
+	Name         string         `yaml:"name" toml:"name"`                 // 租户名称
+	Keys         []string       `yaml:"keys" toml:"keys"`                 // API key，可以配置多个便于轮换
+	Endpoints    []string       `yaml:"endpoints" toml:"endpoints"`       // 允许使用的接口，为空时允许全部接口
+	MonthlyQuota int            `yaml:"monthlyQuota" toml:"monthlyQuota"` // 每月生成次数上限，为 0 时不限
+	Defaults     TenantDefaults `yaml:"defaults" toml:"defaults"`         // 租户的默认参数
+}
+
+// TenantDefaults 是租户的默认参数，未设置的项使用全局默认值
+type TenantDefaults struct {
+	Color      string `yaml:"color" toml:"color"`           // 品牌色，用作二维码、条码和占位图文字的颜色
+	Background string `yaml:"background" toml:"background"` // 条码和占位图的背景颜色
+	Logo       string `yaml:"logo" toml:"logo"`             // PNG 或 JPEG 标志文件，绘制在二维码中央
+	TipText    string `yaml:"tipText" toml:"tipText"`       // 文字图片的提示文字
+}
+
+// Duration 是以 "10s"、"500ms" 形式配置的时长
+type Duration time.Duration
+
+// UnmarshalText 解析 time.ParseDuration 格式的时长
+func (d *Duration) UnmarshalText(text []byte) error {
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(v)
+	return nil
+}
+
+// MarshalText 将时长格式化为 time.Duration 的字符串形式
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(time.Duration(d).String()), nil
+}
+
+// GRPC 是 gRPC 服务配置，gRPC 服务监听单独的端口，与 HTTP 服务共用证书、认证和资源限制
+type GRPC struct {
+	Enabled       bool     `yaml:"enabled" toml:"enabled" env:"PIX_GRPC_ENABLED"`                    // 是否启动 gRPC 服务
+	Addr          string   `yaml:"addr" toml:"addr" env:"PIX_GRPC_ADDR"`                             // 监听地址
+	CaptchaLength int      `yaml:"captchaLength" toml:"captchaLength" env:"PIX_GRPC_CAPTCHA_LENGTH"` // 签发的验证码字符数
+	CaptchaTTL    Duration `yaml:"captchaTTL" toml:"captchaTTL" env:"PIX_GRPC_CAPTCHA_TTL"`          // 验证码的有效期
+}
+
+// Log 是访问日志配置
+type Log struct {
+	Level  string `yaml:"level" toml:"level" env:"PIX_LOG_LEVEL"`    // 日志级别，debug、info、warn 或 error
+	Format string `yaml:"format" toml:"format" env:"PIX_LOG_FORMAT"` // 日志格式，json 或 text
+}
+
+// SlogLevel 返回日志级别对应的 slog.Level，未知的级别按 info 处理
+func (l Log) SlogLevel() slog.Level {
+	var level slog.Level
+	level.UnmarshalText([]byte(l.Level))
+	return level
+}
+
+// Features 是各接口的开关，关闭的接口不会注册路由
+type Features struct {
+	Captcha     bool `yaml:"captcha" toml:"captcha" env:"PIX_FEATURE_CAPTCHA"`
+	QRCode      bool `yaml:"qrcode" toml:"qrcode" env:"PIX_FEATURE_QRCODE"`
+	Barcode     bool `yaml:"barcode" toml:"barcode" env:"PIX_FEATURE_BARCODE"`
+	Image       bool `yaml:"image" toml:"image" env:"PIX_FEATURE_IMAGE"`
+	Avatar      bool `yaml:"avatar" toml:"avatar" env:"PIX_FEATURE_AVATAR"`
+	Placeholder bool `yaml:"placeholder" toml:"placeholder" env:"PIX_FEATURE_PLACEHOLDER"`
+	Render      bool `yaml:"render" toml:"render" env:"PIX_FEATURE_RENDER"`
+	Batch       bool `yaml:"batch" toml:"batch" env:"PIX_FEATURE_BATCH"`
+	Labels      bool `yaml:"labels" toml:"labels" env:"PIX_FEATURE_LABELS"`
+	Metrics     bool `yaml:"metrics" toml:"metrics" env:"PIX_FEATURE_METRICS"` // Prometheus 指标 /metrics
+	Docs        bool `yaml:"docs" toml:"docs" env:"PIX_FEATURE_DOCS"`          // 接口文档 /openapi.json 和 /docs
+}
+
+// Default 返回内置的默认配置
+func Default() *Config {
+	return &Config{
+		Server: Server{
+			Addr:            ":8080",
+			ReadTimeout:     Duration(15 * time.Second),
+			WriteTimeout:    Duration(60 * time.Second),
+			IdleTimeout:     Duration(120 * time.Second),
+			ShutdownTimeout: Duration(30 * time.Second),
+			DrainDelay:      Duration(5 * time.Second),
+		},
+		CORS: CORS{AllowOrigins: []string{"*"}},
+		Defaults: Defaults{
+			Captcha:     CaptchaDefaults{Width: 120, Height: 30},
+			QRCode:      QRCodeDefaults{Size: 300, Level: "H", Color: "000000"},
+			Barcode:     BarcodeDefaults{Width: 300, Height: 100, Color: "000000", Background: "ffffff"},
+			Image:       ImageDefaults{Width: 500, Height: 100, TipText: "请通过图片和复制的地址核对一样后进行转账"},
+			Avatar:      AvatarDefaults{Size: 128, Type: "identicon", Shape: "circle", Palette: "default"},
+			Placeholder: PlaceholderDefaults{Bg: "cccccc", Fg: "969696"},
+		},
+		Limits: Limits{
+			MaxWidth:        4096,
+			MaxHeight:       4096,
+			MaxArea:         4096 * 4096,
+			MaxTextLength:   1000,
+			MaxQRCodeLength: 2953, // 二维码在 L 级别下可容纳的最大字节数
+			MaxFontSize:     512,  // 最大尺寸的文字图片基准字号约为 410
+			RenderTimeout:   Duration(10 * time.Second),
+			MaxConcurrency:  runtime.NumCPU(),
+			MaxBodyBytes:    8 << 20,
+		},
+		Cache: Cache{MaxBytes: 64 << 20, MaxAge: Duration(24 * time.Hour)},
+		RateLimit: RateLimit{
+			KeyBy:   "ip",
+			Default: RouteLimit{Requests: 60, Per: Duration(time.Minute), Burst: 20},
+			Routes: map[string]RouteLimit{
+				"/captcha": {Requests: 10, Per: Duration(time.Minute), Burst: 5},
+			},
+		},
+		GRPC: GRPC{Addr: ":9090", CaptchaLength: 4, CaptchaTTL: Duration(5 * time.Minute)},
+		Log:  Log{Level: "info", Format: "json"},
+		Features: Features{
+			Captcha: true, QRCode: true, Barcode: true, Image: true, Avatar: true,
+			Placeholder: true, Render: true, Batch: true, Labels: true, Metrics: true, Docs: true,
+		},
+	}
+}
+
+// Load 加载配置，path 为空时只使用默认值和环境变量
+// 根据扩展名识别 .yaml、.yml 和 .toml 文件，未知的配置项视为错误
+func Load(path string) (*Config, error) {
+	cfg := Default()
+	if path != "" {
+		if err := decodeFile(path, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := applyEnv(reflect.ValueOf(cfg).Elem()); err != nil {
+		return nil, err
+	}
+	if file := cfg.Auth.TenantsFile; file != "" {
+		var tenants struct {
+			Tenants []Tenant `yaml:"tenants" toml:"tenants"`
+		}
+		if err := decodeFile(file, &tenants); err != nil {
+			return nil, err
+		}
+		cfg.Auth.Tenants = append(cfg.Auth.Tenants, tenants.Tenants...)
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// decodeFile 根据扩展名解码 YAML 或 TOML 文件，未知的配置项视为错误
+func decodeFile(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("config: %v", err)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(v); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("config %s: %v", path, err)
+		}
+	case ".toml":
+		dec := toml.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(v); err != nil {
+			var strict *toml.StrictMissingError
+			if errors.As(err, &strict) {
+				return fmt.Errorf("config %s: %s", path, strict.String())
+			}
+			return fmt.Errorf("config %s: %v", path, err)
+		}
+	default:
+		return fmt.Errorf("config %s: unsupported format, use .yaml, .yml or .toml", path)
+	}
+	return nil
+}
+
+// applyEnv 用环境变量覆盖带 env 标签的字段，列表以逗号分隔
+func applyEnv(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			if err := applyEnv(value); err != nil {
+				return err
+			}
+			continue
+		}
+		name := field.Tag.Get("env")
+		s, ok := os.LookupEnv(name)
+		if name == "" || !ok {
+			continue
+		}
+		if u, ok := value.Addr().Interface().(encoding.TextUnmarshaler); ok {
+			if err := u.UnmarshalText([]byte(strings.TrimSpace(s))); err != nil {
+				return fmt.Errorf("config: %s: %v", name, err)
+			}
+			continue
+		}
+		switch field.Type.Kind() {
+		case reflect.String:
+			value.SetString(s)
+		case reflect.Int:
+			n, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil {
+				return fmt.Errorf("config: %s must be an integer, got %q", name, s)
+			}
+			value.SetInt(int64(n))
+		case reflect.Bool:
+			b, err := strconv.ParseBool(strings.TrimSpace(s))
+			if err != nil {
+				return fmt.Errorf("config: %s must be true or false, got %q", name, s)
+			}
+			value.SetBool(b)
+		case reflect.Slice:
+			var list []string
+			for _, item := range strings.Split(s, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					list = append(list, item)
+				}
+			}
+			value.Set(reflect.ValueOf(list))
+		}
+	}
+	return nil
+}
+
+// Validate 检查配置，返回所有错误
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, key, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf("config: %s %s", key, fmt.Sprintf(format, args...)))
+		}
+	}
+	positive := func(key string, n int) {
+		check(n > 0, key, "must be positive, got %d", n)
+	}
+	color := func(key, s string) {
+		_, err := colors.Parse(s)
+		check(err == nil, key, "is not a valid color: %q", s)
+	}
+	oneOf := func(key, s string, values ...string) {
+		for _, v := range values {
+			if s == v {
+				return
+			}
+		}
+		check(false, key, "must be one of %s, got %q", strings.Join(values, ", "), s)
+	}
+	maxSize := func(key string, w, h int) {
+		check(w <= c.Limits.MaxWidth && h <= c.Limits.MaxHeight && w*h <= c.Limits.MaxArea,
+			key, "%dx%d exceeds limits.maxWidth, limits.maxHeight or limits.maxArea", w, h)
+	}
+
+	check(c.Server.Addr != "", "server.addr", "must not be empty")
+	check((c.Server.TLSCert == "") == (c.Server.TLSKey == ""), "server.tlsCert", "and server.tlsKey must be set together")
+	for _, file := range [][2]string{{"server.tlsCert", c.Server.TLSCert}, {"server.tlsKey", c.Server.TLSKey}} {
+		if file[1] != "" {
+			_, err := os.Stat(file[1])
+			check(err == nil, file[0], "%v", err)
+		}
+	}
+
+	for _, timeout := range []struct {
+		key string
+		d   Duration
+	}{
+		{"server.readTimeout", c.Server.ReadTimeout},
+		{"server.writeTimeout", c.Server.WriteTimeout},
+		{"server.idleTimeout", c.Server.IdleTimeout},
+		{"server.shutdownTimeout", c.Server.ShutdownTimeout},
+		{"server.drainDelay", c.Server.DrainDelay},
+	} {
+		check(timeout.d >= 0, timeout.key, "must not be negative, got %s", time.Duration(timeout.d))
+	}
+	check(c.Server.WriteTimeout == 0 || c.Server.WriteTimeout > c.Limits.RenderTimeout,
+		"server.writeTimeout", "must be longer than limits.renderTimeout (%s)", time.Duration(c.Limits.RenderTimeout))
+
+	for _, origin := range c.CORS.AllowOrigins {
+		check(origin != "*" || !c.CORS.AllowCredentials, "cors.allowCredentials", "cannot be used with allowOrigins \"*\"; list the allowed origins instead")
+		check(origin == "*" || strings.HasPrefix(origin, "http://") || strings.HasPrefix(origin, "https://"),
+			"cors.allowOrigins", "entries must be \"*\" or start with http:// or https://, got %q", origin)
+	}
+
+	for _, dir := range c.Fonts.Dirs {
+		info, err := os.Stat(dir)
+		check(err == nil && info.IsDir(), "fonts.dirs", "%q is not a directory", dir)
+	}
+	if c.Templates.Dir != "" {
+		info, err := os.Stat(c.Templates.Dir)
+		check(err == nil && info.IsDir(), "templates.dir", "%q is not a directory", c.Templates.Dir)
+	}
+
+	positive("limits.maxWidth", c.Limits.MaxWidth)
+	positive("limits.maxHeight", c.Limits.MaxHeight)
+	positive("limits.maxArea", c.Limits.MaxArea)
+	positive("limits.maxTextLength", c.Limits.MaxTextLength)
+	positive("limits.maxQRCodeLength", c.Limits.MaxQRCodeLength)
+	positive("limits.maxFontSize", c.Limits.MaxFontSize)
+	positive("limits.maxConcurrency", c.Limits.MaxConcurrency)
+	positive("limits.maxBodyBytes", c.Limits.MaxBodyBytes)
+	check(c.Limits.RenderTimeout > 0, "limits.renderTimeout", "must be positive, got %s", time.Duration(c.Limits.RenderTimeout))
+
+	check(c.Cache.MaxBytes >= 0, "cache.maxBytes", "must not be negative, got %d", c.Cache.MaxBytes)
+	check(c.Cache.MaxAge >= 0, "cache.maxAge", "must not be negative, got %s", time.Duration(c.Cache.MaxAge))
+
+	check(!c.Signing.Enabled || len(c.Signing.Keys) > 0, "signing.keys", "must not be empty when signing is enabled")
+	if len(c.Signing.Keys) > 0 {
+		_, err := c.Signing.Signer()
+		check(err == nil, "signing.keys", "%v", err)
+	}
+
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, err := net.ParseCIDR(proxy)
+		check(err == nil || net.ParseIP(proxy) != nil, "server.trustedProxies", "%q is not an IP address or CIDR", proxy)
+	}
+
+	oneOf("rateLimit.keyBy", c.RateLimit.KeyBy, "ip", "key")
+	check(c.RateLimit.KeyBy != "key" || c.Auth.Enabled, "rateLimit.keyBy", "key requires auth to be enabled")
+	routeLimit := func(key string, l RouteLimit) {
+		positive(key+".requests", l.Requests)
+		positive(key+".burst", l.Burst)
+		check(l.Per > 0, key+".per", "must be positive, got %s", time.Duration(l.Per))
+	}
+	routeLimit("rateLimit.default", c.RateLimit.Default)
+	routes := make([]string, 0, len(c.RateLimit.Routes))
+	for route := range c.RateLimit.Routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+	for _, route := range routes {
+		check(strings.HasPrefix(route, "/"), "rateLimit.routes", "%q must be a route path starting with /", route)
+		routeLimit("rateLimit.routes."+route, c.RateLimit.Routes[route])
+	}
+
+	check(!c.Auth.Enabled || len(c.Auth.Tenants) > 0, "auth.tenants", "must not be empty when auth is enabled")
+	names, keys := map[string]bool{}, map[string]bool{}
+	for i, t := range c.Auth.Tenants {
+		key := fmt.Sprintf("auth.tenants[%d]", i)
+		check(t.Name != "" && !names[t.Name], key+".name", "must be set and unique, got %q", t.Name)
+		names[t.Name] = true
+		check(len(t.Keys) > 0, key+".keys", "must not be empty")
+		for j, k := range t.Keys {
+			check(len(k) >= 16, fmt.Sprintf("%s.keys[%d]", key, j), "must be at least 16 characters")
+			check(!keys[k], fmt.Sprintf("%s.keys[%d]", key, j), "is already used")
+			keys[k] = true
+		}
+		for _, e := range t.Endpoints {
+			oneOf(key+".endpoints", e, Endpoints...)
+		}
+		check(t.MonthlyQuota >= 0, key+".monthlyQuota", "must not be negative, got %d", t.MonthlyQuota)
+		if t.Defaults.Color != "" {
+			color(key+".defaults.color", t.Defaults.Color)
+		}
+		if t.Defaults.Background != "" {
+			color(key+".defaults.background", t.Defaults.Background)
+		}
+		if t.Defaults.Logo != "" {
+			_, err := os.Stat(t.Defaults.Logo)
+			check(err == nil, key+".defaults.logo", "%v", err)
+		}
+	}
+
+	d := c.Defaults
+	positive("defaults.captcha.width", d.Captcha.Width)
+	positive("defaults.captcha.height", d.Captcha.Height)
+	maxSize("defaults.captcha", d.Captcha.Width, d.Captcha.Height)
+	positive("defaults.qrcode.size", d.QRCode.Size)
+	maxSize("defaults.qrcode.size", d.QRCode.Size, d.QRCode.Size)
+	oneOf("defaults.qrcode.level", d.QRCode.Level, "L", "M", "Q", "H")
+	color("defaults.qrcode.color", d.QRCode.Color)
+	check(d.QRCode.Margin >= 0 && d.QRCode.Margin <= d.QRCode.Size/4, "defaults.qrcode.margin", "must be between 0 and a quarter of the size, got %d", d.QRCode.Margin)
+	positive("defaults.barcode.width", d.Barcode.Width)
+	positive("defaults.barcode.height", d.Barcode.Height)
+	maxSize("defaults.barcode", d.Barcode.Width, d.Barcode.Height)
+	color("defaults.barcode.color", d.Barcode.Color)
+	color("defaults.barcode.background", d.Barcode.Background)
+	positive("defaults.image.width", d.Image.Width)
+	positive("defaults.image.height", d.Image.Height)
+	maxSize("defaults.image", d.Image.Width, d.Image.Height)
+	positive("defaults.avatar.size", d.Avatar.Size)
+	maxSize("defaults.avatar.size", d.Avatar.Size, d.Avatar.Size)
+	oneOf("defaults.avatar.type", d.Avatar.Type, "identicon", "initials")
+	oneOf("defaults.avatar.shape", d.Avatar.Shape, "circle", "square", "rounded")
+	_, err := avatar.ParsePalette(d.Avatar.Palette)
+	check(err == nil, "defaults.avatar.palette", "%v", err)
+	color("defaults.placeholder.bg", d.Placeholder.Bg)
+	color("defaults.placeholder.fg", d.Placeholder.Fg)
+
+	if c.GRPC.Enabled {
+		check(c.GRPC.Addr != "" && c.GRPC.Addr != c.Server.Addr, "grpc.addr", "must be set and differ from server.addr, got %q", c.GRPC.Addr)
+		check(c.GRPC.CaptchaLength > 0 && c.GRPC.CaptchaLength <= 16, "grpc.captchaLength", "must be between 1 and 16, got %d", c.GRPC.CaptchaLength)
+		check(c.GRPC.CaptchaTTL > 0, "grpc.captchaTTL", "must be positive, got %s", time.Duration(c.GRPC.CaptchaTTL))
+		// gRPC 调用没有可签名的链接，启用签名时只能通过认证限制调用方
+		check(!c.Signing.Enabled || c.Auth.Enabled, "grpc.enabled", "requires auth to be enabled when signing is enabled")
+	}
+
+	oneOf("log.level", c.Log.Level, "debug", "info", "warn", "error")
+	oneOf("log.format", c.Log.Format, "json", "text")
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile 在临时目录中写入配置文件
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoad 测试 YAML 和 TOML 配置文件以及环境变量覆盖
+func TestLoad(t *testing.T) {
+	files := map[string]string{
+		"pix.yaml": "server:\n  addr: :9000\ndefaults:\n  qrcode:\n    size: 200\n    level: M\nfeatures:\n  batch: false\nlimits:\n  renderTimeout: 2s\n",
+		"pix.toml": "[server]\naddr = \":9000\"\n[defaults.qrcode]\nsize = 200\nlevel = \"M\"\n[features]\nbatch = false\n[limits]\nrenderTimeout = \"2s\"\n",
+	}
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("PIX_QRCODE_SIZE", "240")
+			cfg, err := Load(writeFile(t, name, content))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.Server.Addr != ":9000" || cfg.Defaults.QRCode.Level != "M" || cfg.Features.Batch {
+				t.Errorf("file values not applied: %+v", cfg)
+			}
+			if cfg.Limits.RenderTimeout != Duration(2*time.Second) {
+				t.Errorf("renderTimeout = %v, want 2s", time.Duration(cfg.Limits.RenderTimeout))
+			}
+			if cfg.Defaults.QRCode.Size != 240 {
+				t.Errorf("qrcode size = %d, want 240 from the environment", cfg.Defaults.QRCode.Size)
+			}
+			if cfg.Defaults.QRCode.Color != "000000" || !cfg.Features.Labels {
+				t.Errorf("unset values should keep their defaults: %+v", cfg)
+			}
+		})
+	}
+}
+
+// TestLoadErrors 测试未知配置项和无效的值
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name, content string
+		want          []string
+	}{
+		{"unknown.yaml", "server:\n  port: 80\n", []string{"port"}},
+		{"unknown.toml", "[server]\nport = 80\n", []string{"port"}},
+		{"invalid.yaml", "defaults:\n  qrcode:\n    level: X\n    color: nope\nlimits:\n  maxWidth: 100\n",
+			[]string{"defaults.qrcode.level", "defaults.qrcode.color", "defaults.qrcode.size 300x300 exceeds limits.maxWidth"}},
+		{"cors.yaml", "cors:\n  allowCredentials: true\n", []string{"cors.allowCredentials"}},
+		{"signing.yaml", "signing:\n  enabled: true\n", []string{"signing.keys must not be empty"}},
+		{"keys.toml", "[signing]\nkeys = [\"k1:short\", \"k2\"]\n", []string{"signing.keys secret of key \"k1\" must be at least 16 characters"}},
+		{"timeouts.yaml", "server:\n  writeTimeout: 5s\n  idleTimeout: -1s\n", []string{"server.writeTimeout must be longer than limits.renderTimeout", "server.idleTimeout must not be negative"}},
+		{"grpc.yaml", "grpc:\n  enabled: true\n  addr: :8080\n  captchaLength: 20\n", []string{"grpc.addr", "grpc.captchaLength"}},
+		{"ratelimit.yaml", "rateLimit:\n  keyBy: key\n", []string{"rateLimit.keyBy key requires auth"}},
+		{"grpcsigning.yaml", "grpc:\n  enabled: true\nsigning:\n  enabled: true\n  keys: [\"k1:0123456789abcdef\"]\n", []string{"grpc.enabled requires auth"}},
+		{"log.yaml", "log:\n  level: verbose\n  format: xml\n", []string{"log.level", "log.format"}},
+		{"pix.ini", "", []string{"unsupported format"}},
+	}
+	for _, tt := range tests {
+		_, err := Load(writeFile(t, tt.name, tt.content))
+		if err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+			continue
+		}
+		for _, want := range tt.want {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("%s: error %q does not mention %q", tt.name, err, want)
+			}
+		}
+	}
+}
+
+// TestLoadTenantsFile 测试从租户文件加载租户
+func TestLoadTenantsFile(t *testing.T) {
+	tenants := writeFile(t, "tenants.toml", "[[tenants]]\nname = \"ops\"\nkeys = [\"ops-0123456789abcdef\"]\nendpoints = [\"qrcode\"]\nmonthlyQuota = 1000\n")
+	path := writeFile(t, "pix.yaml", "auth:\n  enabled: true\n  tenantsFile: "+tenants+"\n  tenants:\n    - name: payments\n      keys: [pay-0123456789abcdef]\n")
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Auth.Tenants) != 2 || cfg.Auth.Tenants[1].Name != "ops" || cfg.Auth.Tenants[1].MonthlyQuota != 1000 {
+		t.Errorf("tenants = %+v", cfg.Auth.Tenants)
+	}
+
+	path = writeFile(t, "dup.yaml", "auth:\n  tenantsFile: "+tenants+"\n  tenants:\n    - name: ops\n      keys: [ops-0123456789abcdef]\n      endpoints: [fax]\n")
+	_, err = Load(path)
+	for _, want := range []string{"auth.tenants[1].name", "auth.tenants[1].keys[0] is already used", "auth.tenants[0].endpoints must be one of"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("error %v does not mention %q", err, want)
+		}
+	}
+}
+
+// TestApplyEnv 测试列表和布尔类型的环境变量
+func TestApplyEnv(t *testing.T) {
+	t.Setenv("PIX_CORS_ALLOW_ORIGINS", "https://a.example, https://b.example")
+	t.Setenv("PIX_CORS_ALLOW_CREDENTIALS", "true")
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.CORS.AllowOrigins; len(got) != 2 || got[1] != "https://b.example" || !cfg.CORS.AllowCredentials {
+		t.Errorf("cors = %+v", cfg.CORS)
+	}
+
+	t.Setenv("PIX_RENDER_TIMEOUT", "soon")
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "PIX_RENDER_TIMEOUT") {
+		t.Errorf("expected an error naming PIX_RENDER_TIMEOUT, got %v", err)
+	}
+	t.Setenv("PIX_RENDER_TIMEOUT", "500ms")
+
+	t.Setenv("PIX_MAX_WIDTH", "wide")
+	if _, err := Load(""); err == nil || !strings.Contains(err.Error(), "PIX_MAX_WIDTH") {
+		t.Errorf("expected an error naming PIX_MAX_WIDTH, got %v", err)
+	}
+}
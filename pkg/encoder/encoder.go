@@ -0,0 +1,209 @@
+package encoder
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"golang.org/x/image/bmp"
+)
+
+// Format 是输出图片格式
+type Format string
+
+const (
+	PNG  Format = "png"
+	JPEG Format = "jpeg"
+	GIF  Format = "gif"
+	WebP Format = "webp"
+	BMP  Format = "bmp"
+)
+
+// formats 是所有支持的格式，顺序即 Accept 权重相同时的优先顺序
+var formats = []Format{PNG, WebP, JPEG, GIF, BMP}
+
+// ContentType 返回格式对应的 MIME 类型
+func (f Format) ContentType() string {
+	return "image/" + string(f)
+}
+
+// Extension 返回格式对应的文件扩展名
+func (f Format) Extension() string {
+	if f == JPEG {
+		return ".jpg"
+	}
+	return "." + string(f)
+}
+
+// ParseFormat 解析格式名称，jpg 等同于 jpeg
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "jpg":
+		return JPEG, nil
+	case PNG, JPEG, GIF, WebP, BMP:
+		return f, nil
+	}
+	return "", errcode.Errorf(errcode.InvalidFormat, "unsupported format %q", s)
+}
+
+// Compression 是 PNG 压缩级别
+type Compression string
+
+const (
+	CompressionDefault Compression = "default"
+	CompressionNone    Compression = "none"
+	CompressionSpeed   Compression = "speed"
+	CompressionBest    Compression = "best"
+)
+
+// pngLevels 是压缩级别到 png 包压缩级别的映射
+var pngLevels = map[Compression]png.CompressionLevel{
+	"":                 png.DefaultCompression,
+	CompressionDefault: png.DefaultCompression,
+	CompressionNone:    png.NoCompression,
+	CompressionSpeed:   png.BestSpeed,
+	CompressionBest:    png.BestCompression,
+}
+
+// Options 是编码参数
+type Options struct {
+	Format      Format      // 输出格式，为空时为 PNG
+	Quality     int         // JPEG 质量 1-100，为 0 时为 90
+	Compression Compression // PNG 压缩级别
+}
+
+// Validate 检查编码参数
+func (o Options) Validate() error {
+	if o.Format != "" {
+		if _, err := ParseFormat(string(o.Format)); err != nil {
+			return err
+		}
+	}
+	if o.Quality < 0 || o.Quality > 100 {
+		return errcode.New(errcode.InvalidFormat, "quality must be between 1 and 100")
+	}
+	if _, ok := pngLevels[o.Compression]; !ok {
+		return errcode.Errorf(errcode.InvalidFormat, "invalid compression %q", o.Compression)
+	}
+	return nil
+}
+
+// Encode 按 opts 将图像编码写入 w
+func Encode(w io.Writer, img image.Image, opts Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	switch opts.Format {
+	case "", PNG:
+		enc := png.Encoder{CompressionLevel: pngLevels[opts.Compression]}
+		return enc.Encode(w, img)
+	case JPEG:
+		quality := opts.Quality
+		if quality == 0 {
+			quality = 90
+		}
+		return jpeg.Encode(w, flatten(img), &jpeg.Options{Quality: quality})
+	case GIF:
+		return gif.Encode(w, img, nil)
+	case WebP:
+		return encodeWebP(w, img)
+	case BMP:
+		return bmp.Encode(w, img)
+	}
+	return errcode.Errorf(errcode.InvalidFormat, "unsupported format %q", opts.Format)
+}
+
+// EncodeBytes 按 opts 将图像编码为字节切片
+func EncodeBytes(img image.Image, opts Options) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := Encode(&buf, img, opts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// DataURI 将编码后的图片数据转换为 data URI
+func DataURI(data []byte, format Format) string {
+	if format == "" {
+		format = PNG
+	}
+	return "data:" + format.ContentType() + ";base64," + base64.StdEncoding.EncodeToString(data)
+}
+
+// flatten 将透明图像合成到白色背景上，用于不支持透明的格式
+func flatten(img image.Image) image.Image {
+	flat := image.NewRGBA(img.Bounds())
+	draw.Draw(flat, flat.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(flat, flat.Bounds(), img, img.Bounds().Min, draw.Over)
+	return flat
+}
+
+// Negotiate 根据 Accept 请求头选择输出格式
+// 只考虑支持的图片类型和通配符，没有可接受的格式时返回 def
+// 通配符与具体类型权重相同时返回 def，浏览器 <img> 的 Accept 列出 avif、webp 等类型但同样接受 image/*，仍返回默认格式
+func Negotiate(accept string, def Format) Format {
+	if accept == "" {
+		return def
+	}
+
+	type candidate struct {
+		format Format
+		q      float64
+		order  int
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+		q := 1.0
+		for _, p := range strings.Split(params, ";") {
+			if k, v, ok := strings.Cut(strings.TrimSpace(p), "="); ok && strings.TrimSpace(k) == "q" {
+				if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+					q = f
+				}
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+
+		switch mediaType {
+		case "*/*", "image/*":
+			candidates = append(candidates, candidate{def, q, -1})
+		default:
+			sub, ok := strings.CutPrefix(mediaType, "image/")
+			if !ok {
+				continue
+			}
+			if f, err := ParseFormat(sub); err == nil {
+				for i, known := range formats {
+					if known == f {
+						candidates = append(candidates, candidate{f, q, i})
+					}
+				}
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return def
+	}
+
+	// 权重高者优先，权重相同时通配符对应的默认格式优先，其次按支持格式的顺序
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].q != candidates[j].q {
+			return candidates[i].q > candidates[j].q
+		}
+		return candidates[i].order < candidates[j].order
+	})
+	return candidates[0].format
+}
@@ -0,0 +1,26 @@
+package encoder
+
+import "testing"
+
+// TestNegotiate 测试根据 Accept 请求头选择输出格式
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   Format
+	}{
+		{"", PNG},
+		{"image/webp", WebP},
+		{"image/jpeg, image/webp", WebP},
+		{"image/webp,image/*;q=0.5", WebP},
+		{"image/jpeg;q=0.9,image/webp;q=0.8", JPEG},
+		{"image/avif,image/webp,image/apng,image/svg+xml,image/*,*/*;q=0.8", PNG},
+		{"*/*", PNG},
+		{"image/webp;q=0,*/*", PNG},
+		{"text/html", PNG},
+	}
+	for _, tt := range tests {
+		if got := Negotiate(tt.accept, PNG); got != tt.want {
+			t.Errorf("Negotiate(%q): expected %s, got %s", tt.accept, tt.want, got)
+		}
+	}
+}
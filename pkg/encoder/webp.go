@@ -0,0 +1,503 @@
+package encoder
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+)
+
+// VP8L 的字母表大小：绿色通道包含 256 个颜色值和 24 个长度前缀，没有颜色缓存
+const (
+	webpGreenAlphabet    = 256 + 24
+	webpColorAlphabet    = 256
+	webpDistanceAlphabet = 40
+	webpMaxCodeLength    = 15
+	webpMaxDimension     = 1 << 14
+)
+
+// webpCodeLengthOrder 是码长码的码长写入顺序
+var webpCodeLengthOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// VP8L 的变换类型
+const (
+	webpSubtractGreen = 2
+	webpColorIndexing = 3
+)
+
+// VP8L 反向引用的距离码：1 表示正上方像素，2 表示左侧像素
+const (
+	webpDistanceAbove = 1
+	webpDistanceLeft  = 2
+	webpMinMatch      = 3
+	webpMaxMatch      = 4096
+)
+
+// encodeWebP 将图像编码为无损 WebP（VP8L）
+// 不超过 256 种颜色时使用调色板变换并打包像素，否则使用减绿变换；
+// 反向引用只查找与左侧或正上方像素相同的连续区域，对纯色块和条码类图像效果较好
+func encodeWebP(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width <= 0 || height <= 0 || width > webpMaxDimension || height > webpMaxDimension {
+		return fmt.Errorf("webp: invalid image size %dx%d", width, height)
+	}
+
+	// VP8L 使用非预乘的 ARGB
+	argb := make([]uint32, 0, width*height)
+	alpha := false
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			alpha = alpha || c.A != 0xff
+			argb = append(argb, uint32(c.A)<<24|uint32(c.R)<<16|uint32(c.G)<<8|uint32(c.B))
+		}
+	}
+
+	bw := &bitWriter{}
+	bw.write(0x2f, 8)
+	bw.write(uint64(width-1), 14)
+	bw.write(uint64(height-1), 14)
+	if alpha {
+		bw.write(1, 1)
+	} else {
+		bw.write(0, 1)
+	}
+	bw.write(0, 3) // 版本号
+
+	if palette := buildPalette(argb, 256); palette != nil {
+		bw.write(1, 1)
+		bw.write(webpColorIndexing, 2)
+		bw.write(uint64(len(palette)-1), 8)
+
+		// 调色板按与前一项的差值存储为 1 行的图像
+		delta := make([]uint32, len(palette))
+		for i := range palette {
+			delta[i] = palette[i]
+			if i > 0 {
+				delta[i] = subPixels(palette[i], palette[i-1])
+			}
+		}
+		writeImageData(bw, delta, len(delta), false)
+		argb, width = bundlePixels(argb, palette, width, height)
+	} else {
+		bw.write(1, 1)
+		bw.write(webpSubtractGreen, 2)
+		for i, p := range argb {
+			g := p >> 8 & 0xff
+			r := (p>>16 - g) & 0xff
+			bl := (p - g) & 0xff
+			argb[i] = p&0xff00ff00 | r<<16 | bl
+		}
+	}
+	bw.write(0, 1) // 没有更多变换
+
+	writeImageData(bw, argb, width, true)
+	data := bw.flush()
+
+	// RIFF 容器，块长度为奇数时补一个字节
+	chunk := len(data)
+	padded := chunk + chunk&1
+	header := make([]byte, 20)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+8+padded))
+	copy(header[8:], "WEBPVP8L")
+	binary.LittleEndian.PutUint32(header[16:], uint32(chunk))
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if chunk&1 == 1 {
+		data = append(data, 0)
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// subPixels 逐通道计算 a - b，结果对 256 取模
+func subPixels(a, b uint32) uint32 {
+	var r uint32
+	for shift := 0; shift < 32; shift += 8 {
+		r |= ((a>>shift - b>>shift) & 0xff) << shift
+	}
+	return r
+}
+
+// buildPalette 返回图像中的所有颜色，颜色数超过 limit 时返回 nil
+func buildPalette(argb []uint32, limit int) []uint32 {
+	seen := map[uint32]bool{}
+	var palette []uint32
+	for _, p := range argb {
+		if !seen[p] {
+			if len(palette) == limit {
+				return nil
+			}
+			seen[p] = true
+			palette = append(palette, p)
+		}
+	}
+	return palette
+}
+
+// bundlePixels 将像素替换为调色板索引，颜色较少时把多个索引打包到一个像素的绿色通道中
+func bundlePixels(argb, palette []uint32, width, height int) ([]uint32, int) {
+	index := make(map[uint32]uint32, len(palette))
+	for i, p := range palette {
+		index[p] = uint32(i)
+	}
+
+	bits := 0
+	switch {
+	case len(palette) <= 2:
+		bits = 3
+	case len(palette) <= 4:
+		bits = 2
+	case len(palette) <= 16:
+		bits = 1
+	}
+	perPixel := 1 << bits
+	depth := 8 >> bits
+	packedWidth := (width + perPixel - 1) / perPixel
+
+	packed := make([]uint32, packedWidth*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*packedWidth + x>>bits
+			packed[i] |= index[argb[y*width+x]] << (8 + depth*(x&(perPixel-1)))
+		}
+	}
+	for i := range packed {
+		packed[i] |= 0xff000000
+	}
+	return packed, packedWidth
+}
+
+// webpToken 是一个字面像素或一次反向引用
+type webpToken struct {
+	pixel  uint32
+	length int // 大于 0 时为反向引用的长度
+	dist   int // 反向引用的距离码
+}
+
+// tokenize 贪心地查找与左侧或正上方像素相同的连续区域
+func tokenize(argb []uint32, width int) []webpToken {
+	var tokens []webpToken
+	for i := 0; i < len(argb); {
+		left, above := 0, 0
+		if i >= 1 {
+			for left < webpMaxMatch && i+left < len(argb) && argb[i+left] == argb[i+left-1] {
+				left++
+			}
+		}
+		if i >= width {
+			for above < webpMaxMatch && i+above < len(argb) && argb[i+above] == argb[i+above-width] {
+				above++
+			}
+		}
+
+		switch {
+		case above >= webpMinMatch && above >= left:
+			tokens = append(tokens, webpToken{length: above, dist: webpDistanceAbove})
+			i += above
+		case left >= webpMinMatch:
+			tokens = append(tokens, webpToken{length: left, dist: webpDistanceLeft})
+			i += left
+		default:
+			tokens = append(tokens, webpToken{pixel: argb[i]})
+			i++
+		}
+	}
+	return tokens
+}
+
+// prefixEncode 将长度或距离值编码为前缀符号和额外比特
+func prefixEncode(value int) (symbol int, extraBits uint, extra uint64) {
+	v := value - 1
+	if v < 4 {
+		return v, 0, 0
+	}
+	highest := 0
+	for n := v; n > 1; n >>= 1 {
+		highest++
+	}
+	second := v >> (highest - 1) & 1
+	extraBits = uint(highest - 1)
+	return 2*highest + second, extraBits, uint64(v & (1<<extraBits - 1))
+}
+
+// writeImageData 写入熵编码的图像数据，main 表示是否为主图像
+func writeImageData(bw *bitWriter, argb []uint32, width int, main bool) {
+	bw.write(0, 1) // 没有颜色缓存
+	if main {
+		bw.write(0, 1) // 没有元前缀编码
+	}
+
+	var tokens []webpToken
+	if main {
+		tokens = tokenize(argb, width)
+	} else {
+		for _, p := range argb {
+			tokens = append(tokens, webpToken{pixel: p})
+		}
+	}
+
+	// 统计每个字母表的频率并生成前缀编码
+	green := make([]int, webpGreenAlphabet)
+	red := make([]int, webpColorAlphabet)
+	blue := make([]int, webpColorAlphabet)
+	alphas := make([]int, webpColorAlphabet)
+	dist := make([]int, webpDistanceAlphabet)
+	for _, t := range tokens {
+		if t.length > 0 {
+			sym, _, _ := prefixEncode(t.length)
+			green[256+sym]++
+			sym, _, _ = prefixEncode(t.dist)
+			dist[sym]++
+			continue
+		}
+		green[t.pixel>>8&0xff]++
+		red[t.pixel>>16&0xff]++
+		blue[t.pixel&0xff]++
+		alphas[t.pixel>>24]++
+	}
+
+	codes := make([]*prefixCode, 0, 5)
+	for _, freq := range [][]int{green, red, blue, alphas, dist} {
+		codes = append(codes, writePrefixCode(bw, freq))
+	}
+
+	for _, t := range tokens {
+		if t.length > 0 {
+			sym, n, extra := prefixEncode(t.length)
+			codes[0].writeSymbol(bw, 256+sym)
+			bw.write(extra, n)
+			sym, n, extra = prefixEncode(t.dist)
+			codes[4].writeSymbol(bw, sym)
+			bw.write(extra, n)
+			continue
+		}
+		codes[0].writeSymbol(bw, int(t.pixel>>8&0xff))
+		codes[1].writeSymbol(bw, int(t.pixel>>16&0xff))
+		codes[2].writeSymbol(bw, int(t.pixel&0xff))
+		codes[3].writeSymbol(bw, int(t.pixel>>24))
+	}
+}
+
+// bitWriter 按 LSB 优先的顺序写入比特
+type bitWriter struct {
+	buf   []byte
+	acc   uint64
+	nbits uint
+}
+
+// write 写入 value 的低 n 位
+func (bw *bitWriter) write(value uint64, n uint) {
+	bw.acc |= value << bw.nbits
+	bw.nbits += n
+	for bw.nbits >= 8 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc >>= 8
+		bw.nbits -= 8
+	}
+}
+
+// flush 写出剩余的比特并返回全部数据
+func (bw *bitWriter) flush() []byte {
+	if bw.nbits > 0 {
+		bw.buf = append(bw.buf, byte(bw.acc))
+		bw.acc, bw.nbits = 0, 0
+	}
+	return bw.buf
+}
+
+// prefixCode 是一个规范前缀编码
+type prefixCode struct {
+	lengths []int
+	codes   []uint64 // 已按比特反转，可直接按 LSB 优先写入
+	single  bool     // 只有一个符号时写入符号不占用比特
+}
+
+// writeSymbol 写入一个符号
+func (pc *prefixCode) writeSymbol(bw *bitWriter, symbol int) {
+	if pc.single {
+		return
+	}
+	bw.write(pc.codes[symbol], uint(pc.lengths[symbol]))
+}
+
+// newPrefixCode 根据码长生成规范前缀编码
+func newPrefixCode(lengths []int) *prefixCode {
+	pc := &prefixCode{lengths: lengths, codes: make([]uint64, len(lengths))}
+
+	used := 0
+	var count [webpMaxCodeLength + 1]int
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+			used++
+		}
+	}
+	pc.single = used <= 1
+
+	var next [webpMaxCodeLength + 2]uint64
+	code := uint64(0)
+	for l := 1; l <= webpMaxCodeLength; l++ {
+		code = (code + uint64(count[l-1])) << 1
+		next[l] = code
+	}
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		pc.codes[s] = reverseBits(next[l], l)
+		next[l]++
+	}
+	return pc
+}
+
+// reverseBits 反转 code 的低 n 位
+func reverseBits(code uint64, n int) uint64 {
+	var r uint64
+	for i := 0; i < n; i++ {
+		r = r<<1 | code&1
+		code >>= 1
+	}
+	return r
+}
+
+// writePrefixCode 写入一个前缀编码并返回它
+// 不超过两个符号且符号值小于 256 时使用简单编码，否则使用普通编码
+func writePrefixCode(bw *bitWriter, freq []int) *prefixCode {
+	var symbols []int
+	for s, f := range freq {
+		if f > 0 {
+			symbols = append(symbols, s)
+		}
+	}
+	if len(symbols) == 0 {
+		symbols = []int{0}
+	}
+
+	if len(symbols) <= 2 && symbols[len(symbols)-1] < 256 {
+		bw.write(1, 1)
+		bw.write(uint64(len(symbols)-1), 1)
+		bw.write(1, 1) // 第一个符号使用 8 位
+		bw.write(uint64(symbols[0]), 8)
+		if len(symbols) == 2 {
+			bw.write(uint64(symbols[1]), 8)
+		}
+		lengths := make([]int, len(freq))
+		for _, s := range symbols {
+			lengths[s] = 1
+		}
+		return newPrefixCode(lengths)
+	}
+
+	lengths := huffmanLengths(freq, webpMaxCodeLength)
+
+	// 码长本身使用码长码编码，这里只使用 0-15 的字面码长
+	clFreq := make([]int, 19)
+	for _, l := range lengths {
+		clFreq[l]++
+	}
+	clLengths := huffmanLengths(clFreq, 7)
+	clCode := newPrefixCode(clLengths)
+
+	n := 4
+	for i, s := range webpCodeLengthOrder {
+		if clLengths[s] > 0 {
+			n = max(n, i+1)
+		}
+	}
+	bw.write(0, 1)
+	bw.write(uint64(n-4), 4)
+	for _, s := range webpCodeLengthOrder[:n] {
+		bw.write(uint64(clLengths[s]), 3)
+	}
+	bw.write(0, 1) // 码长数量等于字母表大小
+	for _, l := range lengths {
+		clCode.writeSymbol(bw, l)
+	}
+	return newPrefixCode(lengths)
+}
+
+// huffmanNode 是构建哈夫曼树时的节点
+type huffmanNode struct {
+	freq        int
+	symbol      int
+	left, right *huffmanNode
+}
+
+// huffmanHeap 是按频率排序的最小堆
+type huffmanHeap []*huffmanNode
+
+func (h huffmanHeap) Len() int { return len(h) }
+func (h huffmanHeap) Less(i, j int) bool {
+	if h[i].freq != h[j].freq {
+		return h[i].freq < h[j].freq
+	}
+	return h[i].symbol < h[j].symbol
+}
+func (h huffmanHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *huffmanHeap) Push(x interface{}) { *h = append(*h, x.(*huffmanNode)) }
+func (h *huffmanHeap) Pop() interface{} {
+	old := *h
+	n := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return n
+}
+
+// huffmanLengths 计算不超过 maxLen 的哈夫曼码长
+// 码长超限时将频率减半后重新计算，直到满足限制
+func huffmanLengths(freq []int, maxLen int) []int {
+	lengths := make([]int, len(freq))
+	f := append([]int(nil), freq...)
+	for {
+		h := &huffmanHeap{}
+		for s, n := range f {
+			if n > 0 {
+				*h = append(*h, &huffmanNode{freq: n, symbol: s})
+			}
+		}
+		switch h.Len() {
+		case 0:
+			return lengths
+		case 1:
+			lengths[(*h)[0].symbol] = 1
+			return lengths
+		}
+
+		heap.Init(h)
+		for h.Len() > 1 {
+			a := heap.Pop(h).(*huffmanNode)
+			b := heap.Pop(h).(*huffmanNode)
+			heap.Push(h, &huffmanNode{freq: a.freq + b.freq, symbol: min(a.symbol, b.symbol), left: a, right: b})
+		}
+
+		for i := range lengths {
+			lengths[i] = 0
+		}
+		deepest := 0
+		var walk func(n *huffmanNode, depth int)
+		walk = func(n *huffmanNode, depth int) {
+			if n.left == nil {
+				lengths[n.symbol] = depth
+				deepest = max(deepest, depth)
+				return
+			}
+			walk(n.left, depth+1)
+			walk(n.right, depth+1)
+		}
+		walk(heap.Pop(h).(*huffmanNode), 0)
+		if deepest <= maxLen {
+			return lengths
+		}
+
+		for i, n := range f {
+			if n > 0 {
+				f[i] = n/2 + 1
+			}
+		}
+	}
+}
@@ -0,0 +1,146 @@
+package encoder
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+// TestEncodeWebP 测试无损 WebP 编码后解码得到相同的像素
+func TestEncodeWebP(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	noise := image.NewNRGBA(image.Rect(0, 0, 37, 23))
+	r.Read(noise.Pix)
+
+	gradient := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			gradient.SetNRGBA(x, y, color.NRGBA{uint8(x * 4), uint8(y * 4), 128, 255})
+		}
+	}
+
+	twoColor := image.NewNRGBA(image.Rect(0, 0, 9, 9))
+	for i := range twoColor.Pix {
+		twoColor.Pix[i] = 255
+	}
+	twoColor.SetNRGBA(4, 4, color.NRGBA{0, 0, 0, 255})
+
+	solid := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	solid.SetNRGBA(0, 0, color.NRGBA{10, 20, 30, 40})
+
+	// 频率极不均衡，触发码长限制
+	skewed := image.NewNRGBA(image.Rect(0, 0, 256, 128))
+	for i := 0; i < len(skewed.Pix); i += 4 {
+		n := i / 4
+		v := uint8(0)
+		if n%2 == 0 {
+			v = uint8(n / 2 % 256 >> uint(n%7))
+		}
+		skewed.Pix[i], skewed.Pix[i+1], skewed.Pix[i+2], skewed.Pix[i+3] = v, v, v, 255
+	}
+
+	// 每个通道的 256 个值出现次数相同，码长码只有一个符号
+	allValues := image.NewNRGBA(image.Rect(0, 0, 256, 2))
+	for x := 0; x < 256; x++ {
+		allValues.SetNRGBA(x, 0, color.NRGBA{uint8(x), uint8(255 - x), uint8(x), 255})
+		allValues.SetNRGBA(x, 1, color.NRGBA{uint8(x), uint8(x), uint8(255 - x), 255})
+	}
+
+	// 少量颜色时会把多个调色板索引打包到一个像素中
+	fewColors := func(n, w, h int) *image.NRGBA {
+		img := image.NewNRGBA(image.Rect(0, 0, w, h))
+		for y := 0; y < h; y++ {
+			for x := 0; x < w; x++ {
+				k := uint8((x*7 + y*3) % n)
+				img.SetNRGBA(x, y, color.NRGBA{k * 20, 255 - k*10, k, 255 - k})
+			}
+		}
+		return img
+	}
+
+	// 大面积纯色，反向引用长度超过上限
+	large := image.NewNRGBA(image.Rect(0, 0, 300, 100))
+	for i := range large.Pix {
+		large.Pix[i] = 200
+	}
+
+	for name, img := range map[string]*image.NRGBA{
+		"allValues": allValues,
+		"fourColor": fewColors(4, 13, 7),
+		"tenColor":  fewColors(10, 11, 5),
+		"large":     large,
+		"noise":     noise,
+		"gradient":  gradient,
+		"twoColor":  twoColor,
+		"solid":     solid,
+		"skewed":    skewed,
+	} {
+		var buf bytes.Buffer
+		if err := encodeWebP(&buf, img); err != nil {
+			t.Fatalf("%s: encodeWebP: %v", name, err)
+		}
+		decoded, err := webp.Decode(&buf)
+		if err != nil {
+			t.Fatalf("%s: webp.Decode: %v", name, err)
+		}
+		if decoded.Bounds() != img.Bounds() {
+			t.Fatalf("%s: expected bounds %v, got %v", name, img.Bounds(), decoded.Bounds())
+		}
+		for y := 0; y < img.Bounds().Dy(); y++ {
+			for x := 0; x < img.Bounds().Dx(); x++ {
+				want := img.NRGBAAt(x, y)
+				got := color.NRGBAModel.Convert(decoded.At(x, y)).(color.NRGBA)
+				if want != got {
+					t.Fatalf("%s: pixel (%d, %d) expected %v, got %v", name, x, y, want, got)
+				}
+			}
+		}
+	}
+}
+
+// TestHuffmanLengths 测试码长限制
+func TestHuffmanLengths(t *testing.T) {
+	// 斐波那契频率会生成很深的哈夫曼树
+	freq := make([]int, 30)
+	a, b := 1, 1
+	for i := range freq {
+		freq[i] = a
+		a, b = b, a+b
+	}
+	lengths := huffmanLengths(freq, 15)
+
+	// 码长不超过限制，且满足 Kraft 等式
+	kraft := 0.0
+	for _, l := range lengths {
+		if l < 1 || l > 15 {
+			t.Fatalf("huffmanLengths: invalid length %d", l)
+		}
+		kraft += 1 / float64(int(1)<<l)
+	}
+	if kraft != 1 {
+		t.Errorf("huffmanLengths: expected complete code, got Kraft sum %v", kraft)
+	}
+}
+
+// TestPrefixEncode 测试长度和距离的前缀编码可以按规范还原
+func TestPrefixEncode(t *testing.T) {
+	for value := 1; value <= 4096; value++ {
+		sym, n, extra := prefixEncode(value)
+		got := sym + 1
+		if sym >= 4 {
+			bits := (sym - 2) >> 1
+			if uint(bits) != n {
+				t.Fatalf("prefixEncode(%d): expected %d extra bits, got %d", value, bits, n)
+			}
+			got = (2+sym&1)<<bits + int(extra) + 1
+		}
+		if got != value {
+			t.Fatalf("prefixEncode(%d): decoded as %d", value, got)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+// Package errcode 定义生成器共用的错误码
+// 错误码是稳定的字符串，HTTP 和 gRPC 接口按错误码返回状态码，调用方可以据此区分错误
+package errcode
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code 是错误码
+type Code string
+
+// 错误码，新增错误码时同时在 handler 中配置 HTTP 状态码
+const (
+	InvalidRequest   Code = "INVALID_REQUEST"    // 参数格式错误或不满足约束
+	InvalidColor     Code = "INVALID_COLOR"      // 颜色无法解析
+	InvalidSize      Code = "INVALID_SIZE"       // 尺寸或边距无效，或放不下内容
+	InvalidLevel     Code = "INVALID_LEVEL"      // 二维码错误校验级别无效
+	InvalidText      Code = "INVALID_TEXT"       // 文字为空或包含不支持的字符
+	InvalidMarkup    Code = "INVALID_MARKUP"     // 富文本语法错误
+	InvalidFormat    Code = "INVALID_FORMAT"     // 输出格式或编码参数无效
+	PayloadTooLong   Code = "PAYLOAD_TOO_LONG"   // 文字或二维码内容超过上限
+	SizeTooLarge     Code = "SIZE_TOO_LARGE"     // 图片尺寸超过上限
+	FontMissing      Code = "FONT_MISSING"       // 没有设置字体或字体不存在
+	TemplateNotFound Code = "TEMPLATE_NOT_FOUND" // 模板不存在
+	RenderTimeout    Code = "RENDER_TIMEOUT"     // 渲染超时
+	Busy             Code = "BUSY"               // 没有空闲的渲染槽位
+	RateLimited      Code = "RATE_LIMITED"       // 请求过于频繁
+	MissingKey       Code = "MISSING_API_KEY"    // 没有 API key
+	InvalidKey       Code = "INVALID_API_KEY"    // API key 无效
+	EndpointDenied   Code = "ENDPOINT_DENIED"    // 租户无权使用接口
+	QuotaExceeded    Code = "QUOTA_EXCEEDED"     // 每月配额已用尽
+	InvalidSignature Code = "INVALID_SIGNATURE"  // 签名缺失、过期或无效
+	NotFound         Code = "NOT_FOUND"          // 资源不存在
+	Internal         Code = "INTERNAL"           // 服务内部错误
+)
+
+// Error 是带有错误码的错误
+type Error struct {
+	Code    Code   // 错误码
+	Message string // 错误信息
+	Err     error  // 包装的错误，可以为 nil
+}
+
+// New 返回带有错误码的错误，通常用于定义包级的错误变量
+func New(code Code, msg string) *Error {
+	return &Error{Code: code, Message: msg}
+}
+
+// Errorf 按格式返回带有错误码的错误，format 中的 %w 会被包装
+func Errorf(code Code, format string, args ...interface{}) *Error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Code: code, Message: err.Error(), Err: errors.Unwrap(err)}
+}
+
+func (e *Error) Error() string { return e.Message }
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Of 返回错误链中最外层的错误码，没有错误码时返回 InvalidRequest，err 为 nil 时返回空字符串
+func Of(err error) Code {
+	if err == nil {
+		return ""
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return InvalidRequest
+}
@@ -0,0 +1,43 @@
+package errcode
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+var errMissing = errors.New("missing")
+
+// TestOf 测试从错误链中取出最外层的错误码
+func TestOf(t *testing.T) {
+	base := New(InvalidColor, "invalid color")
+	tests := []struct {
+		err  error
+		want Code
+	}{
+		{nil, ""},
+		{errors.New("plain"), InvalidRequest},
+		{base, InvalidColor},
+		{fmt.Errorf("layer 1: %w", base), InvalidColor},
+		{Errorf(Internal, "render failed: %w", base), Internal},
+	}
+	for _, tt := range tests {
+		if got := Of(tt.err); got != tt.want {
+			t.Errorf("Of(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+// TestErrorf 测试 Errorf 包装 %w 指向的错误
+func TestErrorf(t *testing.T) {
+	err := Errorf(FontMissing, "load %s: %w", "a.ttf", errMissing)
+	if err.Error() != "load a.ttf: missing" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+	if !errors.Is(err, errMissing) {
+		t.Error("Errorf() does not wrap the %w argument")
+	}
+	if Errorf(FontMissing, "no wrap").Unwrap() != nil {
+		t.Error("Errorf() without %w wraps an error")
+	}
+}
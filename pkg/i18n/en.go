@@ -0,0 +1,45 @@
+package i18n
+
+// en 是英文的消息目录
+var en = map[string]string{
+	// 错误码
+	"INVALID_REQUEST":    "invalid request",
+	"INVALID_COLOR":      "invalid color",
+	"INVALID_SIZE":       "invalid size",
+	"INVALID_LEVEL":      "invalid QR code error correction level",
+	"INVALID_TEXT":       "invalid text",
+	"INVALID_MARKUP":     "invalid markup",
+	"INVALID_FORMAT":     "invalid output format",
+	"PAYLOAD_TOO_LONG":   "content too long",
+	"SIZE_TOO_LARGE":     "image size exceeds limits",
+	"FONT_MISSING":       "font not found",
+	"TEMPLATE_NOT_FOUND": "template not found",
+	"RENDER_TIMEOUT":     "render time limit exceeded",
+	"BUSY":               "server busy, retry later",
+	"RATE_LIMITED":       "rate limit exceeded",
+	"MISSING_API_KEY":    "missing API key",
+	"INVALID_API_KEY":    "invalid API key",
+	"ENDPOINT_DENIED":    "endpoint not allowed for this API key",
+	"QUOTA_EXCEEDED":     "monthly quota exceeded",
+	"INVALID_SIGNATURE":  "invalid or expired signature",
+	"NOT_FOUND":          "not found",
+	"INTERNAL":           "internal error",
+
+	// 字段错误原因
+	"reason.required":    "is required",
+	"reason.min":         "must be at least %s",
+	"reason.max":         "must be at most %s",
+	"reason.gt":          "must be greater than %s",
+	"reason.oneof":       "must be one of: %s",
+	"reason.color":       "must be a color name or hex value",
+	"reason.type":        "must be of type %s",
+	"reason.failed":      "failed on %s",
+	"reason.maxSize":     "must be at most %d",
+	"reason.maxArea":     "%dx%d exceeds the maximum area of %d pixels",
+	"reason.maxChars":    "must be at most %d characters",
+	"reason.maxBytes":    "must be at most %d bytes",
+	"reason.maxFontSize": "font size %.0f exceeds the maximum of %d",
+
+	// 默认文字
+	"image.tipText": "Check that the address in the image matches the copied address before transferring",
+}
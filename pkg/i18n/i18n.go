@@ -0,0 +1,74 @@
+// Package i18n 提供中文和英文的错误信息和默认文字
+// 语言按 lang 参数或 Accept-Language 请求头选择，没有匹配的语言时使用简体中文
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+// Lang 是语言标签
+type Lang string
+
+// 支持的语言
+const (
+	ZhCN Lang = "zh-CN" // 简体中文
+	En   Lang = "en"    // 英文
+)
+
+// Default 是没有匹配的语言时使用的语言
+const Default = ZhCN
+
+// Langs 是支持的语言，按优先级排列
+var Langs = []Lang{ZhCN, En}
+
+// catalogs 是各语言的消息目录，键为错误码或以点分隔的消息名
+var catalogs = map[Lang]map[string]string{
+	ZhCN: zhCN,
+	En:   en,
+}
+
+// matcher 将请求的语言匹配到支持的语言，zh-TW 等中文变体也匹配简体中文
+var matcher = language.NewMatcher([]language.Tag{language.SimplifiedChinese, language.English})
+
+// Match 按 Accept-Language 请求头的权重选择语言，没有匹配时返回 Default
+func Match(accept string) Lang {
+	tags, _, err := language.ParseAcceptLanguage(accept)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+	_, i, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return Langs[i]
+}
+
+// Parse 解析 lang 参数，支持 zh、zh-CN、en、en-US 等形式，不支持的语言返回 false
+func Parse(s string) (Lang, bool) {
+	tag, err := language.Parse(s)
+	if err != nil {
+		return "", false
+	}
+	_, i, confidence := matcher.Match(tag)
+	if confidence == language.No {
+		return "", false
+	}
+	return Langs[i], true
+}
+
+// Text 返回消息的译文，args 按 fmt.Sprintf 格式化
+// 当前语言没有该消息时使用 Default 的译文，都没有时返回 key
+func (l Lang) Text(key string, args ...interface{}) string {
+	msg, ok := catalogs[l][key]
+	if !ok {
+		if msg, ok = catalogs[Default][key]; !ok {
+			msg = key
+		}
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
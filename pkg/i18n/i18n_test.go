@@ -0,0 +1,68 @@
+package i18n
+
+import "testing"
+
+// TestMatch 测试按 Accept-Language 的权重选择语言
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		accept string
+		want   Lang
+	}{
+		{"", ZhCN},
+		{"en-US,en;q=0.9", En},
+		{"zh-TW,zh;q=0.9,en;q=0.8", ZhCN},
+		{"fr-FR,en;q=0.5,zh;q=0.3", En},
+		{"fr-FR", ZhCN},
+		{"en;q=0.2,zh-CN;q=0.8", ZhCN},
+		{";;invalid", ZhCN},
+	}
+	for _, tt := range tests {
+		if got := Match(tt.accept); got != tt.want {
+			t.Errorf("Match(%q) = %s, want %s", tt.accept, got, tt.want)
+		}
+	}
+}
+
+// TestParse 测试解析 lang 参数
+func TestParse(t *testing.T) {
+	for s, want := range map[string]Lang{"en": En, "en-GB": En, "zh": ZhCN, "zh-CN": ZhCN} {
+		if got, ok := Parse(s); !ok || got != want {
+			t.Errorf("Parse(%q) = %s, %v, want %s", s, got, ok, want)
+		}
+	}
+	for _, s := range []string{"", "fr", "!!"} {
+		if got, ok := Parse(s); ok {
+			t.Errorf("Parse(%q) = %s, want no match", s, got)
+		}
+	}
+}
+
+// TestText 测试格式化译文和缺少译文时的回退
+func TestText(t *testing.T) {
+	if got := En.Text("reason.maxChars", 10); got != "must be at most 10 characters" {
+		t.Errorf("En.Text() = %q", got)
+	}
+	if got := ZhCN.Text("reason.maxChars", 10); got != "不能超过 10 个字符" {
+		t.Errorf("ZhCN.Text() = %q", got)
+	}
+	if got := Lang("ja").Text("NOT_FOUND"); got != "资源不存在" {
+		t.Errorf("unsupported language: got %q", got)
+	}
+	if got := En.Text("no.such.key"); got != "no.such.key" {
+		t.Errorf("missing key: got %q", got)
+	}
+}
+
+// TestCatalogs 测试每种语言的目录包含相同的消息
+func TestCatalogs(t *testing.T) {
+	for _, lang := range Langs {
+		for key := range catalogs[Default] {
+			if _, ok := catalogs[lang][key]; !ok {
+				t.Errorf("%s: missing %s", lang, key)
+			}
+		}
+		if len(catalogs[lang]) != len(catalogs[Default]) {
+			t.Errorf("%s has %d messages, %s has %d", lang, len(catalogs[lang]), Default, len(catalogs[Default]))
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package i18n
+
+// zhCN 是简体中文的消息目录
+var zhCN = map[string]string{
+	// 错误码
+	"INVALID_REQUEST":    "请求参数无效",
+	"INVALID_COLOR":      "颜色无效",
+	"INVALID_SIZE":       "尺寸无效",
+	"INVALID_LEVEL":      "二维码容错级别无效",
+	"INVALID_TEXT":       "文字无效",
+	"INVALID_MARKUP":     "富文本格式错误",
+	"INVALID_FORMAT":     "输出格式无效",
+	"PAYLOAD_TOO_LONG":   "内容过长",
+	"SIZE_TOO_LARGE":     "图片尺寸超过上限",
+	"FONT_MISSING":       "字体不存在",
+	"TEMPLATE_NOT_FOUND": "模板不存在",
+	"RENDER_TIMEOUT":     "渲染超时",
+	"BUSY":               "服务繁忙，请稍后重试",
+	"RATE_LIMITED":       "请求过于频繁",
+	"MISSING_API_KEY":    "缺少 API key",
+	"INVALID_API_KEY":    "API key 无效",
+	"ENDPOINT_DENIED":    "无权使用该接口",
+	"QUOTA_EXCEEDED":     "本月配额已用尽",
+	"INVALID_SIGNATURE":  "签名无效或已过期",
+	"NOT_FOUND":          "资源不存在",
+	"INTERNAL":           "服务内部错误",
+
+	// 字段错误原因
+	"reason.required":    "不能为空",
+	"reason.min":         "不能小于 %s",
+	"reason.max":         "不能大于 %s",
+	"reason.gt":          "必须大于 %s",
+	"reason.oneof":       "必须是以下值之一：%s",
+	"reason.color":       "必须是颜色名或 16 进制颜色值",
+	"reason.type":        "类型必须是 %s",
+	"reason.failed":      "不满足 %s 约束",
+	"reason.maxSize":     "不能大于 %d",
+	"reason.maxArea":     "%dx%d 超过了最大面积 %d 像素",
+	"reason.maxChars":    "不能超过 %d 个字符",
+	"reason.maxBytes":    "不能超过 %d 字节",
+	"reason.maxFontSize": "字号 %.0f 超过了最大字号 %d",
+
+	// 默认文字
+	"image.tipText": "请通过图片和复制的地址核对一样后进行转账",
+}
@@ -0,0 +1,118 @@
+package identicon
+
+import (
+	"crypto/sha256"
+	"image"
+	"image/color"
+	"image/draw"
+)
+
+// Grid 是图标的格子数，图标左右对称
+const Grid = 5
+
+// Identicon 是由数据哈希确定的对称图标
+type Identicon struct {
+	Cells [Grid][Grid]bool // 每个格子是否填充
+	Color color.RGBA       // 前景色
+}
+
+// New 根据数据的 SHA-256 哈希生成图标，相同数据总是得到相同的图标
+func New(data []byte) *Identicon {
+	sum := sha256.Sum256(data)
+	icon := &Identicon{Color: hashColor(sum)}
+
+	// 只计算左半边（含中间列），右半边镜像
+	half := (Grid + 1) / 2
+	for y := 0; y < Grid; y++ {
+		for x := 0; x < half; x++ {
+			bit := y*half + x
+			on := sum[4+bit/8]>>(bit%8)&1 == 1
+			icon.Cells[y][x] = on
+			icon.Cells[y][Grid-1-x] = on
+		}
+	}
+	return icon
+}
+
+// hashColor 根据哈希值选取饱和度和亮度适中的颜色
+func hashColor(sum [sha256.Size]byte) color.RGBA {
+	hue := float64(uint16(sum[0])<<8|uint16(sum[1])) / 65536 * 360
+	sat := 0.45 + float64(sum[2])/255*0.2
+	light := 0.4 + float64(sum[3])/255*0.15
+	return HSL(hue, sat, light)
+}
+
+// HSL 将 HSL 颜色转换为 RGBA，hue 取值 0-360，sat 和 light 取值 0-1
+func HSL(hue, sat, light float64) color.RGBA {
+	c := (1 - abs(2*light-1)) * sat
+	h := hue / 60
+	x := c * (1 - abs(mod2(h)-1))
+	var r, g, b float64
+	switch {
+	case h < 1:
+		r, g, b = c, x, 0
+	case h < 2:
+		r, g, b = x, c, 0
+	case h < 3:
+		r, g, b = 0, c, x
+	case h < 4:
+		r, g, b = 0, x, c
+	case h < 5:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	m := light - c/2
+	return color.RGBA{
+		R: uint8((r+m)*255 + 0.5),
+		G: uint8((g+m)*255 + 0.5),
+		B: uint8((b+m)*255 + 0.5),
+		A: 255,
+	}
+}
+
+// abs 返回浮点数的绝对值
+func abs(x float64) float64 {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// mod2 返回 x 对 2 取模的结果
+func mod2(x float64) float64 {
+	return x - 2*float64(int(x/2))
+}
+
+// Draw 将图标绘制到 dst 的 rect 范围内，bg 为 nil 时不绘制背景
+func (icon *Identicon) Draw(dst draw.Image, rect image.Rectangle, bg color.Color) {
+	if bg != nil {
+		draw.Draw(dst, rect, image.NewUniform(bg), image.Point{}, draw.Src)
+	}
+
+	// 四周各留半个格子的边距
+	size := min(rect.Dx(), rect.Dy())
+	cell := size / (Grid + 1)
+	if cell <= 0 {
+		return
+	}
+	left := rect.Min.X + (rect.Dx()-cell*Grid)/2
+	top := rect.Min.Y + (rect.Dy()-cell*Grid)/2
+
+	fg := image.NewUniform(icon.Color)
+	for y := 0; y < Grid; y++ {
+		for x := 0; x < Grid; x++ {
+			if icon.Cells[y][x] {
+				r := image.Rect(left+x*cell, top+y*cell, left+(x+1)*cell, top+(y+1)*cell)
+				draw.Draw(dst, r, fg, image.Point{}, draw.Src)
+			}
+		}
+	}
+}
+
+// Image 生成 size x size 的白底图标图片
+func (icon *Identicon) Image(size int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	icon.Draw(img, img.Bounds(), color.White)
+	return img
+}
@@ -0,0 +1,66 @@
+package identicon
+
+import (
+	"image/color"
+	"testing"
+)
+
+// TestNew 测试图标的确定性和对称性
+func TestNew(t *testing.T) {
+	a := New([]byte("TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"))
+	b := New([]byte("TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t"))
+	c := New([]byte("TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6u"))
+
+	if *a != *b {
+		t.Errorf("New: expected identical icons for identical data")
+	}
+	if *a == *c {
+		t.Errorf("New: expected different icons for different data")
+	}
+	for y := 0; y < Grid; y++ {
+		for x := 0; x < Grid; x++ {
+			if a.Cells[y][x] != a.Cells[y][Grid-1-x] {
+				t.Fatalf("New: expected symmetric cells at row %d", y)
+			}
+		}
+	}
+}
+
+// TestHSL 测试 HSL 颜色转换
+func TestHSL(t *testing.T) {
+	tests := []struct {
+		h, s, l float64
+		want    color.RGBA
+	}{
+		{0, 1, 0.5, color.RGBA{255, 0, 0, 255}},
+		{120, 1, 0.5, color.RGBA{0, 255, 0, 255}},
+		{240, 1, 0.5, color.RGBA{0, 0, 255, 255}},
+		{0, 0, 1, color.RGBA{255, 255, 255, 255}},
+	}
+	for _, tt := range tests {
+		if got := HSL(tt.h, tt.s, tt.l); got != tt.want {
+			t.Errorf("HSL(%v, %v, %v): expected %v, got %v", tt.h, tt.s, tt.l, tt.want, got)
+		}
+	}
+}
+
+// TestImage 测试图标绘制
+func TestImage(t *testing.T) {
+	icon := New([]byte("seed"))
+	img := icon.Image(60)
+	if img.Bounds().Dx() != 60 || img.Bounds().Dy() != 60 {
+		t.Fatalf("Image: expected 60x60, got %v", img.Bounds())
+	}
+
+	var filled int
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 60; x++ {
+			if img.RGBAAt(x, y) == icon.Color {
+				filled++
+			}
+		}
+	}
+	if filled == 0 {
+		t.Errorf("Image: expected some cells to be filled")
+	}
+}
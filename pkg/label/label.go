@@ -0,0 +1,255 @@
+// Package label 将二维码或条码连同说明文字排版到 A4 或 Letter 标签纸上并输出 PDF
+package label
+
+import (
+	"fmt"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/bitqiu/pix-gen/pkg/barcode"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/pdf"
+	"github.com/bitqiu/pix-gen/pkg/qrcode"
+)
+
+// Sheet 描述标签纸的版式，长度单位为毫米
+// LabelWidth 和 LabelHeight 为 0 时根据页边距和间距平分页面
+type Sheet struct {
+	Page         string  `json:"page" doc:"纸张：a4 或 letter"`
+	Columns      int     `json:"columns" doc:"每行标签数"`
+	Rows         int     `json:"rows" doc:"每页行数"`
+	MarginTop    float64 `json:"marginTop" doc:"上边距"`
+	MarginBottom float64 `json:"marginBottom" doc:"下边距"`
+	MarginLeft   float64 `json:"marginLeft" doc:"左边距"`
+	MarginRight  float64 `json:"marginRight" doc:"右边距"`
+	GapX         float64 `json:"gapX" doc:"列间距"`
+	GapY         float64 `json:"gapY" doc:"行间距"`
+	LabelWidth   float64 `json:"labelWidth,omitempty" doc:"标签宽度"`
+	LabelHeight  float64 `json:"labelHeight,omitempty" doc:"标签高度"`
+}
+
+// Presets 是常见的 Avery 标签纸版式
+var Presets = map[string]Sheet{
+	"avery-l7160": {Page: "a4", Columns: 3, Rows: 7, MarginTop: 15.15, MarginLeft: 7.25, GapX: 2.5, LabelWidth: 63.5, LabelHeight: 38.1},
+	"avery-l7163": {Page: "a4", Columns: 2, Rows: 7, MarginTop: 15.15, MarginLeft: 4.65, GapX: 2.5, LabelWidth: 99.1, LabelHeight: 38.1},
+	"avery-l7651": {Page: "a4", Columns: 5, Rows: 13, MarginTop: 10.7, MarginLeft: 4.75, GapX: 2.5, LabelWidth: 38.1, LabelHeight: 21.2},
+	"avery-5160":  {Page: "letter", Columns: 3, Rows: 10, MarginTop: 12.7, MarginLeft: 4.76, GapX: 3.18, LabelWidth: 66.68, LabelHeight: 25.4},
+	"avery-5163":  {Page: "letter", Columns: 2, Rows: 5, MarginTop: 12.7, MarginLeft: 3.97, GapX: 4.76, LabelWidth: 101.6, LabelHeight: 50.8},
+	"avery-5167":  {Page: "letter", Columns: 4, Rows: 20, MarginTop: 12.7, MarginLeft: 7.62, GapX: 7.62, LabelWidth: 44.45, LabelHeight: 12.7},
+}
+
+// PageSize 返回纸张尺寸，单位为点
+func (s Sheet) PageSize() (float64, float64, error) {
+	switch strings.ToLower(s.Page) {
+	case "", "a4":
+		return pdf.A4Width, pdf.A4Height, nil
+	case "letter":
+		return pdf.LetterWidth, pdf.LetterHeight, nil
+	}
+	return 0, 0, fmt.Errorf("unsupported page %q", s.Page)
+}
+
+// LabelSize 返回标签尺寸，单位为毫米
+func (s Sheet) LabelSize() (float64, float64) {
+	w, h := s.LabelWidth, s.LabelHeight
+	pw, ph, _ := s.PageSize()
+	if w == 0 && s.Columns > 0 {
+		w = (pw/pdf.MM - s.MarginLeft - s.MarginRight - s.GapX*float64(s.Columns-1)) / float64(s.Columns)
+	}
+	if h == 0 && s.Rows > 0 {
+		h = (ph/pdf.MM - s.MarginTop - s.MarginBottom - s.GapY*float64(s.Rows-1)) / float64(s.Rows)
+	}
+	return w, h
+}
+
+// Validate 检查版式是否有效，标签不能超出纸张
+func (s Sheet) Validate() error {
+	pw, ph, err := s.PageSize()
+	if err != nil {
+		return err
+	}
+	if s.Columns <= 0 || s.Rows <= 0 {
+		return fmt.Errorf("columns and rows must be positive")
+	}
+	if s.MarginTop < 0 || s.MarginBottom < 0 || s.MarginLeft < 0 || s.MarginRight < 0 || s.GapX < 0 || s.GapY < 0 {
+		return fmt.Errorf("margins and gaps must not be negative")
+	}
+	w, h := s.LabelSize()
+	if w <= 0 || h <= 0 {
+		return fmt.Errorf("margins and gaps leave no room for labels")
+	}
+	right := s.MarginLeft + w*float64(s.Columns) + s.GapX*float64(s.Columns-1)
+	bottom := s.MarginTop + h*float64(s.Rows) + s.GapY*float64(s.Rows-1)
+	// 允许 0.5 毫米的舍入误差
+	if right > pw/pdf.MM+0.5 || bottom > ph/pdf.MM+0.5 {
+		return fmt.Errorf("labels do not fit on the page")
+	}
+	return nil
+}
+
+// Item 是一个标签
+type Item struct {
+	Type    string `json:"type" doc:"符号类型：qrcode 或 barcode"`
+	Text    string `json:"text" doc:"编码的内容"`
+	Caption string `json:"caption,omitempty" doc:"说明文字，为空时使用 text"`
+}
+
+// Options 是生成标签页的参数
+type Options struct {
+	Sheet   Sheet        // 标签纸版式
+	Font    []byte       // 说明文字使用的 TrueType 字体数据
+	Level   qrcode.Level // 二维码错误校验级别，为空时为 M
+	Padding float64      // 标签内边距，毫米
+	Skip    int          // 第一页跳过的标签数，用于继续使用用过一部分的标签纸
+	Border  bool         // 是否绘制标签边框，便于对齐打印
+}
+
+// Render 将标签排版为 PDF 写入 w，标签按行从左到右排列，一页排满后换页
+func Render(w io.Writer, items []Item, opts Options) error {
+	if len(items) == 0 {
+		return fmt.Errorf("no labels")
+	}
+	if err := opts.Sheet.Validate(); err != nil {
+		return err
+	}
+	if opts.Skip < 0 || opts.Padding < 0 {
+		return fmt.Errorf("skip and padding must not be negative")
+	}
+	if opts.Level == "" {
+		opts.Level = qrcode.LevelMedium
+	}
+
+	doc := pdf.New()
+	font, err := doc.AddFont(opts.Font)
+	if err != nil {
+		return err
+	}
+
+	sheet := opts.Sheet
+	pw, ph, _ := sheet.PageSize()
+	lw, lh := sheet.LabelSize()
+	perPage := sheet.Columns * sheet.Rows
+
+	var page *pdf.Page
+	for i, item := range items {
+		slot := (i + opts.Skip) % perPage
+		if page == nil || slot == 0 {
+			page = doc.AddPage(pw, ph)
+			page.SetColor(color.Black)
+		}
+		row, col := slot/sheet.Columns, slot%sheet.Columns
+		box := rect{
+			x: (sheet.MarginLeft + float64(col)*(lw+sheet.GapX)) * pdf.MM,
+			y: (sheet.MarginTop + float64(row)*(lh+sheet.GapY)) * pdf.MM,
+			w: lw * pdf.MM,
+			h: lh * pdf.MM,
+		}
+		if opts.Border {
+			page.StrokeRect(box.x, box.y, box.w, box.h, 0.25)
+		}
+		if err := drawLabel(page, font, box.inset(opts.Padding*pdf.MM), item, opts.Level); err != nil {
+			return errcode.Errorf(errcode.Of(err), "label %d: %w", i+1, err)
+		}
+	}
+
+	_, err = doc.WriteTo(w)
+	return err
+}
+
+// rect 是页面上的矩形区域，单位为点
+type rect struct {
+	x, y, w, h float64
+}
+
+// inset 返回四边各向内收缩 d 的矩形
+func (r rect) inset(d float64) rect {
+	return rect{r.x + d, r.y + d, r.w - 2*d, r.h - 2*d}
+}
+
+// drawLabel 在 box 内绘制一个标签
+// 宽标签上的二维码放在左侧，说明文字在右侧；其他情况符号在上，说明文字在下
+func drawLabel(page *pdf.Page, font *pdf.Font, box rect, item Item, level qrcode.Level) error {
+	if box.w <= 0 || box.h <= 0 {
+		return fmt.Errorf("padding leaves no room for the label")
+	}
+	caption := item.Caption
+	if caption == "" {
+		caption = item.Text
+	}
+
+	switch item.Type {
+	case "", "qrcode":
+		bitmap, err := qrcode.Bitmap(item.Text, level)
+		if err != nil {
+			return err
+		}
+		if box.w >= 1.6*box.h {
+			side := box.h
+			drawQRCode(page, bitmap, rect{box.x, box.y, side, side})
+			textBox := rect{box.x + side, box.y, box.w - side, box.h}
+			drawCaption(page, font, textBox, caption, box.h*0.22)
+			return nil
+		}
+		size := captionSize(box)
+		side := min(box.w, box.h-size*1.3)
+		if side <= 0 {
+			return fmt.Errorf("label is too small for a QR code")
+		}
+		drawQRCode(page, bitmap, rect{box.x + (box.w-side)/2, box.y, side, side})
+		drawCaption(page, font, rect{box.x, box.y + side, box.w, box.h - side}, caption, size)
+	case "barcode":
+		b, err := barcode.Code128(item.Text)
+		if err != nil {
+			return err
+		}
+		size := captionSize(box)
+		barHeight := box.h - size*1.3
+		module := box.w / float64(len(b.Modules))
+		for _, bar := range b.Bars() {
+			page.Rect(box.x+float64(bar[0])*module, box.y, float64(bar[1])*module, barHeight)
+		}
+		drawCaption(page, font, rect{box.x, box.y + barHeight, box.w, box.h - barHeight}, caption, size)
+	default:
+		return fmt.Errorf("unsupported label type %q", item.Type)
+	}
+	return nil
+}
+
+// captionSize 返回符号下方说明文字的字号
+func captionSize(box rect) float64 {
+	return min(box.h*0.16, 10)
+}
+
+// drawQRCode 在正方形 box 内绘制二维码，四周保留两个模块的空白区
+// 同一行相邻的深色模块合并为一个矩形
+func drawQRCode(page *pdf.Page, bitmap [][]bool, box rect) {
+	n := len(bitmap)
+	module := box.w / float64(n+4)
+	x0, y0 := box.x+2*module, box.y+2*module
+	for r, row := range bitmap {
+		for c := 0; c < len(row); c++ {
+			if !row[c] {
+				continue
+			}
+			start := c
+			for c < len(row) && row[c] {
+				c++
+			}
+			page.Rect(x0+float64(start)*module, y0+float64(r)*module, float64(c-start)*module, module)
+		}
+	}
+}
+
+// drawCaption 在 box 内水平和垂直居中绘制一行文字，过宽时缩小字号
+func drawCaption(page *pdf.Page, font *pdf.Font, box rect, text string, size float64) {
+	if text == "" || box.w <= 0 || box.h <= 0 {
+		return
+	}
+	if w := font.Width(text, size); w > box.w {
+		size *= box.w / w
+	}
+	w := font.Width(text, size)
+	// 以 0.35 倍字号近似 x 高度的一半，使文字视觉居中
+	page.Text(font, size, box.x+(box.w-w)/2, box.y+box.h/2+size*0.35, text)
+}
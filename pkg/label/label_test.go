@@ -0,0 +1,55 @@
+package label
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// TestPresets 测试所有预设版式都能放进纸张
+func TestPresets(t *testing.T) {
+	for name, sheet := range Presets {
+		if err := sheet.Validate(); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
+
+// TestSheetLabelSize 测试自定义版式根据页边距和间距计算标签尺寸
+func TestSheetLabelSize(t *testing.T) {
+	sheet := Sheet{Page: "a4", Columns: 2, Rows: 4, MarginLeft: 10, MarginRight: 10, MarginTop: 10, MarginBottom: 10, GapX: 10}
+	w, h := sheet.LabelSize()
+	if w < 89.9 || w > 90.1 || h < 69.2 || h > 69.3 {
+		t.Errorf("LabelSize = %.2f x %.2f, want 90 x 69.25", w, h)
+	}
+	sheet.MarginLeft = 200
+	if err := sheet.Validate(); err == nil {
+		t.Error("expected error for margins wider than the page")
+	}
+}
+
+// TestRenderPages 测试标签排满一页后换页，跳过的标签计入第一页
+func TestRenderPages(t *testing.T) {
+	items := make([]Item, 20)
+	for i := range items {
+		items[i] = Item{Type: "qrcode", Text: "ASSET"}
+	}
+	items[5] = Item{Type: "barcode", Text: "00042"}
+
+	var buf bytes.Buffer
+	opts := Options{Sheet: Presets["avery-l7160"], Font: goregular.TTF, Padding: 2, Skip: 5}
+	if err := Render(&buf, items, opts); err != nil {
+		t.Fatal(err)
+	}
+	// 每页 21 个标签，跳过 5 个后 20 个标签需要两页
+	if got := strings.Count(buf.String(), "/Type /Page /Parent"); got != 2 {
+		t.Errorf("got %d pages, want 2", got)
+	}
+
+	items[0] = Item{Type: "datamatrix", Text: "x"}
+	if err := Render(&buf, items, opts); err == nil || !strings.Contains(err.Error(), "label 1") {
+		t.Errorf("expected error for label 1, got %v", err)
+	}
+}
@@ -0,0 +1,333 @@
+// Package pdf 是一个最小的 PDF 写入器
+// 只支持矢量矩形和嵌入 TrueType 字体的文字，足够输出标签页
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image/color"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf16"
+
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+)
+
+// 常用页面尺寸，单位为点（1/72 英寸）
+const (
+	A4Width      = 595.28
+	A4Height     = 841.89
+	LetterWidth  = 612
+	LetterHeight = 792
+)
+
+// MM 是一毫米对应的点数
+const MM = 72 / 25.4
+
+// Document 是一个 PDF 文档
+type Document struct {
+	pages []*Page
+	fonts []*Font
+}
+
+// New 创建一个空文档
+func New() *Document {
+	return &Document{}
+}
+
+// Font 是嵌入文档的 TrueType 字体
+// 文字以字形编号（Identity-H）写入，附带 ToUnicode 映射以便复制和搜索
+type Font struct {
+	name string // 页面资源中的名称，如 F1
+	font *truetype.Font
+	file *fontFile
+	used map[truetype.Index]rune // 用到的字形及对应字符
+}
+
+// fontFile 是解析后的字体和压缩后的字体文件，多个文档共用
+type fontFile struct {
+	font *truetype.Font
+	data []byte
+
+	once       sync.Once
+	compressed []byte
+}
+
+// fontKey 是字体数据的地址和长度
+type fontKey struct {
+	p *byte
+	n int
+}
+
+// fontFiles 按字体数据缓存 fontFile，同一份字体数据只解析和压缩一次
+var fontFiles sync.Map
+
+// stream 返回压缩后的字体文件，第一次调用时压缩
+func (f *fontFile) stream() []byte {
+	f.once.Do(func() { f.compressed = deflate(f.data) })
+	return f.compressed
+}
+
+// AddFont 解析并嵌入 TrueType 字体数据，字体文件完整嵌入
+// 解析结果和压缩后的字体文件按 data 的地址缓存，data 在进程中应保持不变，如字体注册表中的字体
+func (d *Document) AddFont(data []byte) (*Font, error) {
+	file, err := loadFontFile(data)
+	if err != nil {
+		return nil, err
+	}
+	f := &Font{
+		name: "F" + strconv.Itoa(len(d.fonts)+1),
+		font: file.font,
+		file: file,
+		used: map[truetype.Index]rune{},
+	}
+	d.fonts = append(d.fonts, f)
+	return f, nil
+}
+
+// loadFontFile 返回缓存的字体，没有时解析并缓存
+func loadFontFile(data []byte) (*fontFile, error) {
+	if len(data) == 0 {
+		return nil, fmt.Errorf("parse font: empty font data")
+	}
+	key := fontKey{&data[0], len(data)}
+	if v, ok := fontFiles.Load(key); ok {
+		return v.(*fontFile), nil
+	}
+	parsed, err := freetype.ParseFont(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse font: %v", err)
+	}
+	v, _ := fontFiles.LoadOrStore(key, &fontFile{font: parsed, data: data})
+	return v.(*fontFile), nil
+}
+
+// Width 返回文字在 size 字号下的宽度
+func (f *Font) Width(s string, size float64) float64 {
+	var w int
+	for _, r := range s {
+		w += int(f.font.HMetric(1000, f.font.Index(r)).AdvanceWidth)
+	}
+	return float64(w) * size / 1000
+}
+
+// Page 是文档中的一页，坐标原点在页面左上角，单位为点
+type Page struct {
+	Width, Height float64
+	content       bytes.Buffer
+}
+
+// AddPage 添加一个 width x height 的页面
+func (d *Document) AddPage(width, height float64) *Page {
+	p := &Page{Width: width, Height: height}
+	d.pages = append(d.pages, p)
+	return p
+}
+
+// SetColor 设置之后填充和文字的颜色
+func (p *Page) SetColor(c color.Color) {
+	r, g, b, _ := c.RGBA()
+	fmt.Fprintf(&p.content, "%s %s %s rg\n", num(float64(r)/0xffff), num(float64(g)/0xffff), num(float64(b)/0xffff))
+}
+
+// Rect 填充左上角在 (x, y) 的矩形
+func (p *Page) Rect(x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%s %s %s %s re f\n", num(x), num(p.Height-y-h), num(w), num(h))
+}
+
+// StrokeRect 用当前颜色描边左上角在 (x, y) 的矩形
+func (p *Page) StrokeRect(x, y, w, h, lineWidth float64) {
+	fmt.Fprintf(&p.content, "q %s w 0 G %s %s %s %s re S Q\n", num(lineWidth), num(x), num(p.Height-y-h), num(w), num(h))
+}
+
+// Text 以 (x, y) 为基线起点绘制一行文字
+func (p *Page) Text(f *Font, size, x, y float64, s string) {
+	var hex strings.Builder
+	for _, r := range s {
+		idx := f.font.Index(r)
+		if _, ok := f.used[idx]; !ok {
+			f.used[idx] = r
+		}
+		fmt.Fprintf(&hex, "%04X", uint16(idx))
+	}
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td <%s> Tj ET\n", f.name, num(size), num(x), num(p.Height-y), hex.String())
+}
+
+// num 格式化数字，最多保留三位小数
+func num(v float64) string {
+	s := strconv.FormatFloat(v, 'f', 3, 64)
+	s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+	if s == "" || s == "-0" {
+		return "0"
+	}
+	return s
+}
+
+// WriteTo 将文档写入 w
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	pw := &writer{w: w}
+	pw.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+
+	// 对象编号：1 为目录，2 为页面树，之后每个字体 5 个对象，每页 2 个对象
+	fontObj := make([]int, len(d.fonts))
+	next := 3
+	for i := range d.fonts {
+		fontObj[i] = next
+		next += 5
+	}
+	pageObj := make([]int, len(d.pages))
+	for i := range d.pages {
+		pageObj[i] = next
+		next += 2
+	}
+	pw.offsets = make([]int64, next-1)
+
+	pw.object(1, "<< /Type /Catalog /Pages 2 0 R >>")
+	kids := make([]string, len(d.pages))
+	for i, n := range pageObj {
+		kids[i] = fmt.Sprintf("%d 0 R", n)
+	}
+	pw.object(2, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+
+	var resources strings.Builder
+	for i, f := range d.fonts {
+		f.write(pw, fontObj[i])
+		fmt.Fprintf(&resources, "/%s %d 0 R ", f.name, fontObj[i])
+	}
+
+	for i, p := range d.pages {
+		n := pageObj[i]
+		pw.object(n, fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s %s] /Resources << /Font << %s>> >> /Contents %d 0 R >>",
+			num(p.Width), num(p.Height), resources.String(), n+1))
+		pw.stream(n+1, "", p.content.Bytes(), true)
+	}
+
+	xref := pw.n
+	pw.printf("xref\n0 %d\n0000000000 65535 f \n", len(pw.offsets)+1)
+	for _, off := range pw.offsets {
+		pw.printf("%010d 00000 n \n", off)
+	}
+	pw.printf("trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(pw.offsets)+1, xref)
+	return pw.n, pw.err
+}
+
+// write 写入字体的 5 个对象：Type0 字体、CID 字体、字体描述、字体文件和 ToUnicode 映射
+func (f *Font) write(pw *writer, n int) {
+	name := psName(f.font.Name(truetype.NameIDPostscriptName))
+	bounds := f.font.Bounds(1000)
+
+	pw.object(n, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s /Encoding /Identity-H /DescendantFonts [%d 0 R] /ToUnicode %d 0 R >>",
+		name, n+1, n+4))
+
+	// 只列出用到的字形宽度
+	indices := make([]int, 0, len(f.used))
+	for idx := range f.used {
+		indices = append(indices, int(idx))
+	}
+	sort.Ints(indices)
+	var widths strings.Builder
+	for _, idx := range indices {
+		fmt.Fprintf(&widths, "%d [%d] ", idx, f.font.HMetric(1000, truetype.Index(idx)).AdvanceWidth)
+	}
+	pw.object(n+1, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType2 /BaseFont /%s /CIDSystemInfo << /Registry (Adobe) /Ordering (Identity) /Supplement 0 >> /FontDescriptor %d 0 R /W [%s] /CIDToGIDMap /Identity >>",
+		name, n+2, widths.String()))
+
+	pw.object(n+2, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [%d %d %d %d] /ItalicAngle 0 /Ascent %d /Descent %d /CapHeight %d /StemV 80 /FontFile2 %d 0 R >>",
+		name, bounds.Min.X, bounds.Min.Y, bounds.Max.X, bounds.Max.Y, bounds.Max.Y, bounds.Min.Y, bounds.Max.Y, n+3))
+	pw.stream(n+3, fmt.Sprintf("/Length1 %d /Filter /FlateDecode ", len(f.file.data)), f.file.stream(), false)
+
+	var cmap strings.Builder
+	cmap.WriteString("/CIDInit /ProcSet findresource begin\n12 dict begin\nbegincmap\n")
+	cmap.WriteString("/CIDSystemInfo << /Registry (Adobe) /Ordering (UCS) /Supplement 0 >> def\n")
+	cmap.WriteString("/CMapName /Adobe-Identity-UCS def\n/CMapType 2 def\n")
+	cmap.WriteString("1 begincodespacerange\n<0000> <FFFF>\nendcodespacerange\n")
+	// 每个 bfchar 段最多 100 项
+	for start := 0; start < len(indices); start += 100 {
+		end := min(start+100, len(indices))
+		fmt.Fprintf(&cmap, "%d beginbfchar\n", end-start)
+		for _, idx := range indices[start:end] {
+			fmt.Fprintf(&cmap, "<%04X> <", idx)
+			for _, u := range utf16.Encode([]rune{f.used[truetype.Index(idx)]}) {
+				fmt.Fprintf(&cmap, "%04X", u)
+			}
+			cmap.WriteString(">\n")
+		}
+		cmap.WriteString("endbfchar\n")
+	}
+	cmap.WriteString("endcmap\nCMapName currentdict /CMap defineresource pop\nend\nend\n")
+	pw.stream(n+4, "", []byte(cmap.String()), true)
+}
+
+// psName 返回可用作 PDF 名称的 PostScript 字体名
+func psName(s string) string {
+	s = strings.Map(func(r rune) rune {
+		if r <= ' ' || r > '~' || strings.ContainsRune("()<>[]{}/%#", r) {
+			return -1
+		}
+		return r
+	}, s)
+	if s == "" {
+		return "Font"
+	}
+	return s
+}
+
+// writer 记录写入的字节数和每个对象的偏移
+type writer struct {
+	w       io.Writer
+	n       int64
+	offsets []int64
+	err     error
+}
+
+// printf 格式化写入，出错后忽略之后的写入
+func (w *writer) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(w.w, format, args...)
+	w.n += int64(n)
+	w.err = err
+}
+
+// write 写入原始字节
+func (w *writer) write(data []byte) {
+	if w.err != nil {
+		return
+	}
+	n, err := w.w.Write(data)
+	w.n += int64(n)
+	w.err = err
+}
+
+// object 写入编号为 num 的对象
+func (w *writer) object(num int, body string) {
+	w.offsets[num-1] = w.n
+	w.printf("%d 0 obj\n%s\nendobj\n", num, body)
+}
+
+// stream 写入编号为 num 的流对象，dict 为附加的字典项
+func (w *writer) stream(num int, dict string, data []byte, compress bool) {
+	if compress {
+		data = deflate(data)
+		dict += "/Filter /FlateDecode "
+	}
+	w.offsets[num-1] = w.n
+	w.printf("%d 0 obj\n<< %s/Length %d >>\nstream\n", num, dict, len(data))
+	w.write(data)
+	w.printf("\nendstream\nendobj\n")
+}
+
+// deflate 返回 zlib 压缩后的数据
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	zw.Write(data)
+	zw.Close()
+	return buf.Bytes()
+}
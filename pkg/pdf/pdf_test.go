@@ -0,0 +1,90 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image/color"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// TestWriteTo 测试交叉引用表中的偏移指向对应的对象
+func TestWriteTo(t *testing.T) {
+	doc := New()
+	f, err := doc.AddFont(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := doc.AddPage(A4Width, A4Height)
+	p.SetColor(color.Black)
+	p.Rect(10, 10, 20, 20)
+	p.Text(f, 12, 10, 50, "Hello")
+	doc.AddPage(LetterWidth, LetterHeight)
+
+	var buf bytes.Buffer
+	if _, err := doc.WriteTo(&buf); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+
+	m := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(data)
+	if m == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(m[1]))
+	if !bytes.HasPrefix(data[xref:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point to xref table", xref)
+	}
+
+	entries := regexp.MustCompile(`(\d{10}) 00000 n \n`).FindAllSubmatch(data[xref:], -1)
+	// 目录、页面树、5 个字体对象和 2 个页面各 2 个对象
+	if len(entries) != 11 {
+		t.Fatalf("got %d objects, want 11", len(entries))
+	}
+	for i, e := range entries {
+		off, _ := strconv.Atoi(string(e[1]))
+		want := fmt.Sprintf("%d 0 obj\n", i+1)
+		if !bytes.HasPrefix(data[off:], []byte(want)) {
+			t.Errorf("object %d offset %d points to %q", i+1, off, data[off:off+10])
+		}
+	}
+}
+
+// TestWidth 测试文字宽度随字号线性变化
+func TestWidth(t *testing.T) {
+	f, err := New().AddFont(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w10, w20 := f.Width("Hello", 10), f.Width("Hello", 20)
+	if w10 <= 0 || w20 != 2*w10 {
+		t.Errorf("Width = %v, %v", w10, w20)
+	}
+}
+
+// TestAddFontCache 测试同一份字体数据只解析和压缩一次
+func TestAddFontCache(t *testing.T) {
+	a, err := New().AddFont(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := New().AddFont(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if a.font != b.font || a.file != b.file {
+		t.Error("AddFont: expected the parsed font to be shared")
+	}
+	if s := a.file.stream(); &s[0] != &b.file.stream()[0] {
+		t.Error("stream: expected the compressed font to be shared")
+	}
+	if _, err := New().AddFont(nil); err == nil {
+		t.Error("AddFont(nil): expected an error")
+	}
+}
@@ -0,0 +1,146 @@
+package poster
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const testTemplate = `{
+	"width": 200,
+	"height": 300,
+	"variables": {
+		"title": {"default": "Hello"},
+		"url": {"required": true}
+	},
+	"background": {"gradient": {"from": "#ffffff", "to": "#ff0000", "angle": 90}},
+	"layers": [
+		{"type": "rect", "x": 10, "y": 10, "width": 180, "height": 40, "fill": "#0000ff", "radius": 8},
+		{"type": "text", "x": 10, "y": 10, "width": 180, "height": 40, "text": "{{title}}", "color": "#ffffff"},
+		{"type": "qrcode", "x": 50, "y": 70, "width": 100, "height": 100, "text": "{{url}}"},
+		{"type": "barcode", "x": 20, "y": 190, "width": 160, "height": 40, "text": "SKU-{{title}}"},
+		{"type": "circle", "x": 80, "y": 250, "width": 40, "height": 40, "fill": "#00ff00"},
+		{"type": "line", "x": 0, "y": 240, "x2": 199, "y2": 240, "stroke": "#000000", "strokeWidth": 2}
+	]
+}`
+
+// testRenderer 返回使用 goregular 字体的渲染器
+func testRenderer(t *testing.T) *Renderer {
+	f, err := freetype.ParseFont(goregular.TTF)
+	if err != nil {
+		t.Fatalf("failed to parse font: %v", err)
+	}
+	return &Renderer{Font: func(string) (*truetype.Font, error) { return f, nil }}
+}
+
+// TestParse 测试模板解析和变量声明检查
+func TestParse(t *testing.T) {
+	if _, err := Parse("ok", []byte(testTemplate)); err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	if _, err := Parse("bad", []byte(`{"width": 10, "height": 10, "layers": [{"type": "text", "text": "{{missing}}"}]}`)); err == nil {
+		t.Errorf("Parse: expected error for undeclared variable")
+	}
+	if _, err := Parse("bad", []byte(`{"width": 10, "height": 10, "layers": [{"type": "star"}]}`)); err == nil {
+		t.Errorf("Parse: expected error for unknown layer type")
+	}
+}
+
+// TestApply 测试变量替换
+func TestApply(t *testing.T) {
+	tpl, err := Parse("test", []byte(testTemplate))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+
+	if _, err := tpl.Apply(map[string]string{}); err == nil {
+		t.Errorf("Apply: expected error for missing required variable")
+	}
+	if _, err := tpl.Apply(map[string]string{"url": "x", "other": "y"}); err == nil {
+		t.Errorf("Apply: expected error for unknown variable")
+	}
+
+	spec, err := tpl.Apply(map[string]string{"url": `https://example.com/?q="a"`})
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	if got := spec.Layers[1].Text; got != "Hello" {
+		t.Errorf("Apply: expected default title, got %q", got)
+	}
+	if got := spec.Layers[2].Text; got != `https://example.com/?q="a"` {
+		t.Errorf("Apply: expected escaped url, got %q", got)
+	}
+}
+
+// TestRender 测试各类图层的渲染
+func TestRender(t *testing.T) {
+	tpl, err := Parse("test", []byte(testTemplate))
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	img, err := testRenderer(t).Render(tpl, map[string]string{"url": "https://example.com", "title": "Pix"})
+	if err != nil {
+		t.Fatalf("Render: %v", err)
+	}
+
+	tests := []struct {
+		x, y int
+		want color.RGBA
+	}{
+		{20, 12, color.RGBA{0, 0, 255, 255}},   // 圆角矩形
+		{100, 270, color.RGBA{0, 255, 0, 255}}, // 圆形
+		{5, 240, color.RGBA{0, 0, 0, 255}},     // 直线
+	}
+	for _, tt := range tests {
+		if got := img.RGBAAt(tt.x, tt.y); got != tt.want {
+			t.Errorf("Render: pixel (%d, %d) expected %v, got %v", tt.x, tt.y, tt.want, got)
+		}
+	}
+	if got := img.RGBAAt(1, 0); got != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("Render: expected gradient to start white, got %v", got)
+	}
+	if got := img.RGBAAt(1, 299); got.R != 255 || got.G > 2 {
+		t.Errorf("Render: expected gradient to end red, got %v", got)
+	}
+}
+
+// pngHeader 返回只有文件头和 IHDR 块、声明为 width x height 的 PNG
+func pngHeader(width, height uint32) []byte {
+	ihdr := make([]byte, 17)
+	copy(ihdr, "IHDR")
+	binary.BigEndian.PutUint32(ihdr[4:], width)
+	binary.BigEndian.PutUint32(ihdr[8:], height)
+	ihdr[12], ihdr[13] = 8, 6 // 8 位 RGBA
+	data := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0d")
+	data = append(data, ihdr...)
+	return binary.BigEndian.AppendUint32(data, crc32.ChecksumIEEE(ihdr))
+}
+
+// TestImageLimits 测试图片在解码像素前按文件头中的尺寸和字节数拒绝
+func TestImageLimits(t *testing.T) {
+	r := testRenderer(t)
+	r.MaxImageWidth, r.MaxImageHeight, r.MaxImageArea, r.MaxImageBytes = 4096, 4096, 1<<24, 1024
+	dataURI := func(b []byte) string { return "data:image/png;base64," + base64.StdEncoding.EncodeToString(b) }
+
+	if _, err := r.loadImage(dataURI(pngHeader(40000, 40000))); errcode.Of(err) != errcode.SizeTooLarge {
+		t.Errorf("oversized image: got %v", err)
+	}
+	if _, err := r.loadImage(dataURI(make([]byte, 2048))); errcode.Of(err) != errcode.PayloadTooLong {
+		t.Errorf("large data URI: got %v", err)
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 4, 4)))
+	if _, err := r.loadImage(dataURI(buf.Bytes())); err != nil {
+		t.Errorf("small image: %v", err)
+	}
+}
@@ -0,0 +1,447 @@
+package poster
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io/fs"
+	"math"
+	"strings"
+
+	"github.com/bitqiu/pix-gen/pkg/barcode"
+	"github.com/bitqiu/pix-gen/pkg/colors"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/qrcode"
+	"github.com/bitqiu/pix-gen/pkg/richtext"
+	"github.com/golang/freetype/truetype"
+	xdraw "golang.org/x/image/draw"
+)
+
+// Renderer 将模板渲染为图片
+type Renderer struct {
+	// Font 按名称查找字体，名称为空时返回默认字体
+	Font func(name string) (*truetype.Font, error)
+	// Assets 是 image 图层和背景图片引用的文件，为 nil 时只能使用 data URI
+	Assets fs.FS
+	// MaxImageBytes 限制图片文件的字节数，为 0 时不限制
+	MaxImageBytes int
+	// MaxImageWidth、MaxImageHeight 和 MaxImageArea 限制图片的宽高和像素数，为 0 时不限制
+	// 解码前先读取图片头检查尺寸，超出时不会为像素分配内存
+	MaxImageWidth, MaxImageHeight, MaxImageArea int
+}
+
+// Render 用 vars 填充模板变量并渲染图片
+func (r *Renderer) Render(t *Template, vars map[string]string) (*image.RGBA, error) {
+	spec, err := t.Apply(vars)
+	if err != nil {
+		return nil, err
+	}
+	return r.RenderSpec(spec)
+}
+
+// RenderSpec 按图层顺序渲染模板内容
+func (r *Renderer) RenderSpec(spec *Spec) (*image.RGBA, error) {
+	img := image.NewRGBA(image.Rect(0, 0, spec.Width, spec.Height))
+	if err := r.drawBackground(img, spec.Background); err != nil {
+		return nil, errcode.Errorf(errcode.Of(err), "background: %w", err)
+	}
+
+	for i, layer := range spec.Layers {
+		var err error
+		switch layer.Type {
+		case "text":
+			err = r.drawText(img, layer)
+		case "qrcode":
+			err = drawQRCode(img, layer)
+		case "barcode":
+			err = drawBarcode(img, layer)
+		case "rect", "circle":
+			err = drawShape(img, layer)
+		case "line":
+			err = drawLine(img, layer)
+		case "image":
+			err = r.drawImage(img, layer)
+		default:
+			err = fmt.Errorf("unknown type %q", layer.Type)
+		}
+		if err != nil {
+			return nil, errcode.Errorf(errcode.Of(err), "layer %d (%s): %w", i, layer.Type, err)
+		}
+	}
+	return img, nil
+}
+
+// rect 返回图层所占的矩形区域
+func (l Layer) rect() image.Rectangle {
+	return image.Rect(l.X, l.Y, l.X+l.Width, l.Y+l.Height)
+}
+
+// parseColor 解析颜色，值为空时返回 def
+func parseColor(value string, def color.Color) (color.Color, error) {
+	if value == "" {
+		return def, nil
+	}
+	c, err := colors.Parse(value)
+	if err != nil {
+		return nil, errcode.Errorf(errcode.InvalidColor, "invalid color %q", value)
+	}
+	return c, nil
+}
+
+// drawBackground 绘制画布背景
+func (r *Renderer) drawBackground(img *image.RGBA, bg Background) error {
+	c, err := parseColor(bg.Color, color.White)
+	if err != nil {
+		return err
+	}
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+
+	if g := bg.Gradient; g != nil {
+		from, err := parseColor(g.From, color.White)
+		if err != nil {
+			return err
+		}
+		to, err := parseColor(g.To, color.Black)
+		if err != nil {
+			return err
+		}
+		drawGradient(img, from, to, g.Angle)
+	}
+
+	if bg.Image != "" {
+		src, err := r.loadImage(bg.Image)
+		if err != nil {
+			return err
+		}
+		fit := bg.Fit
+		if fit == "" {
+			fit = "cover"
+		}
+		return drawFitted(img, img.Bounds(), src, fit)
+	}
+	return nil
+}
+
+// drawGradient 在整个画布上绘制线性渐变
+func drawGradient(img *image.RGBA, from, to color.Color, angle float64) {
+	b := img.Bounds()
+	sin, cos := math.Sincos(angle * math.Pi / 180)
+
+	// 将四个角投影到渐变方向上，得到渐变的起止位置
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, p := range []image.Point{b.Min, {b.Max.X, b.Min.Y}, {b.Min.X, b.Max.Y}, b.Max} {
+		d := float64(p.X)*cos + float64(p.Y)*sin
+		lo, hi = math.Min(lo, d), math.Max(hi, d)
+	}
+
+	f := color.RGBAModel.Convert(from).(color.RGBA)
+	t := color.RGBAModel.Convert(to).(color.RGBA)
+	lerp := func(a, b uint8, k float64) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*k + 0.5)
+	}
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			k := 0.0
+			if hi > lo {
+				k = (float64(x)*cos + float64(y)*sin - lo) / (hi - lo)
+			}
+			img.SetRGBA(x, y, color.RGBA{lerp(f.R, t.R, k), lerp(f.G, t.G, k), lerp(f.B, t.B, k), lerp(f.A, t.A, k)})
+		}
+	}
+}
+
+// drawText 绘制文字图层，未指定字号时自动缩小到放得下为止
+func (r *Renderer) drawText(img *image.RGBA, l Layer) error {
+	if r.Font == nil {
+		return errcode.New(errcode.FontMissing, "no font available")
+	}
+	f, err := r.Font(l.Font)
+	if err != nil {
+		return err
+	}
+	fg, err := parseColor(l.Color, color.Black)
+	if err != nil {
+		return err
+	}
+
+	lines := []richtext.Line{{{Text: l.Text}}}
+	if l.Markup != "" {
+		if lines, err = richtext.ParseMarkup(l.Markup); err != nil {
+			return err
+		}
+	} else if strings.Contains(l.Text, "\n") {
+		lines = nil
+		for _, s := range strings.Split(l.Text, "\n") {
+			lines = append(lines, richtext.Line{{Text: s}})
+		}
+	}
+
+	opts := richtext.Options{Font: f, FontSize: l.FontSize, Color: fg, LineGap: l.LineGap, Align: l.Align}
+	if opts.FontSize > 0 {
+		layout, err := richtext.NewLayout(lines, opts)
+		if err != nil {
+			return err
+		}
+		layout.Draw(img, l.rect())
+		return nil
+	}
+
+	for opts.FontSize = float64(l.Height); opts.FontSize >= 4; opts.FontSize *= 0.9 {
+		layout, err := richtext.NewLayout(lines, opts)
+		if err != nil {
+			return err
+		}
+		if layout.Width <= l.Width && layout.Height <= l.Height {
+			layout.Draw(img, l.rect())
+			return nil
+		}
+	}
+	return errcode.Errorf(errcode.InvalidSize, "text does not fit in %dx%d", l.Width, l.Height)
+}
+
+// drawQRCode 绘制二维码图层，二维码为正方形，在图层区域内居中
+func drawQRCode(img *image.RGBA, l Layer) error {
+	fg, err := parseColor(l.Color, color.Black)
+	if err != nil {
+		return err
+	}
+	bg, err := parseColor(l.Background, color.White)
+	if err != nil {
+		return err
+	}
+	level := qrcode.Level(l.Level)
+	if level == "" {
+		level = qrcode.LevelMedium
+	}
+
+	size := min(l.Width, l.Height)
+	qr, err := qrcode.Render(l.Text, qrcode.Options{Level: level, Size: size, Margin: l.Margin, Color: fg, Background: bg})
+	if err != nil {
+		return err
+	}
+	left := l.X + (l.Width-size)/2
+	top := l.Y + (l.Height-size)/2
+	draw.Draw(img, image.Rect(left, top, left+size, top+size), qr, image.Point{}, draw.Over)
+	return nil
+}
+
+// drawBarcode 绘制 Code 128 条码图层
+func drawBarcode(img *image.RGBA, l Layer) error {
+	fg, err := parseColor(l.Color, color.Black)
+	if err != nil {
+		return err
+	}
+	b, err := barcode.Code128(l.Text)
+	if err != nil {
+		return err
+	}
+	if l.Background != "" {
+		bg, err := parseColor(l.Background, color.White)
+		if err != nil {
+			return err
+		}
+		draw.Draw(img, l.rect(), image.NewUniform(bg), image.Point{}, draw.Src)
+	}
+	b.Draw(img, l.rect(), fg)
+	return nil
+}
+
+// drawShape 绘制矩形（可带圆角）或内切于图层区域的椭圆
+func drawShape(img *image.RGBA, l Layer) error {
+	fill, err := parseColor(l.Fill, nil)
+	if err != nil {
+		return err
+	}
+	stroke, err := parseColor(l.Stroke, nil)
+	if err != nil {
+		return err
+	}
+
+	rect := l.rect()
+	inside := func(rect image.Rectangle, radius float64) func(x, y float64) bool {
+		if l.Type == "circle" {
+			cx, cy := float64(rect.Min.X+rect.Max.X)/2, float64(rect.Min.Y+rect.Max.Y)/2
+			rx, ry := float64(rect.Dx())/2, float64(rect.Dy())/2
+			return func(x, y float64) bool {
+				dx, dy := (x-cx)/rx, (y-cy)/ry
+				return dx*dx+dy*dy <= 1
+			}
+		}
+		return func(x, y float64) bool {
+			return inRoundRect(rect, radius, x, y)
+		}
+	}
+
+	outer := inside(rect, float64(l.Radius))
+	if fill != nil {
+		drawMask(img, rect, fill, outer)
+	}
+
+	// 描边为外轮廓与向内收缩 strokeWidth 后的轮廓之间的环形区域
+	if stroke != nil && l.StrokeWidth > 0 {
+		w := l.StrokeWidth
+		innerRect := image.Rect(rect.Min.X+w, rect.Min.Y+w, rect.Max.X-w, rect.Max.Y-w)
+		inner := func(x, y float64) bool { return false }
+		if !innerRect.Empty() {
+			inner = inside(innerRect, math.Max(0, float64(l.Radius-w)))
+		}
+		drawMask(img, rect, stroke, func(x, y float64) bool { return outer(x, y) && !inner(x, y) })
+	}
+	return nil
+}
+
+// inRoundRect 判断点是否在圆角矩形内
+func inRoundRect(r image.Rectangle, radius, x, y float64) bool {
+	minX, minY, maxX, maxY := float64(r.Min.X), float64(r.Min.Y), float64(r.Max.X), float64(r.Max.Y)
+	if x < minX || x > maxX || y < minY || y > maxY {
+		return false
+	}
+	radius = math.Min(radius, math.Min(maxX-minX, maxY-minY)/2)
+	cx := math.Max(minX+radius, math.Min(x, maxX-radius))
+	cy := math.Max(minY+radius, math.Min(y, maxY-radius))
+	dx, dy := x-cx, y-cy
+	return dx*dx+dy*dy <= radius*radius
+}
+
+// drawMask 用 4x4 超采样计算覆盖率，按覆盖率混合颜色以消除锯齿
+func drawMask(img *image.RGBA, rect image.Rectangle, c color.Color, inside func(x, y float64) bool) {
+	rect = rect.Intersect(img.Bounds())
+	mask := image.NewAlpha(rect)
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			n := 0
+			for sy := 0; sy < 4; sy++ {
+				for sx := 0; sx < 4; sx++ {
+					if inside(float64(x)+(float64(sx)+0.5)/4, float64(y)+(float64(sy)+0.5)/4) {
+						n++
+					}
+				}
+			}
+			mask.SetAlpha(x, y, color.Alpha{A: uint8(n * 255 / 16)})
+		}
+	}
+	draw.DrawMask(img, rect, image.NewUniform(c), image.Point{}, mask, rect.Min, draw.Over)
+}
+
+// drawLine 绘制从 (x, y) 到 (x2, y2) 的直线
+func drawLine(img *image.RGBA, l Layer) error {
+	c, err := parseColor(l.Stroke, color.Black)
+	if err != nil {
+		return err
+	}
+	half := math.Max(1, float64(l.StrokeWidth)) / 2
+	x1, y1, x2, y2 := float64(l.X), float64(l.Y), float64(l.X2), float64(l.Y2)
+	length := math.Hypot(x2-x1, y2-y1)
+
+	bounds := image.Rect(min(l.X, l.X2), min(l.Y, l.Y2), max(l.X, l.X2), max(l.Y, l.Y2)).Inset(-int(half) - 1)
+	drawMask(img, bounds, c, func(x, y float64) bool {
+		if length == 0 {
+			return math.Hypot(x-x1, y-y1) <= half
+		}
+		// 点到线段的距离
+		t := math.Max(0, math.Min(1, ((x-x1)*(x2-x1)+(y-y1)*(y2-y1))/(length*length)))
+		return math.Hypot(x-(x1+t*(x2-x1)), y-(y1+t*(y2-y1))) <= half
+	})
+	return nil
+}
+
+// drawImage 绘制图片图层
+func (r *Renderer) drawImage(img *image.RGBA, l Layer) error {
+	src, err := r.loadImage(l.Src)
+	if err != nil {
+		return err
+	}
+	fit := l.Fit
+	if fit == "" {
+		fit = "contain"
+	}
+	return drawFitted(img, l.rect(), src, fit)
+}
+
+// loadImage 读取 data URI 或 Assets 中的图片
+func (r *Renderer) loadImage(src string) (image.Image, error) {
+	var data []byte
+	if strings.HasPrefix(src, "data:") {
+		_, payload, ok := strings.Cut(src, ";base64,")
+		if !ok {
+			return nil, fmt.Errorf("only base64 data URIs are supported")
+		}
+		if r.MaxImageBytes > 0 && base64.StdEncoding.DecodedLen(len(payload)) > r.MaxImageBytes {
+			return nil, errcode.Errorf(errcode.PayloadTooLong, "image must be at most %d bytes", r.MaxImageBytes)
+		}
+		var err error
+		if data, err = base64.StdEncoding.DecodeString(payload); err != nil {
+			return nil, fmt.Errorf("invalid data URI: %v", err)
+		}
+	} else {
+		if r.Assets == nil {
+			return nil, fmt.Errorf("image %q not found", src)
+		}
+		var err error
+		if data, err = fs.ReadFile(r.Assets, src); err != nil {
+			return nil, fmt.Errorf("image %q not found", src)
+		}
+		if r.MaxImageBytes > 0 && len(data) > r.MaxImageBytes {
+			return nil, errcode.Errorf(errcode.PayloadTooLong, "image %q must be at most %d bytes", src, r.MaxImageBytes)
+		}
+	}
+
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %v", err)
+	}
+	if r.exceeds(cfg.Width, cfg.Height) {
+		return nil, errcode.Errorf(errcode.SizeTooLarge, "image %dx%d exceeds limits", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode image: %v", err)
+	}
+	return img, nil
+}
+
+// exceeds 判断图片尺寸是否超出限制
+func (r *Renderer) exceeds(width, height int) bool {
+	return (r.MaxImageWidth > 0 && width > r.MaxImageWidth) ||
+		(r.MaxImageHeight > 0 && height > r.MaxImageHeight) ||
+		(r.MaxImageArea > 0 && width > 0 && height > r.MaxImageArea/width)
+}
+
+// drawFitted 按缩放方式将图片绘制到 rect 中
+// contain 保持比例完整显示，cover 保持比例铺满并裁剪，fill 拉伸铺满
+func drawFitted(dst *image.RGBA, rect image.Rectangle, src image.Image, fit string) error {
+	sb := src.Bounds()
+	if sb.Empty() || rect.Empty() {
+		return nil
+	}
+	sx := float64(rect.Dx()) / float64(sb.Dx())
+	sy := float64(rect.Dy()) / float64(sb.Dy())
+
+	target := rect
+	switch fit {
+	case "fill":
+	case "contain", "cover":
+		scale := math.Min(sx, sy)
+		if fit == "cover" {
+			scale = math.Max(sx, sy)
+		}
+		w := int(math.Round(float64(sb.Dx()) * scale))
+		h := int(math.Round(float64(sb.Dy()) * scale))
+		left := rect.Min.X + (rect.Dx()-w)/2
+		top := rect.Min.Y + (rect.Dy()-h)/2
+		target = image.Rect(left, top, left+w, top+h)
+	default:
+		return fmt.Errorf("invalid fit %q", fit)
+	}
+
+	// cover 模式下超出 rect 的部分被裁剪
+	sub := dst.SubImage(rect).(*image.RGBA)
+	xdraw.CatmullRom.Scale(sub, target, src, sb, xdraw.Over, nil)
+	return nil
+}
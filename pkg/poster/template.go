@@ -0,0 +1,200 @@
+package poster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Variable 是模板中的具名变量
+type Variable struct {
+	Default     string `json:"default,omitempty"`     // 默认值
+	Required    bool   `json:"required,omitempty"`    // 是否必填
+	Description string `json:"description,omitempty"` // 说明
+}
+
+// Background 是画布背景，可以是纯色、渐变或图片
+type Background struct {
+	Color    string    `json:"color,omitempty"`    // 背景颜色
+	Gradient *Gradient `json:"gradient,omitempty"` // 线性渐变
+	Image    string    `json:"image,omitempty"`    // 背景图片
+	Fit      string    `json:"fit,omitempty"`      // 背景图片的缩放方式，默认为 cover
+}
+
+// Gradient 是线性渐变
+type Gradient struct {
+	From  string  `json:"from"`  // 起始颜色
+	To    string  `json:"to"`    // 结束颜色
+	Angle float64 `json:"angle"` // 渐变方向，0 为从左到右，90 为从上到下
+}
+
+// Layer 是画布上的一个图层，按 Type 使用不同的字段
+type Layer struct {
+	Type   string `json:"type"`   // 图层类型：text、qrcode、barcode、rect、circle、line、image
+	X      int    `json:"x"`      // 左上角横坐标
+	Y      int    `json:"y"`      // 左上角纵坐标
+	Width  int    `json:"width"`  // 宽度
+	Height int    `json:"height"` // 高度
+
+	// text、qrcode、barcode 图层
+	Text       string  `json:"text,omitempty"`       // 文字或编码内容
+	Markup     string  `json:"markup,omitempty"`     // 标签语法的富文本，仅 text 图层
+	Font       string  `json:"font,omitempty"`       // 字体名称
+	FontSize   float64 `json:"fontSize,omitempty"`   // 字号，为 0 时自动适应图层大小
+	Color      string  `json:"color,omitempty"`      // 文字或前景颜色
+	Background string  `json:"background,omitempty"` // 二维码和条码的背景颜色
+	Align      string  `json:"align,omitempty"`      // 文字对齐方式：left、center、right
+	LineGap    int     `json:"lineGap,omitempty"`    // 文字行间距
+	Level      string  `json:"level,omitempty"`      // 二维码容错级别，默认为 M
+	Margin     int     `json:"margin,omitempty"`     // 二维码边距
+
+	// rect、circle、line 图层
+	Fill        string `json:"fill,omitempty"`        // 填充颜色
+	Stroke      string `json:"stroke,omitempty"`      // 描边颜色
+	StrokeWidth int    `json:"strokeWidth,omitempty"` // 描边宽度
+	Radius      int    `json:"radius,omitempty"`      // 矩形圆角半径
+	X2          int    `json:"x2,omitempty"`          // 直线终点横坐标
+	Y2          int    `json:"y2,omitempty"`          // 直线终点纵坐标
+
+	// image 图层
+	Src string `json:"src,omitempty"` // 图片文件名或 data URI
+	Fit string `json:"fit,omitempty"` // 缩放方式：contain、cover、fill，默认为 contain
+}
+
+// Spec 是变量替换后的模板内容
+type Spec struct {
+	Width      int                 `json:"width"`
+	Height     int                 `json:"height"`
+	Variables  map[string]Variable `json:"variables,omitempty"`
+	Background Background          `json:"background"`
+	Layers     []Layer             `json:"layers"`
+}
+
+// Template 是一个 JSON 模板
+type Template struct {
+	Name      string
+	Variables map[string]Variable
+	raw       []byte
+}
+
+// placeholder 匹配模板中的 {{name}} 变量引用
+var placeholder = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_]+)\s*\}\}`)
+
+// Parse 解析 JSON 模板，并检查引用的变量都已声明
+func Parse(name string, data []byte) (*Template, error) {
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("template %s: %v", name, err)
+	}
+	for _, m := range placeholder.FindAllSubmatch(data, -1) {
+		if _, ok := spec.Variables[string(m[1])]; !ok {
+			return nil, fmt.Errorf("template %s: undeclared variable %q", name, m[1])
+		}
+	}
+
+	t := &Template{Name: name, Variables: spec.Variables, raw: data}
+	// 使用默认值渲染一次以检查模板结构
+	if _, err := t.apply(t.defaults(), false); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// defaults 返回所有变量的默认值
+func (t *Template) defaults() map[string]string {
+	vars := map[string]string{}
+	for name, v := range t.Variables {
+		vars[name] = v.Default
+	}
+	return vars
+}
+
+// Apply 用 vars 填充变量，未提供的变量使用默认值
+func (t *Template) Apply(vars map[string]string) (*Spec, error) {
+	return t.apply(vars, true)
+}
+
+// apply 替换变量并解析模板，strict 为 true 时检查未知变量和必填变量
+func (t *Template) apply(vars map[string]string, strict bool) (*Spec, error) {
+	if strict {
+		var unknown []string
+		for name := range vars {
+			if _, ok := t.Variables[name]; !ok {
+				unknown = append(unknown, name)
+			}
+		}
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			return nil, fmt.Errorf("unknown variables: %s", strings.Join(unknown, ", "))
+		}
+	}
+
+	var missing []string
+	values := map[string]string{}
+	for name, v := range t.Variables {
+		value, ok := vars[name]
+		if !ok || value == "" {
+			value = v.Default
+		}
+		if strict && v.Required && value == "" {
+			missing = append(missing, name)
+		}
+		values[name] = value
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("missing required variables: %s", strings.Join(missing, ", "))
+	}
+
+	// 变量只出现在 JSON 字符串中，替换时按 JSON 字符串转义
+	data := placeholder.ReplaceAllFunc(t.raw, func(m []byte) []byte {
+		name := string(placeholder.FindSubmatch(m)[1])
+		quoted, _ := json.Marshal(values[name])
+		return quoted[1 : len(quoted)-1]
+	})
+
+	var spec Spec
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, fmt.Errorf("template %s: %v", t.Name, err)
+	}
+	if spec.Width <= 0 || spec.Height <= 0 {
+		return nil, fmt.Errorf("template %s: width and height must be positive", t.Name)
+	}
+	for i, layer := range spec.Layers {
+		switch layer.Type {
+		case "text", "qrcode", "barcode", "rect", "circle", "line", "image":
+		default:
+			return nil, fmt.Errorf("template %s: layer %d has unknown type %q", t.Name, i, layer.Type)
+		}
+	}
+	return &spec, nil
+}
+
+// LoadDir 加载目录下的所有 .json 模板，模板名为不含扩展名的文件名
+func LoadDir(dir string) (map[string]*Template, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read template dir: %v", err)
+	}
+	templates := map[string]*Template{}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		t, err := Parse(name, data)
+		if err != nil {
+			return nil, err
+		}
+		templates[name] = t
+	}
+	return templates, nil
+}
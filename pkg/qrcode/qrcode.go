@@ -1,142 +1,268 @@
+// Package qrcode 生成二维码图像，可以直接在其他 Go 程序中使用
 package qrcode
 
 import (
 	"bytes"
+	"context"
 	"fmt"
-	"github.com/skip2/go-qrcode"
 	"image"
 	"image/color"
 	"image/draw"
 	"image/png"
 	"strconv"
-	"strings"
+
+	"github.com/bitqiu/pix-gen/pkg/colors"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/skip2/go-qrcode"
+	xdraw "golang.org/x/image/draw"
+)
+
+// Level 是二维码的错误校验级别
+type Level string
+
+// 错误校验级别，可恢复的数据依次约为 7%、15%、25%、30%
+const (
+	LevelLow      Level = "L"
+	LevelMedium   Level = "M"
+	LevelQuartile Level = "Q"
+	LevelHigh     Level = "H"
 )
 
-// colorNames 是颜色名称到16进制颜色值的映射表
-var colorNames = map[string]string{
-	"black":   "000000",
-	"white":   "ffffff",
-	"red":     "ff0000",
-	"green":   "00ff00",
-	"blue":    "0000ff",
-	"yellow":  "ffff00",
-	"cyan":    "00ffff",
-	"magenta": "ff00ff",
-	"gray":    "808080",
-	"purple":  "800080",
-	"orange":  "ffa500",
-}
-
-// GenerateQRCode 生成二维码图像
+// ErrInvalidLevel 表示错误校验级别无效
+var ErrInvalidLevel = errcode.New(errcode.InvalidLevel, "invalid QR code level")
+
+// ParseLevel 解析 L、M、Q、H 形式的错误校验级别
+func ParseLevel(s string) (Level, error) {
+	l := Level(s)
+	if _, err := l.recovery(); err != nil {
+		return "", err
+	}
+	return l, nil
+}
+
+// recovery 将错误校验级别转换为 go-qrcode 的级别
+func (l Level) recovery() (qrcode.RecoveryLevel, error) {
+	switch l {
+	case LevelLow:
+		return qrcode.Low, nil
+	case LevelMedium:
+		return qrcode.Medium, nil
+	case LevelQuartile:
+		return qrcode.High, nil
+	case LevelHigh:
+		return qrcode.Highest, nil
+	}
+	return qrcode.Medium, ErrInvalidLevel
+}
+
+// Option 设置生成二维码的参数
+type Option func(*Options)
+
+// WithLevel 设置错误校验级别，默认为 M
+func WithLevel(l Level) Option {
+	return func(o *Options) { o.Level = l }
+}
+
+// WithSize 设置图像边长，包含边距，默认为 256
+func WithSize(size int) Option {
+	return func(o *Options) { o.Size = size }
+}
+
+// WithMargin 设置边距，不能超过边长的四分之一，默认为 0
+func WithMargin(margin int) Option {
+	return func(o *Options) { o.Margin = margin }
+}
+
+// WithColor 设置前景颜色，默认为黑色
+func WithColor(c color.Color) Option {
+	return func(o *Options) { o.Color = c }
+}
+
+// WithBackground 设置背景颜色，默认为白色
+func WithBackground(c color.Color) Option {
+	return func(o *Options) { o.Background = c }
+}
+
+// WithLogo 设置居中绘制的标志
+func WithLogo(logo image.Image) Option {
+	return func(o *Options) { o.Logo = logo }
+}
+
+// Encode 按选项生成二维码图像，未设置的选项使用默认值
+func Encode(ctx context.Context, text string, opts ...Option) (image.Image, error) {
+	o := Options{Level: LevelMedium, Size: 256}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return Render(text, o)
+}
+
+// GenerateQRCode 生成二维码图像并编码为 PNG
+//
+// Deprecated: 使用 Encode 生成图像后自行编码。
 func GenerateQRCode(text, level, sizeQuery, colorQuery, marginQuery string) ([]byte, error) {
+	qrWithMargin, err := GenerateQRCodeImage(text, level, sizeQuery, colorQuery, marginQuery)
+	if err != nil {
+		return nil, err
+	}
+
+	// 编码带有边距的二维码图像
+	var pngBuffer bytes.Buffer
+	err = png.Encode(&pngBuffer, qrWithMargin)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode QR code image with margin")
+	}
+
+	return pngBuffer.Bytes(), nil
+}
+
+// GenerateQRCodeImage 根据字符串参数生成二维码图像
+//
+// Deprecated: 使用 Encode。
+func GenerateQRCodeImage(text, level, sizeQuery, colorQuery, marginQuery string) (image.Image, error) {
 	// 转换字符串为int，并增加错误处理
 	size, err := strconv.ParseInt(sizeQuery, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid size format")
+		return nil, errcode.New(errcode.InvalidSize, "invalid size format")
 	}
 
 	// 转换边距字符串为int，并增加错误处理
 	margin, err := strconv.ParseInt(marginQuery, 10, 64)
 	if err != nil {
-		return nil, fmt.Errorf("invalid margin format")
+		return nil, errcode.New(errcode.InvalidSize, "invalid margin format")
 	}
 
-	// 检查边距不能大于 size 的四分之一
-	if margin > size/4 {
-		return nil, fmt.Errorf("margin cannot be greater than one quarter of the size")
+	// 获取前景颜色
+	rgbaColor, err := colors.Parse(colorQuery)
+	if err != nil {
+		return nil, err
 	}
 
+	// 生成带有边距的二维码图像
+	return Render(text, Options{
+		Level:  Level(level),
+		Size:   int(size),
+		Margin: int(margin),
+		Color:  rgbaColor,
+	})
+}
+
+// Options 是生成二维码图像的参数
+type Options struct {
+	Level      Level       // 错误校验级别
+	Size       int         // 图像边长，包含边距
+	Margin     int         // 边距
+	Color      color.Color // 前景颜色，为 nil 时为黑色
+	Background color.Color // 背景颜色，为 nil 时为白色
+	Logo       image.Image // 居中绘制的标志，为 nil 时不绘制；标志会遮挡部分模块，绘制标志时总是使用 H 级别
+}
+
+// Render 生成带有边距的二维码图像
+func Render(text string, opts Options) (image.Image, error) {
 	// 设置错误校验级别
-	qrLevel := qrcode.Medium
-	switch level {
-	case "L":
-		qrLevel = qrcode.Low
-	case "M":
-		qrLevel = qrcode.Medium
-	case "Q":
-		qrLevel = qrcode.High
-	case "H":
+	qrLevel, err := opts.Level.recovery()
+	if err != nil {
+		return nil, err
+	}
+	// 标志遮挡中央的模块，只有 H 级别的纠错能力保证仍可识别
+	if opts.Logo != nil {
+		if opts.Logo.Bounds().Empty() {
+			return nil, errcode.New(errcode.InvalidSize, "logo image must not be empty")
+		}
 		qrLevel = qrcode.Highest
-	default:
-		return nil, fmt.Errorf("invalid QR code level")
+	}
+
+	// 检查大小和边距的边界条件
+	if opts.Size <= 0 || opts.Margin < 0 {
+		return nil, errcode.New(errcode.InvalidSize, "size must be positive and margin must not be negative")
+	}
+	if opts.Margin > opts.Size/4 {
+		return nil, errcode.New(errcode.InvalidSize, "margin cannot be greater than one quarter of the size")
 	}
 
 	// 创建二维码对象
-	qrc, err := qrcode.New(text, qrLevel)
+	qrc, err := newQRCode(text, qrLevel)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create QR code")
+		return nil, err
 	}
 
 	// 禁用默认的边距
 	qrc.DisableBorder = true
-
-	// 获取前景颜色
-	rgbaColor, err := getColor(colorQuery)
-	if err != nil {
-		return nil, fmt.Errorf("invalid color format")
+	if opts.Color != nil {
+		qrc.ForegroundColor = opts.Color
+	}
+	if opts.Background != nil {
+		qrc.BackgroundColor = opts.Background
 	}
-	qrc.ForegroundColor = rgbaColor
 
 	// 计算二维码图片的实际大小
-	qrCodeSize := int(size - 2*margin)
-	qrImage, err := qrc.PNG(qrCodeSize)
-	if err != nil {
-		return nil, fmt.Errorf("failed to encode QR code image")
-	}
+	qrImage := qrc.Image(opts.Size - 2*opts.Margin)
 
 	// 创建带有边距的新图像
-	qrWithMargin := addMarginToQRCode(qrImage, int(size), int(margin))
-
-	// 编码带有边距的二维码图像
-	var pngBuffer bytes.Buffer
-	err = png.Encode(&pngBuffer, qrWithMargin)
-	if err != nil {
-		return nil, fmt.Errorf("failed to encode QR code image with margin")
+	bg := opts.Background
+	if bg == nil {
+		bg = color.White
 	}
-
-	return pngBuffer.Bytes(), nil
+	img := addMarginToQRCode(qrImage, opts.Size, opts.Margin, bg)
+	if opts.Logo != nil {
+		drawLogo(img, opts.Logo, opts.Size-2*opts.Margin, bg)
+	}
+	return img, nil
 }
 
-// getColor 根据颜色名字或16进制颜色值返回RGBA颜色
-func getColor(input string) (color.RGBA, error) {
-	hex := strings.TrimPrefix(strings.ToLower(input), "#")
-
-	// 如果是颜色名字，转换为16进制颜色值
-	if hexValue, ok := colorNames[hex]; ok {
-		hex = hexValue
+// drawLogo 在二维码中央绘制标志，标志边长为二维码的五分之一，四周留出背景色的边框
+func drawLogo(img *image.RGBA, logo image.Image, qrSize int, bg color.Color) {
+	side := qrSize / 5
+	if side < 4 {
+		return
 	}
+	center := img.Bounds().Dx() / 2
+	pad := max(side/10, 1)
+	frame := image.Rect(center-side/2-pad, center-side/2-pad, center+side/2+pad, center+side/2+pad)
+	draw.Draw(img, frame, &image.Uniform{bg}, image.Point{}, draw.Src)
 
-	if len(hex) != 6 {
-		return color.RGBA{}, fmt.Errorf("invalid color format")
+	// 保持标志的宽高比缩放到 side 以内
+	b := logo.Bounds()
+	w, h := side, side
+	if b.Dx() > b.Dy() {
+		h = side * b.Dy() / b.Dx()
+	} else {
+		w = side * b.Dx() / b.Dy()
 	}
-
-	return hexToRGBA(hex)
+	dst := image.Rect(center-w/2, center-h/2, center-w/2+w, center-h/2+h)
+	xdraw.CatmullRom.Scale(img, dst, logo, b, draw.Over, nil)
 }
 
-// hexToRGBA 将16进制颜色转换为RGBA
-func hexToRGBA(hex string) (color.RGBA, error) {
-	r, err := strconv.ParseUint(hex[:2], 16, 8)
+// Bitmap 返回二维码的模块矩阵，不含边距，便于矢量输出
+func Bitmap(text string, level Level) ([][]bool, error) {
+	qrLevel, err := level.recovery()
 	if err != nil {
-		return color.RGBA{}, err
+		return nil, err
 	}
-	g, err := strconv.ParseUint(hex[2:4], 16, 8)
+	qrc, err := newQRCode(text, qrLevel)
 	if err != nil {
-		return color.RGBA{}, err
+		return nil, err
 	}
-	b, err := strconv.ParseUint(hex[4:], 16, 8)
+	qrc.DisableBorder = true
+	return qrc.Bitmap(), nil
+}
+
+// newQRCode 编码二维码，内容超过所选级别的容量时返回 PAYLOAD_TOO_LONG
+func newQRCode(text string, level qrcode.RecoveryLevel) (*qrcode.QRCode, error) {
+	qrc, err := qrcode.New(text, level)
 	if err != nil {
-		return color.RGBA{}, err
+		return nil, errcode.Errorf(errcode.PayloadTooLong, "failed to create QR code: %w", err)
 	}
-	return color.RGBA{R: uint8(r), G: uint8(g), B: uint8(b), A: 255}, nil
+	return qrc, nil
 }
 
 // addMarginToQRCode 添加边距到二维码图像
-func addMarginToQRCode(qrImage []byte, size int, margin int) image.Image {
-	img, _ := png.Decode(bytes.NewReader(qrImage))
-
+func addMarginToQRCode(img image.Image, size int, margin int, bgColor color.Color) *image.RGBA {
 	// 创建带边距的新图像
 	newImg := image.NewRGBA(image.Rect(0, 0, size, size))
-	bgColor := color.White
 	draw.Draw(newImg, newImg.Bounds(), &image.Uniform{bgColor}, image.Point{}, draw.Src)
 
 	// 将二维码图像绘制到新图像中，应用边距
@@ -0,0 +1,72 @@
+package qrcode
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+)
+
+// TestEncode 测试默认参数、选项和无效的错误校验级别
+func TestEncode(t *testing.T) {
+	ctx := context.Background()
+	img, err := Encode(ctx, "https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 256 || b.Dy() != 256 {
+		t.Errorf("default size: %v", b)
+	}
+
+	red := color.RGBA{255, 0, 0, 255}
+	img, err = Encode(ctx, "https://example.com", WithLevel(LevelHigh), WithSize(120), WithMargin(10), WithColor(red))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 120 {
+		t.Errorf("size: %v", b)
+	}
+	if got := color.RGBAModel.Convert(img.At(5, 5)); got != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("margin: got %v", got)
+	}
+	if got := color.RGBAModel.Convert(img.At(11, 11)); got != red {
+		t.Errorf("finder pattern: got %v", got)
+	}
+
+	if _, err := Encode(ctx, "x", WithLevel("X")); err == nil {
+		t.Error("invalid level: expected an error")
+	}
+	if _, err := ParseLevel("q"); err == nil {
+		t.Error("ParseLevel(\"q\"): expected an error")
+	}
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := Encode(canceled, "x"); err != context.Canceled {
+		t.Errorf("canceled: %v", err)
+	}
+}
+
+// TestRenderLogo 测试绘制标志时总是使用 H 级别，以及空标志返回错误
+func TestRenderLogo(t *testing.T) {
+	logo := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	render := func(level Level) *image.RGBA {
+		img, err := Render("https://example.com", Options{Level: level, Size: 200, Logo: logo})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return img.(*image.RGBA)
+	}
+	low, high := render(LevelLow), render(LevelHigh)
+	if string(low.Pix) != string(high.Pix) {
+		t.Error("level L with a logo: expected the same image as level H")
+	}
+
+	// 宽或高为 0 的标志无法按宽高比缩放
+	for _, r := range []image.Rectangle{image.Rect(0, 0, 0, 10), image.Rect(0, 0, 10, 0), {}} {
+		if _, err := Render("https://example.com", Options{Level: LevelHigh, Size: 200, Logo: image.NewRGBA(r)}); errcode.Of(err) != errcode.InvalidSize {
+			t.Errorf("empty logo %v: got %v", r, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Package ratelimit 使用令牌桶限制客户端的请求频率
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// Limit 是令牌桶的参数
+type Limit struct {
+	Rate  float64 // 每秒补充的令牌数
+	Burst int     // 桶的容量，即允许的突发请求数
+}
+
+// Per 返回每 d 时间允许 n 个请求、最多突发 burst 个请求的限制
+func Per(n int, d time.Duration, burst int) Limit {
+	return Limit{Rate: float64(n) / d.Seconds(), Burst: burst}
+}
+
+// Result 是一次取令牌的结果
+type Result struct {
+	Allowed    bool          // 是否允许请求
+	Limit      int           // 桶的容量
+	Remaining  int           // 剩余的令牌数
+	Reset      time.Duration // 令牌补满所需的时间
+	RetryAfter time.Duration // 被拒绝时距离下一个令牌的时间
+}
+
+// Store 保存令牌桶的状态，多个实例共享限流时可以实现基于 Redis 等的存储
+type Store interface {
+	// Take 从 key 对应的令牌桶中取一个令牌
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
+
+// Bucket 是令牌桶的状态，共享存储可以序列化保存后调用 Take 计算
+type Bucket struct {
+	Tokens  float64   // 上次更新时的令牌数
+	Updated time.Time // 上次更新时间，零值表示新的桶
+}
+
+// Take 按经过的时间补充令牌后取一个令牌
+func (b *Bucket) Take(limit Limit, now time.Time) Result {
+	burst := float64(limit.Burst)
+	if b.Updated.IsZero() {
+		b.Tokens = burst
+	} else if elapsed := now.Sub(b.Updated).Seconds(); elapsed > 0 {
+		b.Tokens = math.Min(burst, b.Tokens+elapsed*limit.Rate)
+	}
+	b.Updated = now
+
+	res := Result{Limit: limit.Burst}
+	if b.Tokens >= 1 {
+		b.Tokens--
+		res.Allowed = true
+	} else {
+		res.RetryAfter = seconds((1 - b.Tokens) / limit.Rate)
+	}
+	res.Remaining = int(b.Tokens)
+	res.Reset = seconds((burst - b.Tokens) / limit.Rate)
+	return res
+}
+
+// full 判断到 now 时令牌桶是否已经补满
+func (b *Bucket) full(limit Limit, now time.Time) bool {
+	return b.Tokens+now.Sub(b.Updated).Seconds()*limit.Rate >= float64(limit.Burst)
+}
+
+// seconds 将秒数转换为时长
+func seconds(s float64) time.Duration {
+	return time.Duration(s * float64(time.Second))
+}
+
+// MemoryStore 是进程内的令牌桶存储
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*memoryBucket
+	swept   time.Time
+}
+
+// memoryBucket 记录令牌桶和它的限制，用于清理已补满的桶
+type memoryBucket struct {
+	Bucket
+	limit Limit
+}
+
+// NewMemoryStore 创建进程内的令牌桶存储
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*memoryBucket{}}
+}
+
+// Take 从 key 对应的令牌桶中取一个令牌
+// 每分钟清理一次已经补满的桶，补满的桶与新桶等价，删除后不影响限流
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.swept) >= time.Minute {
+		for k, b := range s.buckets {
+			if b.full(b.limit, now) {
+				delete(s.buckets, k)
+			}
+		}
+		s.swept = now
+	}
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &memoryBucket{}
+		s.buckets[key] = b
+	}
+	b.limit = limit
+	return b.Take(limit, now), nil
+}
@@ -0,0 +1,46 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestMemoryStore 测试突发请求、补充令牌和按键隔离
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	limit := Per(1, time.Second, 3)
+	now := time.Unix(1700000000, 0)
+	take := func(key string) Result {
+		res, err := s.Take(context.Background(), key, limit, now)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res
+	}
+
+	for i := 2; i >= 0; i-- {
+		if res := take("a"); !res.Allowed || res.Remaining != i {
+			t.Fatalf("burst request: %+v, want remaining %d", res, i)
+		}
+	}
+	res := take("a")
+	if res.Allowed || res.RetryAfter != time.Second || res.Reset != 3*time.Second {
+		t.Errorf("over the limit: %+v", res)
+	}
+	if res := take("b"); !res.Allowed {
+		t.Error("other keys must have their own bucket")
+	}
+
+	now = now.Add(1500 * time.Millisecond)
+	if res := take("a"); !res.Allowed || res.Remaining != 0 {
+		t.Errorf("after refill: %+v", res)
+	}
+
+	// 补满的桶在清理后与新桶相同
+	now = now.Add(time.Hour)
+	take("c")
+	if _, ok := s.buckets["b"]; ok {
+		t.Error("full buckets should be swept")
+	}
+}
@@ -0,0 +1,169 @@
+package richtext
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+)
+
+// MaxSize 是片段相对基准字号的最大倍数
+const MaxSize = 4
+
+// ParseMarkup 解析简单的标签语法，返回按行拆分的文字片段
+//
+// 支持的标签：
+//
+//	<b>...</b>            粗体
+//	<u>...</u>            下划线
+//	<mono>...</mono>      等宽排列
+//	<color=#f00>...</color> 文字颜色
+//	<bg=#ff0>...</bg>     背景高亮颜色
+//	<size=1.5>...</size>  相对基准字号的倍数，不能大于 MaxSize
+//	<br>                  换行，文本中的 \n 同样表示换行
+//
+// 文本中的 &lt; &gt; &amp; 分别表示 < > &
+func ParseMarkup(markup string) ([]Line, error) {
+	var (
+		lines = []Line{{}}
+		stack []Span // 当前生效的样式，栈顶为最内层标签
+		text  strings.Builder
+	)
+
+	current := func() Span {
+		if len(stack) == 0 {
+			return Span{}
+		}
+		return stack[len(stack)-1]
+	}
+	flush := func() {
+		if text.Len() == 0 {
+			return
+		}
+		span := current()
+		span.Text = unescape(text.String())
+		lines[len(lines)-1] = append(lines[len(lines)-1], span)
+		text.Reset()
+	}
+
+	for i := 0; i < len(markup); {
+		switch markup[i] {
+		case '\n':
+			flush()
+			lines = append(lines, Line{})
+			i++
+			continue
+		case '<':
+		default:
+			text.WriteByte(markup[i])
+			i++
+			continue
+		}
+
+		end := strings.IndexByte(markup[i:], '>')
+		if end < 0 {
+			return nil, errcode.Errorf(errcode.InvalidMarkup, "unclosed tag at offset %d", i)
+		}
+		tag := markup[i+1 : i+end]
+		i += end + 1
+		flush()
+
+		if tag == "br" || tag == "br/" {
+			lines = append(lines, Line{})
+			continue
+		}
+
+		// 闭合标签
+		if strings.HasPrefix(tag, "/") {
+			name := tag[1:]
+			if len(stack) == 0 || stack[len(stack)-1].tag != name {
+				return nil, errcode.Errorf(errcode.InvalidMarkup, "unexpected closing tag </%s>", name)
+			}
+			stack = stack[:len(stack)-1]
+			continue
+		}
+
+		// 开始标签，继承外层样式
+		name, value, _ := strings.Cut(tag, "=")
+		span := current()
+		span.tag = name
+		switch name {
+		case "b":
+			span.Bold = true
+		case "u":
+			span.Underline = true
+		case "mono":
+			span.Mono = true
+		case "color":
+			span.Color = value
+		case "bg":
+			span.Background = value
+		case "size":
+			size, err := strconv.ParseFloat(value, 64)
+			if err != nil || size <= 0 || size > MaxSize {
+				return nil, errcode.Errorf(errcode.InvalidMarkup, "invalid size %q: must be greater than 0 and at most %d", value, MaxSize)
+			}
+			span.Size = size
+		default:
+			return nil, errcode.Errorf(errcode.InvalidMarkup, "unknown tag <%s>", tag)
+		}
+		if (name == "color" || name == "bg") && value == "" {
+			return nil, errcode.Errorf(errcode.InvalidMarkup, "tag <%s> requires a value", name)
+		}
+		stack = append(stack, span)
+	}
+	flush()
+
+	if len(stack) > 0 {
+		return nil, errcode.Errorf(errcode.InvalidMarkup, "unclosed tag <%s>", stack[len(stack)-1].tag)
+	}
+	return lines, nil
+}
+
+// unescape 还原 &lt; &gt; &amp; 转义
+func unescape(s string) string {
+	return strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&").Replace(s)
+}
+
+// ParseJSON 解析 JSON 片段列表
+// 支持二维数组（每个子数组为一行）或一维数组（文字中的 \n 表示换行）
+func ParseJSON(data []byte) ([]Line, error) {
+	var lines []Line
+	if err := json.Unmarshal(data, &lines); err == nil {
+		return lines, checkSizes(lines)
+	}
+
+	var spans []Span
+	if err := json.Unmarshal(data, &spans); err != nil {
+		return nil, errcode.Errorf(errcode.InvalidMarkup, "invalid spans: %v", err)
+	}
+	lines = []Line{{}}
+	for _, span := range spans {
+		parts := strings.Split(span.Text, "\n")
+		for i, part := range parts {
+			if i > 0 {
+				lines = append(lines, Line{})
+			}
+			if part == "" {
+				continue
+			}
+			s := span
+			s.Text = part
+			lines[len(lines)-1] = append(lines[len(lines)-1], s)
+		}
+	}
+	return lines, checkSizes(lines)
+}
+
+// checkSizes 检查片段的字号倍数，0 表示基准字号
+func checkSizes(lines []Line) error {
+	for _, line := range lines {
+		for _, span := range line {
+			if span.Size < 0 || span.Size > MaxSize {
+				return errcode.Errorf(errcode.InvalidMarkup, "invalid size %g: must be between 0 and %d", span.Size, MaxSize)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package richtext
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+)
+
+// TestParseMarkup 测试 ParseMarkup 方法
+func TestParseMarkup(t *testing.T) {
+	lines, err := ParseMarkup("<b><color=#f00>TR</color></b>x9 <u>a&lt;b</u><br><bg=ff0><size=1.5>end</size></bg>")
+	if err != nil {
+		t.Fatalf("ParseMarkup: %v", err)
+	}
+
+	want := []Line{
+		{
+			{Text: "TR", Bold: true, Color: "#f00", tag: "color"},
+			{Text: "x9 "},
+			{Text: "a<b", Underline: true, tag: "u"},
+		},
+		{
+			{Text: "end", Background: "ff0", Size: 1.5, tag: "size"},
+		},
+	}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("ParseMarkup: expected %+v, got %+v", want, lines)
+	}
+}
+
+// TestParseMarkupErrors 测试 ParseMarkup 的错误处理
+func TestParseMarkupErrors(t *testing.T) {
+	for _, markup := range []string{
+		"<b>unclosed",
+		"<b>mismatch</u>",
+		"<i>unknown</i>",
+		"<size=big>x</size>",
+		"<size=0>x</size>",
+		"<size=4.5>x</size>",
+		"<size=3000>x</size>",
+		"<color>x</color>",
+		"broken <b",
+	} {
+		if _, err := ParseMarkup(markup); errcode.Of(err) != errcode.InvalidMarkup {
+			t.Errorf("ParseMarkup(%q): expected error", markup)
+		}
+	}
+}
+
+// TestParseJSON 测试 ParseJSON 方法
+func TestParseJSON(t *testing.T) {
+	flat, err := ParseJSON([]byte(`[{"text":"ab\ncd","color":"red"},{"text":"ef","bold":true}]`))
+	if err != nil {
+		t.Fatalf("ParseJSON: %v", err)
+	}
+	want := []Line{
+		{{Text: "ab", Color: "red"}},
+		{{Text: "cd", Color: "red"}, {Text: "ef", Bold: true}},
+	}
+	if !reflect.DeepEqual(flat, want) {
+		t.Errorf("ParseJSON: expected %+v, got %+v", want, flat)
+	}
+
+	nested, err := ParseJSON([]byte(`[[{"text":"ab"}],[{"text":"cd","underline":true}]]`))
+	if err != nil {
+		t.Fatalf("ParseJSON: %v", err)
+	}
+	if len(nested) != 2 || nested[1][0].Text != "cd" || !nested[1][0].Underline {
+		t.Errorf("ParseJSON: unexpected result %+v", nested)
+	}
+
+	if _, err := ParseJSON([]byte(`{"text":"x"}`)); err == nil {
+		t.Errorf("ParseJSON: expected error for object")
+	}
+
+	// 字号倍数的范围与标签语法相同，0 表示基准字号
+	if _, err := ParseJSON([]byte(`[{"text":"a","size":4},{"text":"b"}]`)); err != nil {
+		t.Errorf("ParseJSON: unexpected error %v", err)
+	}
+	for _, data := range []string{`[{"text":"a","size":4.5}]`, `[[{"text":"a"}],[{"text":"b","size":-1}]]`} {
+		if _, err := ParseJSON([]byte(data)); errcode.Of(err) != errcode.InvalidMarkup {
+			t.Errorf("ParseJSON(%s): expected INVALID_MARKUP, got %v", data, err)
+		}
+	}
+}
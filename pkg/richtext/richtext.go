@@ -0,0 +1,238 @@
+package richtext
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"unicode/utf8"
+
+	"github.com/bitqiu/pix-gen/pkg/colors"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/math/fixed"
+)
+
+// Span 是一段样式相同的文字
+type Span struct {
+	Text       string  `json:"text" doc:"文字内容"`
+	Color      string  `json:"color,omitempty" doc:"文字颜色，为空时使用默认颜色"`
+	Background string  `json:"background,omitempty" doc:"背景高亮颜色，为空时不绘制背景"`
+	Size       float64 `json:"size,omitempty" doc:"相对基准字号的倍数，为 0 时等于 1，不能大于 4"`
+	Bold       bool    `json:"bold,omitempty" doc:"是否加粗"`
+	Underline  bool    `json:"underline,omitempty" doc:"是否添加下划线"`
+	Mono       bool    `json:"mono,omitempty" doc:"是否等宽排列，每个字符占用相同宽度"`
+
+	tag string // 解析标签语法时记录对应的标签名
+}
+
+// Line 是一行文字
+type Line []Span
+
+// Options 是绘制文字时的全局参数
+type Options struct {
+	Font     *truetype.Font // 字体
+	FontSize float64        // 基准字号
+	Color    color.Color    // 默认文字颜色
+	LineGap  int            // 行间距
+	Align    string         // 水平对齐方式：left、center、right，默认为 center
+}
+
+// run 是排版后的一段文字
+type run struct {
+	text      string
+	face      font.Face
+	color     color.Color
+	bg        color.Color
+	bold      int // 加粗时的横向偏移量，为 0 表示不加粗
+	underline bool
+	cell      int // 等宽排列时每个字符的宽度，为 0 表示按字形宽度排列
+	ascent    int
+	descent   int
+	width     int
+}
+
+// row 是排版后的一行
+type row struct {
+	runs    []run
+	width   int
+	ascent  int
+	descent int
+}
+
+// Layout 是排版结果，可以多次绘制
+type Layout struct {
+	rows   []row
+	Width  int // 文本块宽度
+	Height int // 文本块高度
+	gap    int
+	align  string
+}
+
+// NewLayout 对文字进行排版
+func NewLayout(lines []Line, opts Options) (*Layout, error) {
+	if opts.Font == nil {
+		return nil, errcode.New(errcode.FontMissing, "font is required")
+	}
+	if opts.Color == nil {
+		opts.Color = color.Black
+	}
+
+	switch opts.Align {
+	case "":
+		opts.Align = "center"
+	case "left", "center", "right":
+	default:
+		return nil, errcode.Errorf(errcode.InvalidRequest, "invalid align %q", opts.Align)
+	}
+
+	l := &Layout{gap: opts.LineGap, align: opts.Align}
+	faces := map[float64]font.Face{}
+	for i, line := range lines {
+		var r row
+		for _, span := range line {
+			scale := span.Size
+			if scale <= 0 {
+				scale = 1
+			}
+			size := opts.FontSize * scale
+			face, ok := faces[size]
+			if !ok {
+				face = truetype.NewFace(opts.Font, &truetype.Options{Size: size, DPI: 72})
+				faces[size] = face
+			}
+
+			ru := run{text: span.Text, face: face, color: opts.Color, underline: span.Underline}
+			if span.Color != "" {
+				c, err := colors.Parse(span.Color)
+				if err != nil {
+					return nil, errcode.Errorf(errcode.InvalidColor, "invalid color %q", span.Color)
+				}
+				ru.color = c
+			}
+			if span.Background != "" {
+				c, err := colors.Parse(span.Background)
+				if err != nil {
+					return nil, errcode.Errorf(errcode.InvalidColor, "invalid background %q", span.Background)
+				}
+				ru.bg = c
+			}
+			if span.Bold {
+				// 没有粗体字形时通过横向偏移重复绘制模拟加粗
+				ru.bold = int(math.Max(1, size/24))
+			}
+
+			metrics := face.Metrics()
+			ru.ascent = metrics.Ascent.Ceil()
+			ru.descent = metrics.Descent.Ceil()
+			if span.Mono {
+				ru.cell = cellWidth(face) + ru.bold
+				ru.width = ru.cell * utf8.RuneCountInString(span.Text)
+			} else {
+				ru.width = font.MeasureString(face, span.Text).Ceil() + ru.bold
+			}
+
+			r.runs = append(r.runs, ru)
+			r.width += ru.width
+			r.ascent = max(r.ascent, ru.ascent)
+			r.descent = max(r.descent, ru.descent)
+		}
+
+		// 空行按基准字号计算高度
+		if len(r.runs) == 0 {
+			face := truetype.NewFace(opts.Font, &truetype.Options{Size: opts.FontSize, DPI: 72})
+			r.ascent = face.Metrics().Ascent.Ceil()
+			r.descent = face.Metrics().Descent.Ceil()
+		}
+
+		l.rows = append(l.rows, r)
+		l.Width = max(l.Width, r.width)
+		l.Height += r.ascent + r.descent
+		if i > 0 {
+			l.Height += l.gap
+		}
+	}
+	return l, nil
+}
+
+// Draw 将文本块绘制到 dst 中，每行在 rect 内按对齐方式排列，整体垂直居中
+func (l *Layout) Draw(dst draw.Image, rect image.Rectangle) {
+	y := rect.Min.Y + (rect.Dy()-l.Height)/2
+	for _, r := range l.rows {
+		x := rect.Min.X + (rect.Dx()-r.width)/2
+		switch l.align {
+		case "left":
+			x = rect.Min.X
+		case "right":
+			x = rect.Max.X - r.width
+		}
+		baseline := y + r.ascent
+		for _, ru := range r.runs {
+			ru.draw(dst, x, baseline)
+			x += ru.width
+		}
+		y += r.ascent + r.descent + l.gap
+	}
+}
+
+// draw 在 x 和基线 baseline 处绘制一段文字
+func (ru run) draw(dst draw.Image, x, baseline int) {
+	if ru.bg != nil {
+		bg := image.Rect(x, baseline-ru.ascent, x+ru.width, baseline+ru.descent)
+		draw.Draw(dst, bg, image.NewUniform(ru.bg), image.Point{}, draw.Over)
+	}
+
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(ru.color),
+		Face: ru.face,
+	}
+	if ru.cell > 0 {
+		// 等宽排列时每个字符在自己的单元格内居中
+		for i, r := range []rune(ru.text) {
+			adv, _ := ru.face.GlyphAdvance(r)
+			left := x + i*ru.cell + (ru.cell-ru.bold-adv.Ceil())/2
+			for j := 0; j <= ru.bold; j++ {
+				d.Dot = fixed.P(left+j, baseline)
+				d.DrawString(string(r))
+			}
+		}
+	} else {
+		for i := 0; i <= ru.bold; i++ {
+			d.Dot = fixed.P(x+i, baseline)
+			d.DrawString(ru.text)
+		}
+	}
+
+	if ru.underline {
+		thickness := max(1, ru.ascent/12)
+		top := baseline + max(1, ru.descent/3)
+		line := image.Rect(x, top, x+ru.width, top+thickness)
+		draw.Draw(dst, line, image.NewUniform(ru.color), image.Point{}, draw.Over)
+	}
+}
+
+// cellWidth 返回等宽排列时的单元格宽度，即数字和字母中最大的字形宽度
+// M、W 等最宽的字母同样是地址中的有效字符，必须放得下，否则会与相邻字符重叠
+func cellWidth(face font.Face) int {
+	var w fixed.Int26_6
+	for _, chars := range []string{"0123456789", "ABCDEFGHIJKLMNOPQRSTUVWXYZ", "abcdefghijklmnopqrstuvwxyz"} {
+		for _, r := range chars {
+			if adv, ok := face.GlyphAdvance(r); ok && adv > w {
+				w = adv
+			}
+		}
+	}
+	return w.Ceil()
+}
+
+// Draw 排版并绘制文字，文本块在 dst 的范围内居中
+func Draw(dst draw.Image, lines []Line, opts Options) error {
+	l, err := NewLayout(lines, opts)
+	if err != nil {
+		return err
+	}
+	l.Draw(dst, dst.Bounds())
+	return nil
+}
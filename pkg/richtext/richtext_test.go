@@ -0,0 +1,82 @@
+package richtext
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/golang/freetype"
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// TestDraw 测试背景高亮和文字颜色的绘制
+func TestDraw(t *testing.T) {
+	f, err := freetype.ParseFont(goregular.TTF)
+	if err != nil {
+		t.Fatalf("failed to parse font: %v", err)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, 200, 60))
+	lines := []Line{{
+		{Text: "AB", Background: "ffff00"},
+		{Text: "CD", Color: "ff0000", Bold: true, Underline: true},
+	}}
+	if err := Draw(img, lines, Options{Font: f, FontSize: 20}); err != nil {
+		t.Fatalf("Draw: %v", err)
+	}
+
+	var yellow, red bool
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 200; x++ {
+			switch img.RGBAAt(x, y) {
+			case color.RGBA{255, 255, 0, 255}:
+				yellow = true
+			case color.RGBA{255, 0, 0, 255}:
+				red = true
+			}
+		}
+	}
+	if !yellow || !red {
+		t.Errorf("Draw: expected yellow background and red text, got yellow=%v red=%v", yellow, red)
+	}
+}
+
+// TestNewLayout 测试排版尺寸计算
+func TestNewLayout(t *testing.T) {
+	f, err := freetype.ParseFont(goregular.TTF)
+	if err != nil {
+		t.Fatalf("failed to parse font: %v", err)
+	}
+
+	small, err := NewLayout([]Line{{{Text: "abc"}}}, Options{Font: f, FontSize: 20})
+	if err != nil {
+		t.Fatalf("NewLayout: %v", err)
+	}
+	large, err := NewLayout([]Line{{{Text: "abc", Size: 2}}, {{Text: "abc"}}}, Options{Font: f, FontSize: 20, LineGap: 10})
+	if err != nil {
+		t.Fatalf("NewLayout: %v", err)
+	}
+	if large.Width <= small.Width || large.Height <= small.Height*2 {
+		t.Errorf("NewLayout: expected larger block, got %dx%d vs %dx%d", large.Width, large.Height, small.Width, small.Height)
+	}
+
+	if _, err := NewLayout([]Line{{{Text: "x", Color: "nope"}}}, Options{Font: f, FontSize: 20}); err == nil {
+		t.Errorf("NewLayout: expected error for invalid color")
+	}
+}
+
+// TestCellWidth 测试等宽单元格放得下最宽的字母
+func TestCellWidth(t *testing.T) {
+	f, err := freetype.ParseFont(goregular.TTF)
+	if err != nil {
+		t.Fatalf("failed to parse font: %v", err)
+	}
+	face := truetype.NewFace(f, &truetype.Options{Size: 20})
+	cell := cellWidth(face)
+	for _, r := range "MWmw" {
+		if adv, _ := face.GlyphAdvance(r); adv.Ceil() > cell {
+			t.Errorf("cellWidth = %d, %q is %d wide", cell, r, adv.Ceil())
+		}
+	}
+}
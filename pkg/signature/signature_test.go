@@ -0,0 +1,86 @@
+package signature
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// TestSignVerify 测试签名校验、篡改参数、过期和密钥轮换
+func TestSignVerify(t *testing.T) {
+	old := Key{ID: "k1", Secret: []byte("old secret")}
+	current := Key{ID: "k2", Secret: []byte("new secret")}
+	now := time.Unix(1700000000, 0)
+
+	oldSigner, _ := New(old)
+	signer, err := New(current, old)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := url.Values{"text": {"pay to alice"}, "size": {"300"}}
+	signed := signer.Sign("GET", "/qrcode", query, nil, now.Add(time.Hour))
+	if signed.Get(ParamKeyID) != "k2" {
+		t.Errorf("kid = %q, want the first key", signed.Get(ParamKeyID))
+	}
+	if err := signer.Verify("GET", "/qrcode", signed, nil, now); err != nil {
+		t.Errorf("valid signature: %v", err)
+	}
+
+	tampered := url.Values{}
+	for k, v := range signed {
+		tampered[k] = v
+	}
+	tampered.Set("text", "pay to mallory")
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		query  url.Values
+		now    time.Time
+		want   error
+	}{
+		{"unsigned", "GET", "/qrcode", query, now, ErrMissing},
+		{"tampered", "GET", "/qrcode", tampered, now, ErrInvalid},
+		{"other path", "GET", "/image", signed, now, ErrInvalid},
+		{"other method", "POST", "/qrcode", signed, now, ErrInvalid},
+		{"expired", "GET", "/qrcode", signed, now.Add(2 * time.Hour), ErrExpired},
+	}
+	for _, tt := range tests {
+		if err := signer.Verify(tt.method, tt.path, tt.query, nil, tt.now); !errors.Is(err, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+
+	// 旧密钥签发的链接在轮换后仍然有效，未知密钥无效
+	legacy := oldSigner.Sign("GET", "/qrcode", query, nil, time.Time{})
+	if err := signer.Verify("GET", "/qrcode", legacy, nil, now); err != nil {
+		t.Errorf("old key: %v", err)
+	}
+	if err := oldSigner.Verify("GET", "/qrcode", signed, nil, now); !errors.Is(err, ErrInvalid) {
+		t.Errorf("unknown key: got %v", err)
+	}
+
+	// 签名覆盖请求体
+	post := signer.Sign("POST", "/qrcode", nil, []byte(`{"text":"a"}`), time.Time{})
+	if err := signer.Verify("POST", "/qrcode", post, []byte(`{"text":"b"}`), now); !errors.Is(err, ErrInvalid) {
+		t.Errorf("tampered body: got %v", err)
+	}
+}
+
+// TestSignURL 测试为 URL 签名
+func TestSignURL(t *testing.T) {
+	signer, _ := New(Key{ID: "k1", Secret: []byte("secret")})
+	signed, err := signer.SignURL("https://pix.example.com/qrcode?text=hi", time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, _ := url.Parse(signed)
+	if u.Host != "pix.example.com" || u.Query().Get("text") != "hi" {
+		t.Errorf("signed URL = %s", signed)
+	}
+	if err := signer.Verify("GET", u.Path, u.Query(), nil, time.Now()); err != nil {
+		t.Errorf("Verify(%s) = %v", signed, err)
+	}
+}
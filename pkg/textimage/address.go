@@ -0,0 +1,145 @@
+package textimage
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"strings"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/identicon"
+	"github.com/bitqiu/pix-gen/pkg/richtext"
+)
+
+// 高亮字符的颜色和背景色
+const (
+	addressHighlightColor = "d40000"
+	addressHighlightBg    = "ffe08a"
+	addressTipColor       = "ff0000"
+)
+
+// chunkAddress 将地址按 size 个字符一组拆分
+func chunkAddress(address string, size int) []string {
+	runes := []rune(address)
+	var groups []string
+	for i := 0; i < len(runes); i += size {
+		end := min(i+size, len(runes))
+		groups = append(groups, string(runes[i:end]))
+	}
+	return groups
+}
+
+// addressLines 将地址分组排成 rows 行，首尾 highlight 个字符高亮
+// 以太坊等地址的 0x 前缀单独成组且不参与高亮
+func addressLines(address string, opts Options, rows int) []richtext.Line {
+	var prefix string
+	if len(address) > 2 && (address[:2] == "0x" || address[:2] == "0X") {
+		prefix, address = address[:2], address[2:]
+	}
+	total := len([]rune(address))
+	groups := chunkAddress(address, opts.Group)
+	perRow := (len(groups) + rows - 1) / rows
+
+	var lines []richtext.Line
+	pos := 0
+	for i := 0; i < len(groups); i += perRow {
+		var line richtext.Line
+		if prefix != "" && i == 0 {
+			line = append(line, richtext.Span{Text: prefix, Mono: true}, richtext.Span{Text: " ", Mono: true})
+		}
+		for j, g := range groups[i:min(i+perRow, len(groups))] {
+			if j > 0 {
+				line = append(line, richtext.Span{Text: " ", Mono: true})
+			}
+			// 按字符是否高亮拆分为多个片段，同组内样式相同的字符合并
+			start := len(line)
+			for _, r := range g {
+				hl := pos < opts.Highlight || pos >= total-opts.Highlight
+				span := richtext.Span{Text: string(r), Mono: true}
+				if hl {
+					span.Bold = true
+					span.Color = addressHighlightColor
+					span.Background = addressHighlightBg
+				}
+				if n := len(line); n > start && line[n-1].Bold == span.Bold {
+					line[n-1].Text += span.Text
+				} else {
+					line = append(line, span)
+				}
+				pos++
+			}
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// DrawAddress 生成地址核对图片
+// 地址按组等宽排列，首尾字符高亮，可选在右侧绘制由地址哈希生成的指纹图标
+func DrawAddress(address string, opts Options) (image.Image, error) {
+	width, height := opts.Width, opts.Height
+	if width <= 0 || height <= 0 {
+		return nil, errcode.New(errcode.InvalidSize, "width and height must be positive integers")
+	}
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return nil, errcode.New(errcode.InvalidText, "address must not be empty")
+	}
+	if opts.Group <= 0 {
+		return nil, errcode.New(errcode.InvalidRequest, "group must be a positive integer")
+	}
+	if opts.Highlight < 0 {
+		return nil, errcode.New(errcode.InvalidRequest, "highlight must not be negative")
+	}
+
+	if opts.Font == nil {
+		return nil, ErrFontMissing
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), &image.Uniform{color.White}, image.Point{}, draw.Src)
+
+	// 指纹图标占据右侧的正方形区域
+	padding := max(4, height/10)
+	textRect := image.Rect(padding, padding, width-padding, height-padding)
+	if opts.Fingerprint {
+		side := height - 2*padding
+		iconRect := image.Rect(width-padding-side, padding, width-padding, height-padding)
+		identicon.New([]byte(address)).Draw(img, iconRect, color.RGBA{240, 240, 240, 255})
+		textRect.Max.X = iconRect.Min.X - padding
+	}
+	if textRect.Dx() <= 0 || textRect.Dy() <= 0 {
+		return nil, errcode.New(errcode.InvalidSize, "image is too small")
+	}
+
+	// 从按面积计算的字号开始，逐步尝试增加行数和缩小字号直到放得下
+	fontSize := FontSize(width, height)
+	var layout *richtext.Layout
+	for layout == nil && fontSize >= 6 {
+		for rows := 1; rows <= 4; rows++ {
+			lines := addressLines(address, opts, rows)
+			if opts.TipText != "" {
+				lines = append(lines, richtext.Line{{Text: opts.TipText, Color: addressTipColor, Size: 0.6}})
+			}
+			l, err := richtext.NewLayout(lines, richtext.Options{
+				Font:     opts.Font,
+				FontSize: fontSize,
+				Color:    color.Black,
+				LineGap:  int(fontSize / 4),
+			})
+			if err != nil {
+				return nil, errcode.Errorf(errcode.Of(err), "failed to lay out text: %w", err)
+			}
+			if l.Width <= textRect.Dx() && l.Height <= textRect.Dy() {
+				layout = l
+				break
+			}
+		}
+		fontSize *= 0.9
+	}
+	if layout == nil {
+		return nil, errcode.New(errcode.InvalidSize, "image is too small to fit the address")
+	}
+	layout.Draw(img, textRect)
+	return img, nil
+}
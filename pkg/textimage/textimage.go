@@ -0,0 +1,136 @@
+// Package textimage 生成文字图片和地址核对图片，可以直接在其他 Go 程序中使用
+package textimage
+
+import (
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/richtext"
+	"github.com/golang/freetype/truetype"
+)
+
+// ErrFontMissing 表示没有设置字体
+var ErrFontMissing = errcode.New(errcode.FontMissing, "font is not set")
+
+// Options 是生成文字图片的参数
+type Options struct {
+	Width       int            // 图片宽度
+	Height      int            // 图片高度
+	Font        *truetype.Font // 字体
+	TipText     string         // 主文字下方的红色提示文字，为空时不绘制
+	Group       int            // 地址模式每组字符数
+	Highlight   int            // 地址模式首尾各高亮的字符数
+	Fingerprint bool           // 地址模式是否在右侧绘制地址指纹图标
+}
+
+// Option 设置生成文字图片的参数
+type Option func(*Options)
+
+// WithSize 设置图片尺寸，默认为 500x100
+func WithSize(width, height int) Option {
+	return func(o *Options) { o.Width, o.Height = width, height }
+}
+
+// WithFont 设置字体，必须设置
+func WithFont(f *truetype.Font) Option {
+	return func(o *Options) { o.Font = f }
+}
+
+// WithTipText 设置主文字下方的提示文字
+func WithTipText(text string) Option {
+	return func(o *Options) { o.TipText = text }
+}
+
+// WithGroup 设置地址模式每组字符数，默认为 4
+func WithGroup(n int) Option {
+	return func(o *Options) { o.Group = n }
+}
+
+// WithHighlight 设置地址模式首尾各高亮的字符数，默认为 4
+func WithHighlight(n int) Option {
+	return func(o *Options) { o.Highlight = n }
+}
+
+// WithFingerprint 设置地址模式是否绘制地址指纹图标
+func WithFingerprint(on bool) Option {
+	return func(o *Options) { o.Fingerprint = on }
+}
+
+// newOptions 返回应用了 opts 的参数
+func newOptions(opts []Option) Options {
+	o := Options{Width: 500, Height: 100, Group: 4, Highlight: 4}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// Render 生成黑色主文字加红色提示文字的图片，文本块居中
+func Render(ctx context.Context, text string, opts ...Option) (image.Image, error) {
+	o := newOptions(opts)
+	lines := []richtext.Line{{{Text: text}}}
+	if o.TipText != "" {
+		lines = append(lines, richtext.Line{{Text: o.TipText, Color: "ff0000"}})
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return DrawLines(lines, o)
+}
+
+// RenderLines 生成富文本图片，lines 可以由 richtext.ParseMarkup 解析得到
+func RenderLines(ctx context.Context, lines []richtext.Line, opts ...Option) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return DrawLines(lines, newOptions(opts))
+}
+
+// RenderAddress 生成地址核对图片，地址分组等宽排列，首尾字符高亮
+func RenderAddress(ctx context.Context, address string, opts ...Option) (image.Image, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return DrawAddress(address, newOptions(opts))
+}
+
+// FontSize 返回 width x height 的图片的基准字号，富文本片段的字号为基准字号乘以 Span.Size
+func FontSize(width, height int) float64 {
+	return math.Sqrt(float64(width*height) / 100)
+}
+
+// DrawLines 生成富文本图片，字号按图片面积计算，文本块水平和垂直居中
+func DrawLines(lines []richtext.Line, opts Options) (image.Image, error) {
+	// 检查 width 和 height 的边界条件
+	if opts.Width <= 0 || opts.Height <= 0 {
+		return nil, errcode.New(errcode.InvalidSize, "width and height must be positive integers")
+	}
+	if opts.Font == nil {
+		return nil, ErrFontMissing
+	}
+
+	// 根据图像尺寸动态计算字体大小
+	fontSize := FontSize(opts.Width, opts.Height)
+
+	// 创建一个新的 RGBA 图像，背景为白色
+	img := image.NewRGBA(image.Rect(0, 0, opts.Width, opts.Height))
+	bgColor := color.RGBA{255, 255, 255, 255}
+	draw.Draw(img, img.Bounds(), &image.Uniform{bgColor}, image.Point{}, draw.Src)
+
+	// 绘制文字，文本块水平和垂直居中，两行文字间距 10 像素
+	err := richtext.Draw(img, lines, richtext.Options{
+		Font:     opts.Font,
+		FontSize: fontSize,
+		Color:    color.Black,
+		LineGap:  10,
+	})
+	if err != nil {
+		return nil, errcode.Errorf(errcode.Of(err), "failed to draw text: %w", err)
+	}
+
+	return img, nil
+}
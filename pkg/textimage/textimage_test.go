@@ -0,0 +1,62 @@
+package textimage
+
+import (
+	"context"
+	"image/color"
+	"testing"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// TestRender 测试文字图片的尺寸、文字绘制和缺少字体时的错误
+func TestRender(t *testing.T) {
+	f, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	img, err := Render(ctx, "hello", WithFont(f), WithSize(200, 60), WithTipText("check"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b := img.Bounds(); b.Dx() != 200 || b.Dy() != 60 {
+		t.Errorf("size: %v", b)
+	}
+	var red, dark bool
+	for y := 0; y < 60; y++ {
+		for x := 0; x < 200; x++ {
+			c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+			red = red || c.R > 200 && c.G < 80
+			dark = dark || c.R < 80 && c.G < 80
+		}
+	}
+	if !red || !dark {
+		t.Errorf("expected black text and red tip text, got black %t red %t", dark, red)
+	}
+
+	if _, err := Render(ctx, "hello"); err == nil {
+		t.Error("missing font: expected an error")
+	}
+}
+
+// TestRenderAddress 测试地址核对图片和放不下地址时的错误
+func TestRenderAddress(t *testing.T) {
+	f, err := truetype.Parse(goregular.TTF)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	address := "TQn9Y2khEsLJW1ChVWFMSMeRDow5KcbLSE"
+
+	if _, err := RenderAddress(ctx, address, WithFont(f), WithFingerprint(true)); err != nil {
+		t.Error(err)
+	}
+	if _, err := RenderAddress(ctx, address, WithFont(f), WithSize(20, 10)); err == nil {
+		t.Error("too small: expected an error")
+	}
+	if _, err := RenderAddress(ctx, address, WithFont(f), WithGroup(0)); err == nil {
+		t.Error("invalid group: expected an error")
+	}
+}
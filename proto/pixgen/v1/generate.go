@@ -0,0 +1,3 @@
+package pixgenv1
+
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative pixgen/v1/pixgen.proto
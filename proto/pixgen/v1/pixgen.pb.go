@@ -0,0 +1,1697 @@
+// pix-gen 的 gRPC 接口，参数和默认值与对应的 HTTP 接口相同，未设置的字段使用默认值
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.1
+// 	protoc        (unknown)
+// source: pixgen/v1/pixgen.proto
+
+package pixgenv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ImageFormat 是输出的图片格式
+type ImageFormat int32
+
+const (
+	ImageFormat_IMAGE_FORMAT_UNSPECIFIED ImageFormat = 0 // PNG
+	ImageFormat_IMAGE_FORMAT_PNG         ImageFormat = 1
+	ImageFormat_IMAGE_FORMAT_JPEG        ImageFormat = 2
+	ImageFormat_IMAGE_FORMAT_GIF         ImageFormat = 3
+	ImageFormat_IMAGE_FORMAT_WEBP        ImageFormat = 4
+	ImageFormat_IMAGE_FORMAT_BMP         ImageFormat = 5
+)
+
+// Enum value maps for ImageFormat.
+var (
+	ImageFormat_name = map[int32]string{
+		0: "IMAGE_FORMAT_UNSPECIFIED",
+		1: "IMAGE_FORMAT_PNG",
+		2: "IMAGE_FORMAT_JPEG",
+		3: "IMAGE_FORMAT_GIF",
+		4: "IMAGE_FORMAT_WEBP",
+		5: "IMAGE_FORMAT_BMP",
+	}
+	ImageFormat_value = map[string]int32{
+		"IMAGE_FORMAT_UNSPECIFIED": 0,
+		"IMAGE_FORMAT_PNG":         1,
+		"IMAGE_FORMAT_JPEG":        2,
+		"IMAGE_FORMAT_GIF":         3,
+		"IMAGE_FORMAT_WEBP":        4,
+		"IMAGE_FORMAT_BMP":         5,
+	}
+)
+
+func (x ImageFormat) Enum() *ImageFormat {
+	p := new(ImageFormat)
+	*p = x
+	return p
+}
+
+func (x ImageFormat) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ImageFormat) Descriptor() protoreflect.EnumDescriptor {
+	return file_pixgen_v1_pixgen_proto_enumTypes[0].Descriptor()
+}
+
+func (ImageFormat) Type() protoreflect.EnumType {
+	return &file_pixgen_v1_pixgen_proto_enumTypes[0]
+}
+
+func (x ImageFormat) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ImageFormat.Descriptor instead.
+func (ImageFormat) EnumDescriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{0}
+}
+
+// Compression 是 PNG 压缩级别
+type Compression int32
+
+const (
+	Compression_COMPRESSION_UNSPECIFIED Compression = 0
+	Compression_COMPRESSION_NONE        Compression = 1
+	Compression_COMPRESSION_SPEED       Compression = 2
+	Compression_COMPRESSION_BEST        Compression = 3
+)
+
+// Enum value maps for Compression.
+var (
+	Compression_name = map[int32]string{
+		0: "COMPRESSION_UNSPECIFIED",
+		1: "COMPRESSION_NONE",
+		2: "COMPRESSION_SPEED",
+		3: "COMPRESSION_BEST",
+	}
+	Compression_value = map[string]int32{
+		"COMPRESSION_UNSPECIFIED": 0,
+		"COMPRESSION_NONE":        1,
+		"COMPRESSION_SPEED":       2,
+		"COMPRESSION_BEST":        3,
+	}
+)
+
+func (x Compression) Enum() *Compression {
+	p := new(Compression)
+	*p = x
+	return p
+}
+
+func (x Compression) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Compression) Descriptor() protoreflect.EnumDescriptor {
+	return file_pixgen_v1_pixgen_proto_enumTypes[1].Descriptor()
+}
+
+func (Compression) Type() protoreflect.EnumType {
+	return &file_pixgen_v1_pixgen_proto_enumTypes[1]
+}
+
+func (x Compression) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Compression.Descriptor instead.
+func (Compression) EnumDescriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{1}
+}
+
+// ErrorCorrection 是二维码的错误校验级别
+type ErrorCorrection int32
+
+const (
+	ErrorCorrection_ERROR_CORRECTION_UNSPECIFIED ErrorCorrection = 0 // 使用配置的默认级别
+	ErrorCorrection_ERROR_CORRECTION_L           ErrorCorrection = 1
+	ErrorCorrection_ERROR_CORRECTION_M           ErrorCorrection = 2
+	ErrorCorrection_ERROR_CORRECTION_Q           ErrorCorrection = 3
+	ErrorCorrection_ERROR_CORRECTION_H           ErrorCorrection = 4
+)
+
+// Enum value maps for ErrorCorrection.
+var (
+	ErrorCorrection_name = map[int32]string{
+		0: "ERROR_CORRECTION_UNSPECIFIED",
+		1: "ERROR_CORRECTION_L",
+		2: "ERROR_CORRECTION_M",
+		3: "ERROR_CORRECTION_Q",
+		4: "ERROR_CORRECTION_H",
+	}
+	ErrorCorrection_value = map[string]int32{
+		"ERROR_CORRECTION_UNSPECIFIED": 0,
+		"ERROR_CORRECTION_L":           1,
+		"ERROR_CORRECTION_M":           2,
+		"ERROR_CORRECTION_Q":           3,
+		"ERROR_CORRECTION_H":           4,
+	}
+)
+
+func (x ErrorCorrection) Enum() *ErrorCorrection {
+	p := new(ErrorCorrection)
+	*p = x
+	return p
+}
+
+func (x ErrorCorrection) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ErrorCorrection) Descriptor() protoreflect.EnumDescriptor {
+	return file_pixgen_v1_pixgen_proto_enumTypes[2].Descriptor()
+}
+
+func (ErrorCorrection) Type() protoreflect.EnumType {
+	return &file_pixgen_v1_pixgen_proto_enumTypes[2]
+}
+
+func (x ErrorCorrection) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ErrorCorrection.Descriptor instead.
+func (ErrorCorrection) EnumDescriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{2}
+}
+
+// TextImageMode 是文字图片的模式
+type TextImageMode int32
+
+const (
+	TextImageMode_TEXT_IMAGE_MODE_UNSPECIFIED TextImageMode = 0 // 普通文字
+	TextImageMode_TEXT_IMAGE_MODE_ADDRESS     TextImageMode = 1 // 地址核对模式
+)
+
+// Enum value maps for TextImageMode.
+var (
+	TextImageMode_name = map[int32]string{
+		0: "TEXT_IMAGE_MODE_UNSPECIFIED",
+		1: "TEXT_IMAGE_MODE_ADDRESS",
+	}
+	TextImageMode_value = map[string]int32{
+		"TEXT_IMAGE_MODE_UNSPECIFIED": 0,
+		"TEXT_IMAGE_MODE_ADDRESS":     1,
+	}
+)
+
+func (x TextImageMode) Enum() *TextImageMode {
+	p := new(TextImageMode)
+	*p = x
+	return p
+}
+
+func (x TextImageMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (TextImageMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_pixgen_v1_pixgen_proto_enumTypes[3].Descriptor()
+}
+
+func (TextImageMode) Type() protoreflect.EnumType {
+	return &file_pixgen_v1_pixgen_proto_enumTypes[3]
+}
+
+func (x TextImageMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use TextImageMode.Descriptor instead.
+func (TextImageMode) EnumDescriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{3}
+}
+
+// Output 是所有生成接口共用的输出参数
+type Output struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Format      ImageFormat `protobuf:"varint,1,opt,name=format,proto3,enum=pixgen.v1.ImageFormat" json:"format,omitempty"`
+	Quality     int32       `protobuf:"varint,2,opt,name=quality,proto3" json:"quality,omitempty"` // JPEG 质量 1-100，未设置时为 90
+	Compression Compression `protobuf:"varint,3,opt,name=compression,proto3,enum=pixgen.v1.Compression" json:"compression,omitempty"`
+}
+
+func (x *Output) Reset() {
+	*x = Output{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Output) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Output) ProtoMessage() {}
+
+func (x *Output) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Output.ProtoReflect.Descriptor instead.
+func (*Output) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Output) GetFormat() ImageFormat {
+	if x != nil {
+		return x.Format
+	}
+	return ImageFormat_IMAGE_FORMAT_UNSPECIFIED
+}
+
+func (x *Output) GetQuality() int32 {
+	if x != nil {
+		return x.Quality
+	}
+	return 0
+}
+
+func (x *Output) GetCompression() Compression {
+	if x != nil {
+		return x.Compression
+	}
+	return Compression_COMPRESSION_UNSPECIFIED
+}
+
+// Image 是编码后的图片
+type Image struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ContentType string `protobuf:"bytes,1,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Data        []byte `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *Image) Reset() {
+	*x = Image{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *Image) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Image) ProtoMessage() {}
+
+func (x *Image) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Image.ProtoReflect.Descriptor instead.
+func (*Image) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Image) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *Image) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+type IssueCaptchaRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Width  int32   `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height int32   `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	Length int32   `protobuf:"varint,3,opt,name=length,proto3" json:"length,omitempty"` // 字符数，未设置时使用配置的长度
+	Font   string  `protobuf:"bytes,4,opt,name=font,proto3" json:"font,omitempty"`
+	Output *Output `protobuf:"bytes,5,opt,name=output,proto3" json:"output,omitempty"`
+}
+
+func (x *IssueCaptchaRequest) Reset() {
+	*x = IssueCaptchaRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IssueCaptchaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueCaptchaRequest) ProtoMessage() {}
+
+func (x *IssueCaptchaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueCaptchaRequest.ProtoReflect.Descriptor instead.
+func (*IssueCaptchaRequest) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *IssueCaptchaRequest) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *IssueCaptchaRequest) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *IssueCaptchaRequest) GetLength() int32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *IssueCaptchaRequest) GetFont() string {
+	if x != nil {
+		return x.Font
+	}
+	return ""
+}
+
+func (x *IssueCaptchaRequest) GetOutput() *Output {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+type IssueCaptchaResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Image      *Image                 `protobuf:"bytes,2,opt,name=image,proto3" json:"image,omitempty"`
+	ExpireTime *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expire_time,json=expireTime,proto3" json:"expire_time,omitempty"`
+}
+
+func (x *IssueCaptchaResponse) Reset() {
+	*x = IssueCaptchaResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *IssueCaptchaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueCaptchaResponse) ProtoMessage() {}
+
+func (x *IssueCaptchaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueCaptchaResponse.ProtoReflect.Descriptor instead.
+func (*IssueCaptchaResponse) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *IssueCaptchaResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *IssueCaptchaResponse) GetImage() *Image {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *IssueCaptchaResponse) GetExpireTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpireTime
+	}
+	return nil
+}
+
+type VerifyCaptchaRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Code string `protobuf:"bytes,2,opt,name=code,proto3" json:"code,omitempty"` // 不区分大小写
+}
+
+func (x *VerifyCaptchaRequest) Reset() {
+	*x = VerifyCaptchaRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyCaptchaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyCaptchaRequest) ProtoMessage() {}
+
+func (x *VerifyCaptchaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyCaptchaRequest.ProtoReflect.Descriptor instead.
+func (*VerifyCaptchaRequest) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *VerifyCaptchaRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *VerifyCaptchaRequest) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+type VerifyCaptchaResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Valid bool `protobuf:"varint,1,opt,name=valid,proto3" json:"valid,omitempty"`
+}
+
+func (x *VerifyCaptchaResponse) Reset() {
+	*x = VerifyCaptchaResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *VerifyCaptchaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyCaptchaResponse) ProtoMessage() {}
+
+func (x *VerifyCaptchaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyCaptchaResponse.ProtoReflect.Descriptor instead.
+func (*VerifyCaptchaResponse) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *VerifyCaptchaResponse) GetValid() bool {
+	if x != nil {
+		return x.Valid
+	}
+	return false
+}
+
+type EncodeQRCodeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text   string          `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	Level  ErrorCorrection `protobuf:"varint,2,opt,name=level,proto3,enum=pixgen.v1.ErrorCorrection" json:"level,omitempty"`
+	Size   int32           `protobuf:"varint,3,opt,name=size,proto3" json:"size,omitempty"`
+	Color  string          `protobuf:"bytes,4,opt,name=color,proto3" json:"color,omitempty"` // 颜色名或不含 # 的 16 进制值
+	Margin int32           `protobuf:"varint,5,opt,name=margin,proto3" json:"margin,omitempty"`
+	Output *Output         `protobuf:"bytes,6,opt,name=output,proto3" json:"output,omitempty"`
+}
+
+func (x *EncodeQRCodeRequest) Reset() {
+	*x = EncodeQRCodeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncodeQRCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncodeQRCodeRequest) ProtoMessage() {}
+
+func (x *EncodeQRCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncodeQRCodeRequest.ProtoReflect.Descriptor instead.
+func (*EncodeQRCodeRequest) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *EncodeQRCodeRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *EncodeQRCodeRequest) GetLevel() ErrorCorrection {
+	if x != nil {
+		return x.Level
+	}
+	return ErrorCorrection_ERROR_CORRECTION_UNSPECIFIED
+}
+
+func (x *EncodeQRCodeRequest) GetSize() int32 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+func (x *EncodeQRCodeRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *EncodeQRCodeRequest) GetMargin() int32 {
+	if x != nil {
+		return x.Margin
+	}
+	return 0
+}
+
+func (x *EncodeQRCodeRequest) GetOutput() *Output {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+type DecodeQRCodeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Image []byte `protobuf:"bytes,1,opt,name=image,proto3" json:"image,omitempty"` // PNG、JPEG、GIF、WebP 或 BMP 图片
+}
+
+func (x *DecodeQRCodeRequest) Reset() {
+	*x = DecodeQRCodeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecodeQRCodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeQRCodeRequest) ProtoMessage() {}
+
+func (x *DecodeQRCodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeQRCodeRequest.ProtoReflect.Descriptor instead.
+func (*DecodeQRCodeRequest) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *DecodeQRCodeRequest) GetImage() []byte {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+type DecodeQRCodeResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text string `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *DecodeQRCodeResponse) Reset() {
+	*x = DecodeQRCodeResponse{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *DecodeQRCodeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DecodeQRCodeResponse) ProtoMessage() {}
+
+func (x *DecodeQRCodeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DecodeQRCodeResponse.ProtoReflect.Descriptor instead.
+func (*DecodeQRCodeResponse) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *DecodeQRCodeResponse) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+type EncodeBarcodeRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text       string  `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"` // ASCII 32-126 字符
+	Width      int32   `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height     int32   `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	Color      string  `protobuf:"bytes,4,opt,name=color,proto3" json:"color,omitempty"`
+	Background string  `protobuf:"bytes,5,opt,name=background,proto3" json:"background,omitempty"`
+	Output     *Output `protobuf:"bytes,6,opt,name=output,proto3" json:"output,omitempty"`
+}
+
+func (x *EncodeBarcodeRequest) Reset() {
+	*x = EncodeBarcodeRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *EncodeBarcodeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncodeBarcodeRequest) ProtoMessage() {}
+
+func (x *EncodeBarcodeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncodeBarcodeRequest.ProtoReflect.Descriptor instead.
+func (*EncodeBarcodeRequest) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *EncodeBarcodeRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *EncodeBarcodeRequest) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *EncodeBarcodeRequest) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *EncodeBarcodeRequest) GetColor() string {
+	if x != nil {
+		return x.Color
+	}
+	return ""
+}
+
+func (x *EncodeBarcodeRequest) GetBackground() string {
+	if x != nil {
+		return x.Background
+	}
+	return ""
+}
+
+func (x *EncodeBarcodeRequest) GetOutput() *Output {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+type RenderTextImageRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Text        string        `protobuf:"bytes,1,opt,name=text,proto3" json:"text,omitempty"`
+	TipText     *string       `protobuf:"bytes,2,opt,name=tip_text,json=tipText,proto3,oneof" json:"tip_text,omitempty"` // 未设置时使用配置的提示文字，设置为空字符串时不显示
+	Width       int32         `protobuf:"varint,3,opt,name=width,proto3" json:"width,omitempty"`
+	Height      int32         `protobuf:"varint,4,opt,name=height,proto3" json:"height,omitempty"`
+	Font        string        `protobuf:"bytes,5,opt,name=font,proto3" json:"font,omitempty"`
+	Markup      string        `protobuf:"bytes,6,opt,name=markup,proto3" json:"markup,omitempty"` // 标签语法的富文本
+	Mode        TextImageMode `protobuf:"varint,7,opt,name=mode,proto3,enum=pixgen.v1.TextImageMode" json:"mode,omitempty"`
+	Group       int32         `protobuf:"varint,8,opt,name=group,proto3" json:"group,omitempty"`         // 地址模式每组字符数
+	Highlight   int32         `protobuf:"varint,9,opt,name=highlight,proto3" json:"highlight,omitempty"` // 地址模式首尾高亮字符数
+	Fingerprint bool          `protobuf:"varint,10,opt,name=fingerprint,proto3" json:"fingerprint,omitempty"`
+	Output      *Output       `protobuf:"bytes,11,opt,name=output,proto3" json:"output,omitempty"`
+}
+
+func (x *RenderTextImageRequest) Reset() {
+	*x = RenderTextImageRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RenderTextImageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenderTextImageRequest) ProtoMessage() {}
+
+func (x *RenderTextImageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenderTextImageRequest.ProtoReflect.Descriptor instead.
+func (*RenderTextImageRequest) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RenderTextImageRequest) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+func (x *RenderTextImageRequest) GetTipText() string {
+	if x != nil && x.TipText != nil {
+		return *x.TipText
+	}
+	return ""
+}
+
+func (x *RenderTextImageRequest) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *RenderTextImageRequest) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *RenderTextImageRequest) GetFont() string {
+	if x != nil {
+		return x.Font
+	}
+	return ""
+}
+
+func (x *RenderTextImageRequest) GetMarkup() string {
+	if x != nil {
+		return x.Markup
+	}
+	return ""
+}
+
+func (x *RenderTextImageRequest) GetMode() TextImageMode {
+	if x != nil {
+		return x.Mode
+	}
+	return TextImageMode_TEXT_IMAGE_MODE_UNSPECIFIED
+}
+
+func (x *RenderTextImageRequest) GetGroup() int32 {
+	if x != nil {
+		return x.Group
+	}
+	return 0
+}
+
+func (x *RenderTextImageRequest) GetHighlight() int32 {
+	if x != nil {
+		return x.Highlight
+	}
+	return 0
+}
+
+func (x *RenderTextImageRequest) GetFingerprint() bool {
+	if x != nil {
+		return x.Fingerprint
+	}
+	return false
+}
+
+func (x *RenderTextImageRequest) GetOutput() *Output {
+	if x != nil {
+		return x.Output
+	}
+	return nil
+}
+
+type BatchJob struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Types that are assignable to Params:
+	//	*BatchJob_Qrcode
+	//	*BatchJob_Barcode
+	//	*BatchJob_Image
+	Params isBatchJob_Params `protobuf_oneof:"params"`
+}
+
+func (x *BatchJob) Reset() {
+	*x = BatchJob{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchJob) ProtoMessage() {}
+
+func (x *BatchJob) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchJob.ProtoReflect.Descriptor instead.
+func (*BatchJob) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchJob) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (m *BatchJob) GetParams() isBatchJob_Params {
+	if m != nil {
+		return m.Params
+	}
+	return nil
+}
+
+func (x *BatchJob) GetQrcode() *EncodeQRCodeRequest {
+	if x, ok := x.GetParams().(*BatchJob_Qrcode); ok {
+		return x.Qrcode
+	}
+	return nil
+}
+
+func (x *BatchJob) GetBarcode() *EncodeBarcodeRequest {
+	if x, ok := x.GetParams().(*BatchJob_Barcode); ok {
+		return x.Barcode
+	}
+	return nil
+}
+
+func (x *BatchJob) GetImage() *RenderTextImageRequest {
+	if x, ok := x.GetParams().(*BatchJob_Image); ok {
+		return x.Image
+	}
+	return nil
+}
+
+type isBatchJob_Params interface {
+	isBatchJob_Params()
+}
+
+type BatchJob_Qrcode struct {
+	Qrcode *EncodeQRCodeRequest `protobuf:"bytes,2,opt,name=qrcode,proto3,oneof"`
+}
+
+type BatchJob_Barcode struct {
+	Barcode *EncodeBarcodeRequest `protobuf:"bytes,3,opt,name=barcode,proto3,oneof"`
+}
+
+type BatchJob_Image struct {
+	Image *RenderTextImageRequest `protobuf:"bytes,4,opt,name=image,proto3,oneof"`
+}
+
+func (*BatchJob_Qrcode) isBatchJob_Params() {}
+
+func (*BatchJob_Barcode) isBatchJob_Params() {}
+
+func (*BatchJob_Image) isBatchJob_Params() {}
+
+type BatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Jobs []*BatchJob `protobuf:"bytes,1,rep,name=jobs,proto3" json:"jobs,omitempty"`
+}
+
+func (x *BatchRequest) Reset() {
+	*x = BatchRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchRequest) ProtoMessage() {}
+
+func (x *BatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchRequest.ProtoReflect.Descriptor instead.
+func (*BatchRequest) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchRequest) GetJobs() []*BatchJob {
+	if x != nil {
+		return x.Jobs
+	}
+	return nil
+}
+
+type BatchResult struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Index int32  `protobuf:"varint,1,opt,name=index,proto3" json:"index,omitempty"` // 从 1 开始的任务序号
+	Name  string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Image *Image `protobuf:"bytes,3,opt,name=image,proto3" json:"image,omitempty"`
+	Error string `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"` // 任务失败时的原因，其他任务不受影响
+	Code  string `protobuf:"bytes,5,opt,name=code,proto3" json:"code,omitempty"`   // 任务失败时的错误码
+}
+
+func (x *BatchResult) Reset() {
+	*x = BatchResult{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_pixgen_v1_pixgen_proto_msgTypes[13]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *BatchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchResult) ProtoMessage() {}
+
+func (x *BatchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_pixgen_v1_pixgen_proto_msgTypes[13]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchResult.ProtoReflect.Descriptor instead.
+func (*BatchResult) Descriptor() ([]byte, []int) {
+	return file_pixgen_v1_pixgen_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *BatchResult) GetIndex() int32 {
+	if x != nil {
+		return x.Index
+	}
+	return 0
+}
+
+func (x *BatchResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BatchResult) GetImage() *Image {
+	if x != nil {
+		return x.Image
+	}
+	return nil
+}
+
+func (x *BatchResult) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BatchResult) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+var File_pixgen_v1_pixgen_proto protoreflect.FileDescriptor
+
+var file_pixgen_v1_pixgen_proto_rawDesc = []byte{
+	0x0a, 0x16, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2f, 0x76, 0x31, 0x2f, 0x70, 0x69, 0x78, 0x67,
+	0x65, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e,
+	0x2e, 0x76, 0x31, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0x8c, 0x01, 0x0a, 0x06, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x12,
+	0x2e, 0x0a, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x16, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x52, 0x06, 0x66, 0x6f, 0x72, 0x6d, 0x61, 0x74, 0x12,
+	0x18, 0x0a, 0x07, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x71, 0x75, 0x61, 0x6c, 0x69, 0x74, 0x79, 0x12, 0x38, 0x0a, 0x0b, 0x63, 0x6f, 0x6d,
+	0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x16,
+	0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x72,
+	0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x52, 0x0b, 0x63, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73,
+	0x69, 0x6f, 0x6e, 0x22, 0x3e, 0x0a, 0x05, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x21, 0x0a, 0x0c,
+	0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0b, 0x63, 0x6f, 0x6e, 0x74, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x22, 0x9a, 0x01, 0x0a, 0x13, 0x49, 0x73, 0x73, 0x75, 0x65, 0x43, 0x61, 0x70,
+	0x74, 0x63, 0x68, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x77,
+	0x69, 0x64, 0x74, 0x68, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74,
+	0x68, 0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6c, 0x65, 0x6e,
+	0x67, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6c, 0x65, 0x6e, 0x67, 0x74,
+	0x68, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x6f, 0x6e, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x66, 0x6f, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x22, 0x8b, 0x01, 0x0a, 0x14, 0x49, 0x73, 0x73, 0x75, 0x65, 0x43, 0x61, 0x70, 0x74, 0x63, 0x68,
+	0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x26, 0x0a, 0x05, 0x69, 0x6d, 0x61,
+	0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x12, 0x3b, 0x0a, 0x0b, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x5f, 0x74, 0x69, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x0a, 0x65, 0x78, 0x70, 0x69, 0x72, 0x65, 0x54, 0x69, 0x6d, 0x65, 0x22, 0x3a,
+	0x0a, 0x14, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x43, 0x61, 0x70, 0x74, 0x63, 0x68, 0x61, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x02, 0x69, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x22, 0x2d, 0x0a, 0x15, 0x56, 0x65,
+	0x72, 0x69, 0x66, 0x79, 0x43, 0x61, 0x70, 0x74, 0x63, 0x68, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f,
+	0x6e, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x05, 0x76, 0x61, 0x6c, 0x69, 0x64, 0x22, 0xc8, 0x01, 0x0a, 0x13, 0x45, 0x6e,
+	0x63, 0x6f, 0x64, 0x65, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x30, 0x0a, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x05, 0x6c, 0x65, 0x76, 0x65, 0x6c, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x73, 0x69, 0x7a, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x63,
+	0x6f, 0x6c, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x6f, 0x6c, 0x6f,
+	0x72, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x6d, 0x61, 0x72, 0x67, 0x69, 0x6e, 0x12, 0x29, 0x0a, 0x06, 0x6f, 0x75, 0x74,
+	0x70, 0x75, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x69, 0x78, 0x67,
+	0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52, 0x06, 0x6f, 0x75,
+	0x74, 0x70, 0x75, 0x74, 0x22, 0x2b, 0x0a, 0x13, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x51, 0x52,
+	0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69,
+	0x6d, 0x61, 0x67, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x22, 0x2a, 0x0a, 0x14, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x51, 0x52, 0x43, 0x6f, 0x64,
+	0x65, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78,
+	0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0xb9, 0x01,
+	0x0a, 0x14, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x42, 0x61, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x52,
+	0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69,
+	0x64, 0x74, 0x68, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68,
+	0x12, 0x16, 0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x63, 0x6f, 0x6c, 0x6f,
+	0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x63, 0x6f, 0x6c, 0x6f, 0x72, 0x12, 0x1e,
+	0x0a, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x0a, 0x62, 0x61, 0x63, 0x6b, 0x67, 0x72, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x29,
+	0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11,
+	0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x22, 0xe2, 0x02, 0x0a, 0x16, 0x52, 0x65,
+	0x6e, 0x64, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x1e, 0x0a, 0x08, 0x74, 0x69, 0x70, 0x5f,
+	0x74, 0x65, 0x78, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x48, 0x00, 0x52, 0x07, 0x74, 0x69,
+	0x70, 0x54, 0x65, 0x78, 0x74, 0x88, 0x01, 0x01, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74,
+	0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16,
+	0x0a, 0x06, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x66, 0x6f, 0x6e, 0x74, 0x18, 0x05,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x66, 0x6f, 0x6e, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6d, 0x61,
+	0x72, 0x6b, 0x75, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x61, 0x72, 0x6b,
+	0x75, 0x70, 0x12, 0x2c, 0x0a, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x18, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x54, 0x65, 0x78,
+	0x74, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65,
+	0x12, 0x14, 0x0a, 0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x67, 0x72, 0x6f, 0x75, 0x70, 0x12, 0x1c, 0x0a, 0x09, 0x68, 0x69, 0x67, 0x68, 0x6c, 0x69,
+	0x67, 0x68, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x68, 0x69, 0x67, 0x68, 0x6c,
+	0x69, 0x67, 0x68, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x66, 0x69, 0x6e, 0x67, 0x65, 0x72, 0x70, 0x72,
+	0x69, 0x6e, 0x74, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b, 0x66, 0x69, 0x6e, 0x67, 0x65,
+	0x72, 0x70, 0x72, 0x69, 0x6e, 0x74, 0x12, 0x29, 0x0a, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75, 0x74,
+	0x18, 0x0b, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x11, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e,
+	0x76, 0x31, 0x2e, 0x4f, 0x75, 0x74, 0x70, 0x75, 0x74, 0x52, 0x06, 0x6f, 0x75, 0x74, 0x70, 0x75,
+	0x74, 0x42, 0x0b, 0x0a, 0x09, 0x5f, 0x74, 0x69, 0x70, 0x5f, 0x74, 0x65, 0x78, 0x74, 0x22, 0xda,
+	0x01, 0x0a, 0x08, 0x42, 0x61, 0x74, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x12, 0x12, 0x0a, 0x04, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12,
+	0x38, 0x0a, 0x06, 0x71, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1e, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x63, 0x6f,
+	0x64, 0x65, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48,
+	0x00, 0x52, 0x06, 0x71, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x3b, 0x0a, 0x07, 0x62, 0x61, 0x72,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1f, 0x2e, 0x70, 0x69, 0x78,
+	0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x42, 0x61, 0x72,
+	0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x07, 0x62,
+	0x61, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x39, 0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x52, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x48, 0x00, 0x52, 0x05, 0x69, 0x6d, 0x61, 0x67,
+	0x65, 0x42, 0x08, 0x0a, 0x06, 0x70, 0x61, 0x72, 0x61, 0x6d, 0x73, 0x22, 0x37, 0x0a, 0x0c, 0x42,
+	0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x27, 0x0a, 0x04, 0x6a,
+	0x6f, 0x62, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x70, 0x69, 0x78, 0x67,
+	0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x4a, 0x6f, 0x62, 0x52, 0x04,
+	0x6a, 0x6f, 0x62, 0x73, 0x22, 0x89, 0x01, 0x0a, 0x0b, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x05, 0x69, 0x6e, 0x64, 0x65, 0x78, 0x12, 0x12, 0x0a, 0x04, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x6e, 0x61, 0x6d, 0x65, 0x12, 0x26,
+	0x0a, 0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x10, 0x2e,
+	0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52,
+	0x05, 0x69, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04,
+	0x63, 0x6f, 0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65,
+	0x2a, 0x9b, 0x01, 0x0a, 0x0b, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x46, 0x6f, 0x72, 0x6d, 0x61, 0x74,
+	0x12, 0x1c, 0x0a, 0x18, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54,
+	0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14,
+	0x0a, 0x10, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x50,
+	0x4e, 0x47, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x5f, 0x46, 0x4f,
+	0x52, 0x4d, 0x41, 0x54, 0x5f, 0x4a, 0x50, 0x45, 0x47, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x49,
+	0x4d, 0x41, 0x47, 0x45, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x47, 0x49, 0x46, 0x10,
+	0x03, 0x12, 0x15, 0x0a, 0x11, 0x49, 0x4d, 0x41, 0x47, 0x45, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41,
+	0x54, 0x5f, 0x57, 0x45, 0x42, 0x50, 0x10, 0x04, 0x12, 0x14, 0x0a, 0x10, 0x49, 0x4d, 0x41, 0x47,
+	0x45, 0x5f, 0x46, 0x4f, 0x52, 0x4d, 0x41, 0x54, 0x5f, 0x42, 0x4d, 0x50, 0x10, 0x05, 0x2a, 0x6d,
+	0x0a, 0x0b, 0x43, 0x6f, 0x6d, 0x70, 0x72, 0x65, 0x73, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a,
+	0x17, 0x43, 0x4f, 0x4d, 0x50, 0x52, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x4f,
+	0x4d, 0x50, 0x52, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x4e, 0x4f, 0x4e, 0x45, 0x10, 0x01,
+	0x12, 0x15, 0x0a, 0x11, 0x43, 0x4f, 0x4d, 0x50, 0x52, 0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f,
+	0x53, 0x50, 0x45, 0x45, 0x44, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x43, 0x4f, 0x4d, 0x50, 0x52,
+	0x45, 0x53, 0x53, 0x49, 0x4f, 0x4e, 0x5f, 0x42, 0x45, 0x53, 0x54, 0x10, 0x03, 0x2a, 0x93, 0x01,
+	0x0a, 0x0f, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x43, 0x6f, 0x72, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x20, 0x0a, 0x1c, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x52, 0x52, 0x45,
+	0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x16, 0x0a, 0x12, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x52,
+	0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x4c, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x45,
+	0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x52, 0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x4d, 0x10, 0x02, 0x12, 0x16, 0x0a, 0x12, 0x45, 0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x52,
+	0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x51, 0x10, 0x03, 0x12, 0x16, 0x0a, 0x12, 0x45,
+	0x52, 0x52, 0x4f, 0x52, 0x5f, 0x43, 0x4f, 0x52, 0x52, 0x45, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f,
+	0x48, 0x10, 0x04, 0x2a, 0x4d, 0x0a, 0x0d, 0x54, 0x65, 0x78, 0x74, 0x49, 0x6d, 0x61, 0x67, 0x65,
+	0x4d, 0x6f, 0x64, 0x65, 0x12, 0x1f, 0x0a, 0x1b, 0x54, 0x45, 0x58, 0x54, 0x5f, 0x49, 0x4d, 0x41,
+	0x47, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x1b, 0x0a, 0x17, 0x54, 0x45, 0x58, 0x54, 0x5f, 0x49, 0x4d,
+	0x41, 0x47, 0x45, 0x5f, 0x4d, 0x4f, 0x44, 0x45, 0x5f, 0x41, 0x44, 0x44, 0x52, 0x45, 0x53, 0x53,
+	0x10, 0x01, 0x32, 0xa7, 0x01, 0x0a, 0x0e, 0x43, 0x61, 0x70, 0x74, 0x63, 0x68, 0x61, 0x53, 0x65,
+	0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x48, 0x0a, 0x05, 0x49, 0x73, 0x73, 0x75, 0x65, 0x12, 0x1e,
+	0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65,
+	0x43, 0x61, 0x70, 0x74, 0x63, 0x68, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f,
+	0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x73, 0x73, 0x75, 0x65,
+	0x43, 0x61, 0x70, 0x74, 0x63, 0x68, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12,
+	0x4b, 0x0a, 0x06, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x12, 0x1f, 0x2e, 0x70, 0x69, 0x78, 0x67,
+	0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x43, 0x61, 0x70, 0x74,
+	0x63, 0x68, 0x61, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x20, 0x2e, 0x70, 0x69, 0x78,
+	0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x56, 0x65, 0x72, 0x69, 0x66, 0x79, 0x43, 0x61, 0x70,
+	0x74, 0x63, 0x68, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x96, 0x01, 0x0a,
+	0x0d, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3a,
+	0x0a, 0x06, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x1e, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e, 0x63, 0x6f, 0x64, 0x65, 0x51, 0x52, 0x43, 0x6f, 0x64,
+	0x65, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65,
+	0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x12, 0x49, 0x0a, 0x06, 0x44, 0x65,
+	0x63, 0x6f, 0x64, 0x65, 0x12, 0x1e, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x1a, 0x1f, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x65, 0x63, 0x6f, 0x64, 0x65, 0x51, 0x52, 0x43, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x73,
+	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x32, 0x4d, 0x0a, 0x0e, 0x42, 0x61, 0x72, 0x63, 0x6f, 0x64, 0x65,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3b, 0x0a, 0x06, 0x45, 0x6e, 0x63, 0x6f, 0x64,
+	0x65, 0x12, 0x1f, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x45, 0x6e,
+	0x63, 0x6f, 0x64, 0x65, 0x42, 0x61, 0x72, 0x63, 0x6f, 0x64, 0x65, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x1a, 0x10, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x49,
+	0x6d, 0x61, 0x67, 0x65, 0x32, 0x51, 0x0a, 0x10, 0x54, 0x65, 0x78, 0x74, 0x49, 0x6d, 0x61, 0x67,
+	0x65, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3d, 0x0a, 0x06, 0x52, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x12, 0x21, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x52,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x54, 0x65, 0x78, 0x74, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x10, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76,
+	0x31, 0x2e, 0x49, 0x6d, 0x61, 0x67, 0x65, 0x32, 0x4b, 0x0a, 0x0c, 0x42, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x3b, 0x0a, 0x06, 0x52, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x12, 0x17, 0x2e, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x16, 0x2e, 0x70, 0x69, 0x78,
+	0x67, 0x65, 0x6e, 0x2e, 0x76, 0x31, 0x2e, 0x42, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x30, 0x01, 0x42, 0x34, 0x5a, 0x32, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x62, 0x69, 0x74, 0x71, 0x69, 0x75, 0x2f, 0x70, 0x69, 0x78, 0x2d, 0x67, 0x65,
+	0x6e, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x2f, 0x76,
+	0x31, 0x3b, 0x70, 0x69, 0x78, 0x67, 0x65, 0x6e, 0x76, 0x31, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
+}
+
+var (
+	file_pixgen_v1_pixgen_proto_rawDescOnce sync.Once
+	file_pixgen_v1_pixgen_proto_rawDescData = file_pixgen_v1_pixgen_proto_rawDesc
+)
+
+func file_pixgen_v1_pixgen_proto_rawDescGZIP() []byte {
+	file_pixgen_v1_pixgen_proto_rawDescOnce.Do(func() {
+		file_pixgen_v1_pixgen_proto_rawDescData = protoimpl.X.CompressGZIP(file_pixgen_v1_pixgen_proto_rawDescData)
+	})
+	return file_pixgen_v1_pixgen_proto_rawDescData
+}
+
+var file_pixgen_v1_pixgen_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
+var file_pixgen_v1_pixgen_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_pixgen_v1_pixgen_proto_goTypes = []interface{}{
+	(ImageFormat)(0),               // 0: pixgen.v1.ImageFormat
+	(Compression)(0),               // 1: pixgen.v1.Compression
+	(ErrorCorrection)(0),           // 2: pixgen.v1.ErrorCorrection
+	(TextImageMode)(0),             // 3: pixgen.v1.TextImageMode
+	(*Output)(nil),                 // 4: pixgen.v1.Output
+	(*Image)(nil),                  // 5: pixgen.v1.Image
+	(*IssueCaptchaRequest)(nil),    // 6: pixgen.v1.IssueCaptchaRequest
+	(*IssueCaptchaResponse)(nil),   // 7: pixgen.v1.IssueCaptchaResponse
+	(*VerifyCaptchaRequest)(nil),   // 8: pixgen.v1.VerifyCaptchaRequest
+	(*VerifyCaptchaResponse)(nil),  // 9: pixgen.v1.VerifyCaptchaResponse
+	(*EncodeQRCodeRequest)(nil),    // 10: pixgen.v1.EncodeQRCodeRequest
+	(*DecodeQRCodeRequest)(nil),    // 11: pixgen.v1.DecodeQRCodeRequest
+	(*DecodeQRCodeResponse)(nil),   // 12: pixgen.v1.DecodeQRCodeResponse
+	(*EncodeBarcodeRequest)(nil),   // 13: pixgen.v1.EncodeBarcodeRequest
+	(*RenderTextImageRequest)(nil), // 14: pixgen.v1.RenderTextImageRequest
+	(*BatchJob)(nil),               // 15: pixgen.v1.BatchJob
+	(*BatchRequest)(nil),           // 16: pixgen.v1.BatchRequest
+	(*BatchResult)(nil),            // 17: pixgen.v1.BatchResult
+	(*timestamppb.Timestamp)(nil),  // 18: google.protobuf.Timestamp
+}
+var file_pixgen_v1_pixgen_proto_depIdxs = []int32{
+	0,  // 0: pixgen.v1.Output.format:type_name -> pixgen.v1.ImageFormat
+	1,  // 1: pixgen.v1.Output.compression:type_name -> pixgen.v1.Compression
+	4,  // 2: pixgen.v1.IssueCaptchaRequest.output:type_name -> pixgen.v1.Output
+	5,  // 3: pixgen.v1.IssueCaptchaResponse.image:type_name -> pixgen.v1.Image
+	18, // 4: pixgen.v1.IssueCaptchaResponse.expire_time:type_name -> google.protobuf.Timestamp
+	2,  // 5: pixgen.v1.EncodeQRCodeRequest.level:type_name -> pixgen.v1.ErrorCorrection
+	4,  // 6: pixgen.v1.EncodeQRCodeRequest.output:type_name -> pixgen.v1.Output
+	4,  // 7: pixgen.v1.EncodeBarcodeRequest.output:type_name -> pixgen.v1.Output
+	3,  // 8: pixgen.v1.RenderTextImageRequest.mode:type_name -> pixgen.v1.TextImageMode
+	4,  // 9: pixgen.v1.RenderTextImageRequest.output:type_name -> pixgen.v1.Output
+	10, // 10: pixgen.v1.BatchJob.qrcode:type_name -> pixgen.v1.EncodeQRCodeRequest
+	13, // 11: pixgen.v1.BatchJob.barcode:type_name -> pixgen.v1.EncodeBarcodeRequest
+	14, // 12: pixgen.v1.BatchJob.image:type_name -> pixgen.v1.RenderTextImageRequest
+	15, // 13: pixgen.v1.BatchRequest.jobs:type_name -> pixgen.v1.BatchJob
+	5,  // 14: pixgen.v1.BatchResult.image:type_name -> pixgen.v1.Image
+	6,  // 15: pixgen.v1.CaptchaService.Issue:input_type -> pixgen.v1.IssueCaptchaRequest
+	8,  // 16: pixgen.v1.CaptchaService.Verify:input_type -> pixgen.v1.VerifyCaptchaRequest
+	10, // 17: pixgen.v1.QRCodeService.Encode:input_type -> pixgen.v1.EncodeQRCodeRequest
+	11, // 18: pixgen.v1.QRCodeService.Decode:input_type -> pixgen.v1.DecodeQRCodeRequest
+	13, // 19: pixgen.v1.BarcodeService.Encode:input_type -> pixgen.v1.EncodeBarcodeRequest
+	14, // 20: pixgen.v1.TextImageService.Render:input_type -> pixgen.v1.RenderTextImageRequest
+	16, // 21: pixgen.v1.BatchService.Render:input_type -> pixgen.v1.BatchRequest
+	7,  // 22: pixgen.v1.CaptchaService.Issue:output_type -> pixgen.v1.IssueCaptchaResponse
+	9,  // 23: pixgen.v1.CaptchaService.Verify:output_type -> pixgen.v1.VerifyCaptchaResponse
+	5,  // 24: pixgen.v1.QRCodeService.Encode:output_type -> pixgen.v1.Image
+	12, // 25: pixgen.v1.QRCodeService.Decode:output_type -> pixgen.v1.DecodeQRCodeResponse
+	5,  // 26: pixgen.v1.BarcodeService.Encode:output_type -> pixgen.v1.Image
+	5,  // 27: pixgen.v1.TextImageService.Render:output_type -> pixgen.v1.Image
+	17, // 28: pixgen.v1.BatchService.Render:output_type -> pixgen.v1.BatchResult
+	22, // [22:29] is the sub-list for method output_type
+	15, // [15:22] is the sub-list for method input_type
+	15, // [15:15] is the sub-list for extension type_name
+	15, // [15:15] is the sub-list for extension extendee
+	0,  // [0:15] is the sub-list for field type_name
+}
+
+func init() { file_pixgen_v1_pixgen_proto_init() }
+func file_pixgen_v1_pixgen_proto_init() {
+	if File_pixgen_v1_pixgen_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_pixgen_v1_pixgen_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Output); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*Image); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IssueCaptchaRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*IssueCaptchaResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyCaptchaRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*VerifyCaptchaResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncodeQRCodeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecodeQRCodeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*DecodeQRCodeResponse); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*EncodeBarcodeRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RenderTextImageRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchJob); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_pixgen_v1_pixgen_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*BatchResult); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	file_pixgen_v1_pixgen_proto_msgTypes[10].OneofWrappers = []interface{}{}
+	file_pixgen_v1_pixgen_proto_msgTypes[11].OneofWrappers = []interface{}{
+		(*BatchJob_Qrcode)(nil),
+		(*BatchJob_Barcode)(nil),
+		(*BatchJob_Image)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_pixgen_v1_pixgen_proto_rawDesc,
+			NumEnums:      4,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   5,
+		},
+		GoTypes:           file_pixgen_v1_pixgen_proto_goTypes,
+		DependencyIndexes: file_pixgen_v1_pixgen_proto_depIdxs,
+		EnumInfos:         file_pixgen_v1_pixgen_proto_enumTypes,
+		MessageInfos:      file_pixgen_v1_pixgen_proto_msgTypes,
+	}.Build()
+	File_pixgen_v1_pixgen_proto = out.File
+	file_pixgen_v1_pixgen_proto_rawDesc = nil
+	file_pixgen_v1_pixgen_proto_goTypes = nil
+	file_pixgen_v1_pixgen_proto_depIdxs = nil
+}
@@ -0,0 +1,580 @@
+// pix-gen 的 gRPC 接口，参数和默认值与对应的 HTTP 接口相同，未设置的字段使用默认值
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: pixgen/v1/pixgen.proto
+
+package pixgenv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	CaptchaService_Issue_FullMethodName  = "/pixgen.v1.CaptchaService/Issue"
+	CaptchaService_Verify_FullMethodName = "/pixgen.v1.CaptchaService/Verify"
+)
+
+// CaptchaServiceClient is the client API for CaptchaService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CaptchaServiceClient interface {
+	// Issue 生成随机验证码，返回验证码 ID 和图片
+	Issue(ctx context.Context, in *IssueCaptchaRequest, opts ...grpc.CallOption) (*IssueCaptchaResponse, error)
+	// Verify 校验验证码，每个验证码只能校验一次
+	Verify(ctx context.Context, in *VerifyCaptchaRequest, opts ...grpc.CallOption) (*VerifyCaptchaResponse, error)
+}
+
+type captchaServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCaptchaServiceClient(cc grpc.ClientConnInterface) CaptchaServiceClient {
+	return &captchaServiceClient{cc}
+}
+
+func (c *captchaServiceClient) Issue(ctx context.Context, in *IssueCaptchaRequest, opts ...grpc.CallOption) (*IssueCaptchaResponse, error) {
+	out := new(IssueCaptchaResponse)
+	err := c.cc.Invoke(ctx, CaptchaService_Issue_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *captchaServiceClient) Verify(ctx context.Context, in *VerifyCaptchaRequest, opts ...grpc.CallOption) (*VerifyCaptchaResponse, error) {
+	out := new(VerifyCaptchaResponse)
+	err := c.cc.Invoke(ctx, CaptchaService_Verify_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CaptchaServiceServer is the server API for CaptchaService service.
+// All implementations must embed UnimplementedCaptchaServiceServer
+// for forward compatibility
+type CaptchaServiceServer interface {
+	// Issue 生成随机验证码，返回验证码 ID 和图片
+	Issue(context.Context, *IssueCaptchaRequest) (*IssueCaptchaResponse, error)
+	// Verify 校验验证码，每个验证码只能校验一次
+	Verify(context.Context, *VerifyCaptchaRequest) (*VerifyCaptchaResponse, error)
+	mustEmbedUnimplementedCaptchaServiceServer()
+}
+
+// UnimplementedCaptchaServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedCaptchaServiceServer struct {
+}
+
+func (UnimplementedCaptchaServiceServer) Issue(context.Context, *IssueCaptchaRequest) (*IssueCaptchaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Issue not implemented")
+}
+func (UnimplementedCaptchaServiceServer) Verify(context.Context, *VerifyCaptchaRequest) (*VerifyCaptchaResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Verify not implemented")
+}
+func (UnimplementedCaptchaServiceServer) mustEmbedUnimplementedCaptchaServiceServer() {}
+
+// UnsafeCaptchaServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CaptchaServiceServer will
+// result in compilation errors.
+type UnsafeCaptchaServiceServer interface {
+	mustEmbedUnimplementedCaptchaServiceServer()
+}
+
+func RegisterCaptchaServiceServer(s grpc.ServiceRegistrar, srv CaptchaServiceServer) {
+	s.RegisterService(&CaptchaService_ServiceDesc, srv)
+}
+
+func _CaptchaService_Issue_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueCaptchaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaptchaServiceServer).Issue(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CaptchaService_Issue_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaptchaServiceServer).Issue(ctx, req.(*IssueCaptchaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _CaptchaService_Verify_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyCaptchaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaptchaServiceServer).Verify(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: CaptchaService_Verify_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaptchaServiceServer).Verify(ctx, req.(*VerifyCaptchaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// CaptchaService_ServiceDesc is the grpc.ServiceDesc for CaptchaService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var CaptchaService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pixgen.v1.CaptchaService",
+	HandlerType: (*CaptchaServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Issue",
+			Handler:    _CaptchaService_Issue_Handler,
+		},
+		{
+			MethodName: "Verify",
+			Handler:    _CaptchaService_Verify_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pixgen/v1/pixgen.proto",
+}
+
+const (
+	QRCodeService_Encode_FullMethodName = "/pixgen.v1.QRCodeService/Encode"
+	QRCodeService_Decode_FullMethodName = "/pixgen.v1.QRCodeService/Decode"
+)
+
+// QRCodeServiceClient is the client API for QRCodeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type QRCodeServiceClient interface {
+	Encode(ctx context.Context, in *EncodeQRCodeRequest, opts ...grpc.CallOption) (*Image, error)
+	// Decode 识别图片中的二维码
+	Decode(ctx context.Context, in *DecodeQRCodeRequest, opts ...grpc.CallOption) (*DecodeQRCodeResponse, error)
+}
+
+type qRCodeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewQRCodeServiceClient(cc grpc.ClientConnInterface) QRCodeServiceClient {
+	return &qRCodeServiceClient{cc}
+}
+
+func (c *qRCodeServiceClient) Encode(ctx context.Context, in *EncodeQRCodeRequest, opts ...grpc.CallOption) (*Image, error) {
+	out := new(Image)
+	err := c.cc.Invoke(ctx, QRCodeService_Encode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *qRCodeServiceClient) Decode(ctx context.Context, in *DecodeQRCodeRequest, opts ...grpc.CallOption) (*DecodeQRCodeResponse, error) {
+	out := new(DecodeQRCodeResponse)
+	err := c.cc.Invoke(ctx, QRCodeService_Decode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// QRCodeServiceServer is the server API for QRCodeService service.
+// All implementations must embed UnimplementedQRCodeServiceServer
+// for forward compatibility
+type QRCodeServiceServer interface {
+	Encode(context.Context, *EncodeQRCodeRequest) (*Image, error)
+	// Decode 识别图片中的二维码
+	Decode(context.Context, *DecodeQRCodeRequest) (*DecodeQRCodeResponse, error)
+	mustEmbedUnimplementedQRCodeServiceServer()
+}
+
+// UnimplementedQRCodeServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedQRCodeServiceServer struct {
+}
+
+func (UnimplementedQRCodeServiceServer) Encode(context.Context, *EncodeQRCodeRequest) (*Image, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Encode not implemented")
+}
+func (UnimplementedQRCodeServiceServer) Decode(context.Context, *DecodeQRCodeRequest) (*DecodeQRCodeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Decode not implemented")
+}
+func (UnimplementedQRCodeServiceServer) mustEmbedUnimplementedQRCodeServiceServer() {}
+
+// UnsafeQRCodeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to QRCodeServiceServer will
+// result in compilation errors.
+type UnsafeQRCodeServiceServer interface {
+	mustEmbedUnimplementedQRCodeServiceServer()
+}
+
+func RegisterQRCodeServiceServer(s grpc.ServiceRegistrar, srv QRCodeServiceServer) {
+	s.RegisterService(&QRCodeService_ServiceDesc, srv)
+}
+
+func _QRCodeService_Encode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EncodeQRCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QRCodeServiceServer).Encode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QRCodeService_Encode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QRCodeServiceServer).Encode(ctx, req.(*EncodeQRCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _QRCodeService_Decode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DecodeQRCodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(QRCodeServiceServer).Decode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: QRCodeService_Decode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(QRCodeServiceServer).Decode(ctx, req.(*DecodeQRCodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// QRCodeService_ServiceDesc is the grpc.ServiceDesc for QRCodeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var QRCodeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pixgen.v1.QRCodeService",
+	HandlerType: (*QRCodeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Encode",
+			Handler:    _QRCodeService_Encode_Handler,
+		},
+		{
+			MethodName: "Decode",
+			Handler:    _QRCodeService_Decode_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pixgen/v1/pixgen.proto",
+}
+
+const (
+	BarcodeService_Encode_FullMethodName = "/pixgen.v1.BarcodeService/Encode"
+)
+
+// BarcodeServiceClient is the client API for BarcodeService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BarcodeServiceClient interface {
+	Encode(ctx context.Context, in *EncodeBarcodeRequest, opts ...grpc.CallOption) (*Image, error)
+}
+
+type barcodeServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBarcodeServiceClient(cc grpc.ClientConnInterface) BarcodeServiceClient {
+	return &barcodeServiceClient{cc}
+}
+
+func (c *barcodeServiceClient) Encode(ctx context.Context, in *EncodeBarcodeRequest, opts ...grpc.CallOption) (*Image, error) {
+	out := new(Image)
+	err := c.cc.Invoke(ctx, BarcodeService_Encode_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// BarcodeServiceServer is the server API for BarcodeService service.
+// All implementations must embed UnimplementedBarcodeServiceServer
+// for forward compatibility
+type BarcodeServiceServer interface {
+	Encode(context.Context, *EncodeBarcodeRequest) (*Image, error)
+	mustEmbedUnimplementedBarcodeServiceServer()
+}
+
+// UnimplementedBarcodeServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBarcodeServiceServer struct {
+}
+
+func (UnimplementedBarcodeServiceServer) Encode(context.Context, *EncodeBarcodeRequest) (*Image, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Encode not implemented")
+}
+func (UnimplementedBarcodeServiceServer) mustEmbedUnimplementedBarcodeServiceServer() {}
+
+// UnsafeBarcodeServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BarcodeServiceServer will
+// result in compilation errors.
+type UnsafeBarcodeServiceServer interface {
+	mustEmbedUnimplementedBarcodeServiceServer()
+}
+
+func RegisterBarcodeServiceServer(s grpc.ServiceRegistrar, srv BarcodeServiceServer) {
+	s.RegisterService(&BarcodeService_ServiceDesc, srv)
+}
+
+func _BarcodeService_Encode_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EncodeBarcodeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BarcodeServiceServer).Encode(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BarcodeService_Encode_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BarcodeServiceServer).Encode(ctx, req.(*EncodeBarcodeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// BarcodeService_ServiceDesc is the grpc.ServiceDesc for BarcodeService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BarcodeService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pixgen.v1.BarcodeService",
+	HandlerType: (*BarcodeServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Encode",
+			Handler:    _BarcodeService_Encode_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pixgen/v1/pixgen.proto",
+}
+
+const (
+	TextImageService_Render_FullMethodName = "/pixgen.v1.TextImageService/Render"
+)
+
+// TextImageServiceClient is the client API for TextImageService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TextImageServiceClient interface {
+	Render(ctx context.Context, in *RenderTextImageRequest, opts ...grpc.CallOption) (*Image, error)
+}
+
+type textImageServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTextImageServiceClient(cc grpc.ClientConnInterface) TextImageServiceClient {
+	return &textImageServiceClient{cc}
+}
+
+func (c *textImageServiceClient) Render(ctx context.Context, in *RenderTextImageRequest, opts ...grpc.CallOption) (*Image, error) {
+	out := new(Image)
+	err := c.cc.Invoke(ctx, TextImageService_Render_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// TextImageServiceServer is the server API for TextImageService service.
+// All implementations must embed UnimplementedTextImageServiceServer
+// for forward compatibility
+type TextImageServiceServer interface {
+	Render(context.Context, *RenderTextImageRequest) (*Image, error)
+	mustEmbedUnimplementedTextImageServiceServer()
+}
+
+// UnimplementedTextImageServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTextImageServiceServer struct {
+}
+
+func (UnimplementedTextImageServiceServer) Render(context.Context, *RenderTextImageRequest) (*Image, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Render not implemented")
+}
+func (UnimplementedTextImageServiceServer) mustEmbedUnimplementedTextImageServiceServer() {}
+
+// UnsafeTextImageServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TextImageServiceServer will
+// result in compilation errors.
+type UnsafeTextImageServiceServer interface {
+	mustEmbedUnimplementedTextImageServiceServer()
+}
+
+func RegisterTextImageServiceServer(s grpc.ServiceRegistrar, srv TextImageServiceServer) {
+	s.RegisterService(&TextImageService_ServiceDesc, srv)
+}
+
+func _TextImageService_Render_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenderTextImageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TextImageServiceServer).Render(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TextImageService_Render_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TextImageServiceServer).Render(ctx, req.(*RenderTextImageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// TextImageService_ServiceDesc is the grpc.ServiceDesc for TextImageService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TextImageService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pixgen.v1.TextImageService",
+	HandlerType: (*TextImageServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Render",
+			Handler:    _TextImageService_Render_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "pixgen/v1/pixgen.proto",
+}
+
+const (
+	BatchService_Render_FullMethodName = "/pixgen.v1.BatchService/Render"
+)
+
+// BatchServiceClient is the client API for BatchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BatchServiceClient interface {
+	// Render 并发渲染任务，按任务顺序流式返回每个任务的结果
+	Render(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (BatchService_RenderClient, error)
+}
+
+type batchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBatchServiceClient(cc grpc.ClientConnInterface) BatchServiceClient {
+	return &batchServiceClient{cc}
+}
+
+func (c *batchServiceClient) Render(ctx context.Context, in *BatchRequest, opts ...grpc.CallOption) (BatchService_RenderClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BatchService_ServiceDesc.Streams[0], BatchService_Render_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &batchServiceRenderClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BatchService_RenderClient interface {
+	Recv() (*BatchResult, error)
+	grpc.ClientStream
+}
+
+type batchServiceRenderClient struct {
+	grpc.ClientStream
+}
+
+func (x *batchServiceRenderClient) Recv() (*BatchResult, error) {
+	m := new(BatchResult)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BatchServiceServer is the server API for BatchService service.
+// All implementations must embed UnimplementedBatchServiceServer
+// for forward compatibility
+type BatchServiceServer interface {
+	// Render 并发渲染任务，按任务顺序流式返回每个任务的结果
+	Render(*BatchRequest, BatchService_RenderServer) error
+	mustEmbedUnimplementedBatchServiceServer()
+}
+
+// UnimplementedBatchServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedBatchServiceServer struct {
+}
+
+func (UnimplementedBatchServiceServer) Render(*BatchRequest, BatchService_RenderServer) error {
+	return status.Errorf(codes.Unimplemented, "method Render not implemented")
+}
+func (UnimplementedBatchServiceServer) mustEmbedUnimplementedBatchServiceServer() {}
+
+// UnsafeBatchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BatchServiceServer will
+// result in compilation errors.
+type UnsafeBatchServiceServer interface {
+	mustEmbedUnimplementedBatchServiceServer()
+}
+
+func RegisterBatchServiceServer(s grpc.ServiceRegistrar, srv BatchServiceServer) {
+	s.RegisterService(&BatchService_ServiceDesc, srv)
+}
+
+func _BatchService_Render_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(BatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BatchServiceServer).Render(m, &batchServiceRenderServer{stream})
+}
+
+type BatchService_RenderServer interface {
+	Send(*BatchResult) error
+	grpc.ServerStream
+}
+
+type batchServiceRenderServer struct {
+	grpc.ServerStream
+}
+
+func (x *batchServiceRenderServer) Send(m *BatchResult) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// BatchService_ServiceDesc is the grpc.ServiceDesc for BatchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BatchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "pixgen.v1.BatchService",
+	HandlerType: (*BatchServiceServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Render",
+			Handler:       _BatchService_Render_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "pixgen/v1/pixgen.proto",
+}
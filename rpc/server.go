@@ -0,0 +1,248 @@
+// Package rpc 提供 gRPC 服务，与 HTTP 接口共用请求参数、默认值、资源限制、租户和指标
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bitqiu/pix-gen/handler"
+	"github.com/bitqiu/pix-gen/pkg/captcha"
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	pb "github.com/bitqiu/pix-gen/proto/pixgen/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// endpoints 是 gRPC 服务到接口名称的映射，租户的接口权限和用量按接口名称计算
+var endpoints = map[string]string{
+	pb.CaptchaService_ServiceDesc.ServiceName:   "captcha",
+	pb.QRCodeService_ServiceDesc.ServiceName:    "qrcode",
+	pb.BarcodeService_ServiceDesc.ServiceName:   "barcode",
+	pb.TextImageService_ServiceDesc.ServiceName: "image",
+	pb.BatchService_ServiceDesc.ServiceName:     "batch",
+}
+
+// Server 是 gRPC 服务
+type Server struct {
+	*grpc.Server
+	health *health.Server
+}
+
+// NewServer 创建 gRPC 服务并注册配置中启用的接口
+// tenants 不为 nil 时要求 authorization 元数据携带租户的 API key；limiter 不为 nil 时与 HTTP 接口共用限流；store 保存签发的验证码
+func NewServer(cfg *config.Config, tenants *handler.Tenants, limiter *handler.Limiter, store captcha.Store, logger *slog.Logger) (*Server, error) {
+	var opts []grpc.ServerOption
+	if cfg.Server.TLSCert != "" {
+		creds, err := credentials.NewServerTLSFromFile(cfg.Server.TLSCert, cfg.Server.TLSKey)
+		if err != nil {
+			return nil, fmt.Errorf("grpc: %w", err)
+		}
+		opts = append(opts, grpc.Creds(creds))
+	}
+	i := &interceptor{tenants: tenants, limiter: limiter, logger: logger}
+	opts = append(opts, grpc.ChainUnaryInterceptor(i.unary), grpc.ChainStreamInterceptor(i.stream))
+
+	s := &Server{Server: grpc.NewServer(opts...), health: health.NewServer()}
+	f := cfg.Features
+	if f.Captcha {
+		pb.RegisterCaptchaServiceServer(s, &captchaService{store: store, length: cfg.GRPC.CaptchaLength, ttl: time.Duration(cfg.GRPC.CaptchaTTL)})
+	}
+	if f.QRCode {
+		pb.RegisterQRCodeServiceServer(s, &qrcodeService{limits: cfg.Limits})
+	}
+	if f.Barcode {
+		pb.RegisterBarcodeServiceServer(s, &barcodeService{})
+	}
+	if f.Image {
+		pb.RegisterTextImageServiceServer(s, &textImageService{})
+	}
+	if f.Batch {
+		pb.RegisterBatchServiceServer(s, &batchService{})
+	}
+	healthpb.RegisterHealthServer(s, s.health)
+	return s, nil
+}
+
+// Drain 将健康检查设置为 NOT_SERVING，停止前调用
+func (s *Server) Drain() {
+	s.health.Shutdown()
+}
+
+// interceptor 为每个调用记录日志、恢复 panic、限流并校验租户
+type interceptor struct {
+	tenants *handler.Tenants
+	limiter *handler.Limiter
+	logger  *slog.Logger
+}
+
+// unary 是一元调用的拦截器
+func (i *interceptor) unary(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (resp interface{}, err error) {
+	err = i.intercept(ctx, info.FullMethod, func(ctx context.Context) error {
+		resp, err = next(ctx, req)
+		return err
+	})
+	return resp, err
+}
+
+// stream 是流式调用的拦截器
+func (i *interceptor) stream(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, next grpc.StreamHandler) error {
+	return i.intercept(ss.Context(), info.FullMethod, func(ctx context.Context) error {
+		return next(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	})
+}
+
+// intercept 沿用或生成请求 ID 并通过 x-request-id 响应头返回，按 lang 或 accept-language 元数据选择语言
+// 限流和校验租户后执行调用并记录日志
+func (i *interceptor) intercept(ctx context.Context, method string, call func(ctx context.Context) error) (err error) {
+	start := time.Now()
+	md, _ := metadata.FromIncomingContext(ctx)
+	id := handler.NewRequestID(first(md.Get("x-request-id")))
+	ctx = handler.WithRequestID(ctx, id)
+	ctx = handler.WithLang(ctx, handler.NegotiateLang(first(md.Get("lang")), first(md.Get("accept-language"))))
+	grpc.SetHeader(ctx, metadata.Pairs("x-request-id", id))
+
+	defer func() {
+		if r := recover(); r != nil {
+			i.logger.ErrorContext(ctx, "panic", slog.String("request_id", id), slog.Any("error", r))
+			err = status.Error(codes.Internal, "internal error")
+		}
+		code := status.Code(err)
+		level := slog.LevelInfo
+		switch code {
+		case codes.OK:
+		case codes.Internal, codes.Unknown, codes.DataLoss:
+			level = slog.LevelError
+		default:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("request_id", id),
+			slog.String("method", method),
+			slog.String("code", code.String()),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+		}
+		if name := handler.TenantName(ctx); name != "" {
+			attrs = append(attrs, slog.String("tenant", name))
+		}
+		if err != nil {
+			attrs = append(attrs, slog.String("error", status.Convert(err).Message()))
+		}
+		i.logger.LogAttrs(ctx, level, "rpc", attrs...)
+	}()
+
+	endpoint, ok := endpoints[serviceName(method)]
+	if !ok {
+		return call(ctx)
+	}
+	key, _ := strings.CutPrefix(first(md.Get("authorization")), "Bearer ")
+	// 与 HTTP 接口按 /captcha 等路由共用令牌桶，客户端 IP 为连接的对端地址
+	if i.limiter != nil {
+		res, lerr := i.limiter.Take(ctx, "/"+endpoint, key, peerIP(ctx))
+		if lerr == nil && !res.Allowed {
+			grpc.SetHeader(ctx, metadata.Pairs("retry-after", strconv.Itoa(int(math.Ceil(res.RetryAfter.Seconds())))))
+			return toStatus(ctx, handler.ErrRateLimited)
+		}
+	}
+	if i.tenants != nil {
+		if ctx, err = i.tenants.Authorize(ctx, key, endpoint); err != nil {
+			return toStatus(ctx, err)
+		}
+	}
+	return call(ctx)
+}
+
+// serverStream 替换流的上下文，使服务能取得请求 ID 和租户
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context { return s.ctx }
+
+// peerIP 返回调用方连接的 IP 地址
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// serviceName 返回 /pixgen.v1.QRCodeService/Encode 形式的方法名中的服务名
+func serviceName(method string) string {
+	name, _, _ := strings.Cut(strings.TrimPrefix(method, "/"), "/")
+	return name
+}
+
+// first 返回第一个值，没有时返回空字符串
+func first(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// statusCodes 是错误码对应的 gRPC 状态码，未列出的错误码返回 InvalidArgument
+var statusCodes = map[errcode.Code]codes.Code{
+	errcode.TemplateNotFound: codes.NotFound,
+	errcode.NotFound:         codes.NotFound,
+	errcode.RenderTimeout:    codes.ResourceExhausted,
+	errcode.RateLimited:      codes.ResourceExhausted,
+	errcode.QuotaExceeded:    codes.ResourceExhausted,
+	errcode.Busy:             codes.Unavailable,
+	errcode.MissingKey:       codes.Unauthenticated,
+	errcode.InvalidKey:       codes.Unauthenticated,
+	errcode.EndpointDenied:   codes.PermissionDenied,
+	errcode.InvalidSignature: codes.PermissionDenied,
+	errcode.Internal:         codes.Internal,
+}
+
+// toStatus 将错误转换为 gRPC 状态，与 HTTP 接口的状态码对应
+// 错误码放在 ErrorInfo 详情的 Reason 中，按请求的语言翻译的错误信息放在 LocalizedMessage 详情中
+func toStatus(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return status.Error(codes.Canceled, err.Error())
+	case errors.Is(err, context.DeadlineExceeded):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	code := errcode.Of(err)
+	c, ok := statusCodes[code]
+	if !ok {
+		c = codes.InvalidArgument
+	}
+	lang := handler.Lang(ctx)
+	st, derr := status.New(c, err.Error()).WithDetails(
+		&errdetails.ErrorInfo{Reason: string(code), Domain: "pix-gen"},
+		&errdetails.LocalizedMessage{Locale: string(lang), Message: lang.Text(string(code))},
+	)
+	if derr != nil {
+		return status.Error(c, err.Error())
+	}
+	return st.Err()
+}
@@ -0,0 +1,207 @@
+package rpc
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/bitqiu/pix-gen/handler"
+	"github.com/bitqiu/pix-gen/pkg/captcha"
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	"github.com/bitqiu/pix-gen/pkg/ratelimit"
+	pb "github.com/bitqiu/pix-gen/proto/pixgen/v1"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// dial 启动内存中的 gRPC 服务并返回连接
+func dial(t *testing.T, tenants *handler.Tenants, limiter *handler.Limiter) *grpc.ClientConn {
+	t.Helper()
+	s, err := NewServer(config.Default(), tenants, limiter, captcha.NewMemoryStore(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lis := bufconn.Listen(1 << 20)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// reason 返回 gRPC 错误详情中的错误码
+func reason(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if info, ok := d.(*errdetails.ErrorInfo); ok {
+			return info.Reason
+		}
+	}
+	return ""
+}
+
+// localizedMessage 返回 gRPC 错误详情中翻译后的错误信息
+func localizedMessage(err error) string {
+	for _, d := range status.Convert(err).Details() {
+		if m, ok := d.(*errdetails.LocalizedMessage); ok {
+			return m.Message
+		}
+	}
+	return ""
+}
+
+// TestQRCodeService 测试生成二维码后识别出相同的内容，以及参数错误的状态码
+func TestQRCodeService(t *testing.T) {
+	client := pb.NewQRCodeServiceClient(dial(t, nil, nil))
+	ctx := context.Background()
+
+	img, err := client.Encode(ctx, &pb.EncodeQRCodeRequest{Text: "https://example.com/?a=1", Size: 256})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.ContentType != "image/png" || len(img.Data) == 0 {
+		t.Fatalf("image: %q %d bytes", img.ContentType, len(img.Data))
+	}
+	res, err := client.Decode(ctx, &pb.DecodeQRCodeRequest{Image: img.Data})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Text != "https://example.com/?a=1" {
+		t.Errorf("decoded %q", res.Text)
+	}
+
+	enCtx := metadata.AppendToOutgoingContext(ctx, "accept-language", "en-US")
+	_, err = client.Encode(enCtx, &pb.EncodeQRCodeRequest{Text: "x", Color: "zz"})
+	if status.Code(err) != codes.InvalidArgument || reason(err) != string(errcode.InvalidColor) {
+		t.Errorf("invalid color: %v (%s)", err, reason(err))
+	}
+	if msg := localizedMessage(err); msg != "invalid color" {
+		t.Errorf("localized message = %q", msg)
+	}
+	if _, err := client.Decode(ctx, &pb.DecodeQRCodeRequest{Image: []byte("not an image")}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("invalid image: %v", err)
+	}
+}
+
+// TestBatchService 测试批量生成按顺序返回结果，失败的任务不影响其他任务
+func TestBatchService(t *testing.T) {
+	client := pb.NewBatchServiceClient(dial(t, nil, nil))
+	stream, err := client.Render(context.Background(), &pb.BatchRequest{Jobs: []*pb.BatchJob{
+		{Name: "a", Params: &pb.BatchJob_Qrcode{Qrcode: &pb.EncodeQRCodeRequest{Text: "a"}}},
+		{Name: "b", Params: &pb.BatchJob_Barcode{Barcode: &pb.EncodeBarcodeRequest{Text: "b", Color: "zz"}}},
+		{Name: "c", Params: &pb.BatchJob_Barcode{Barcode: &pb.EncodeBarcodeRequest{Text: "c"}}},
+		{Name: "d", Params: &pb.BatchJob_Image{Image: &pb.RenderTextImageRequest{Width: 4096, Height: 4096, Markup: "<size=2>d</size>"}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var results []*pb.BatchResult
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		results = append(results, res)
+	}
+	if len(results) != 4 {
+		t.Fatalf("got %d results", len(results))
+	}
+	for i, res := range results {
+		if res.Index != int32(i+1) {
+			t.Errorf("result %d has index %d", i, res.Index)
+		}
+		if failed := res.Error != ""; failed != (res.Name == "b" || res.Name == "d") || failed == (res.Image != nil) || failed != (res.Code != "") {
+			t.Errorf("result %s: error %q code %q image %v", res.Name, res.Error, res.Code, res.Image != nil)
+		}
+	}
+	// 字号超限的任务在渲染前失败，不需要字体
+	if res := results[3]; res.Code != string(errcode.SizeTooLarge) {
+		t.Errorf("font size job: code %q error %q", res.Code, res.Error)
+	}
+}
+
+// TestTextImageFontSize 测试字号超过 limits.maxFontSize 时返回 SIZE_TOO_LARGE
+func TestTextImageFontSize(t *testing.T) {
+	client := pb.NewTextImageServiceClient(dial(t, nil, nil))
+	_, err := client.Render(context.Background(), &pb.RenderTextImageRequest{Width: 4096, Height: 4096, Markup: "<size=2>a</size>"})
+	if status.Code(err) == codes.OK || reason(err) != string(errcode.SizeTooLarge) {
+		t.Errorf("Render() = %v, want %s", err, errcode.SizeTooLarge)
+	}
+	// 字号倍数超出范围时返回 INVALID_MARKUP
+	_, err = client.Render(context.Background(), &pb.RenderTextImageRequest{Markup: "<size=3000>a</size>"})
+	if reason(err) != string(errcode.InvalidMarkup) {
+		t.Errorf("Render() = %v, want %s", err, errcode.InvalidMarkup)
+	}
+}
+
+// TestAuthInterceptor 测试 API key 和接口权限对应的状态码
+func TestAuthInterceptor(t *testing.T) {
+	tenants, err := handler.NewTenants([]config.Tenant{{
+		Name:      "labels",
+		Keys:      []string{"lbl-0123456789abcdef"},
+		Endpoints: []string{"barcode"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := dial(t, tenants, nil)
+	barcode := pb.NewBarcodeServiceClient(conn)
+	withKey := func(key string) context.Context {
+		return metadata.AppendToOutgoingContext(context.Background(), "authorization", "Bearer "+key)
+	}
+
+	if _, err := barcode.Encode(context.Background(), &pb.EncodeBarcodeRequest{Text: "1"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("missing key: %v", err)
+	}
+	if _, err := barcode.Encode(withKey("wrong"), &pb.EncodeBarcodeRequest{Text: "1"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("invalid key: %v", err)
+	}
+	var header metadata.MD
+	if _, err := barcode.Encode(withKey("lbl-0123456789abcdef"), &pb.EncodeBarcodeRequest{Text: "1"}, grpc.Header(&header)); err != nil {
+		t.Errorf("valid key: %v", err)
+	}
+	if len(header.Get("x-request-id")) != 1 {
+		t.Errorf("x-request-id header: %v", header)
+	}
+	qrcode := pb.NewQRCodeServiceClient(conn)
+	if _, err := qrcode.Encode(withKey("lbl-0123456789abcdef"), &pb.EncodeQRCodeRequest{Text: "1"}); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("endpoint not allowed: %v", err)
+	}
+}
+
+// TestRateLimitInterceptor 测试 gRPC 调用与 HTTP 接口使用相同的限流
+func TestRateLimitInterceptor(t *testing.T) {
+	cfg := config.Default().RateLimit
+	cfg.Routes = map[string]config.RouteLimit{"/barcode": {Requests: 1, Per: config.Duration(time.Minute), Burst: 1}}
+	barcode := pb.NewBarcodeServiceClient(dial(t, nil, handler.NewLimiter(ratelimit.NewMemoryStore(), cfg, nil)))
+	ctx := context.Background()
+
+	if _, err := barcode.Encode(ctx, &pb.EncodeBarcodeRequest{Text: "1"}); err != nil {
+		t.Fatal(err)
+	}
+	var header metadata.MD
+	_, err := barcode.Encode(ctx, &pb.EncodeBarcodeRequest{Text: "1"}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted || reason(err) != string(errcode.RateLimited) {
+		t.Errorf("rate limited: %v", err)
+	}
+	if got := header.Get("retry-after"); len(got) != 1 || got[0] != "60" {
+		t.Errorf("retry-after header: %v", header)
+	}
+}
@@ -0,0 +1,278 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"image"
+	_ "image/gif"  // 识别 GIF 中的二维码
+	_ "image/jpeg" // 识别 JPEG 中的二维码
+	_ "image/png"  // 识别 PNG 中的二维码
+	"strings"
+	"time"
+
+	"github.com/bitqiu/pix-gen/handler"
+	"github.com/bitqiu/pix-gen/pkg/captcha"
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/errcode"
+	pb "github.com/bitqiu/pix-gen/proto/pixgen/v1"
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	_ "golang.org/x/image/bmp"  // 识别 BMP 中的二维码
+	_ "golang.org/x/image/webp" // 识别 WebP 中的二维码
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// maxBatchJobs 是一次批量生成的最大任务数，与 HTTP 接口相同
+const maxBatchJobs = 10000
+
+// setString 在 v 不为空时覆盖默认值
+func setString(dst *string, v string) {
+	if v != "" {
+		*dst = v
+	}
+}
+
+// setInt 在 v 不为 0 时覆盖默认值
+func setInt(dst *int, v int32) {
+	if v != 0 {
+		*dst = int(v)
+	}
+}
+
+// outputRequest 转换输出参数，未设置的枚举值使用默认值
+func outputRequest(o *pb.Output) handler.OutputRequest {
+	var out handler.OutputRequest
+	if f := o.GetFormat(); f != pb.ImageFormat_IMAGE_FORMAT_UNSPECIFIED {
+		out.Format = strings.ToLower(strings.TrimPrefix(f.String(), "IMAGE_FORMAT_"))
+	}
+	if c := o.GetCompression(); c != pb.Compression_COMPRESSION_UNSPECIFIED {
+		out.Compression = strings.ToLower(strings.TrimPrefix(c.String(), "COMPRESSION_"))
+	}
+	out.Quality = int(o.GetQuality())
+	return out
+}
+
+// encode 渲染请求并返回图片消息
+func encode(ctx context.Context, generator string, req handler.Renderer) (*pb.Image, error) {
+	data, opts, err := handler.Encode(ctx, generator, req)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.Image{ContentType: opts.Format.ContentType(), Data: data}, nil
+}
+
+// captchaService 实现 CaptchaService
+type captchaService struct {
+	pb.UnimplementedCaptchaServiceServer
+	store  captcha.Store
+	length int
+	ttl    time.Duration
+}
+
+// Issue 生成随机验证码，答案保存在验证码存储中
+func (s *captchaService) Issue(ctx context.Context, m *pb.IssueCaptchaRequest) (*pb.IssueCaptchaResponse, error) {
+	req := handler.NewCaptchaRequest()
+	setInt(&req.Width, m.GetWidth())
+	setInt(&req.Height, m.GetHeight())
+	setString(&req.Font, m.GetFont())
+	req.OutputRequest = outputRequest(m.GetOutput())
+	length := s.length
+	setInt(&length, m.GetLength())
+	if length < 1 || length > 16 {
+		return nil, status.Errorf(codes.InvalidArgument, "length must be between 1 and 16, got %d", length)
+	}
+
+	id, data, opts, err := handler.IssueCaptcha(ctx, s.store, req, length, s.ttl)
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.IssueCaptchaResponse{
+		Id:         id,
+		Image:      &pb.Image{ContentType: opts.Format.ContentType(), Data: data},
+		ExpireTime: timestamppb.New(time.Now().Add(s.ttl)),
+	}, nil
+}
+
+// Verify 校验验证码，每个验证码只能校验一次
+func (s *captchaService) Verify(ctx context.Context, m *pb.VerifyCaptchaRequest) (*pb.VerifyCaptchaResponse, error) {
+	if m.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "id is required")
+	}
+	ok, err := handler.VerifyCaptcha(ctx, s.store, m.GetId(), m.GetCode())
+	if err != nil {
+		return nil, status.Errorf(codes.Unavailable, "captcha store: %v", err)
+	}
+	return &pb.VerifyCaptchaResponse{Valid: ok}, nil
+}
+
+// qrcodeService 实现 QRCodeService
+type qrcodeService struct {
+	pb.UnimplementedQRCodeServiceServer
+	limits config.Limits
+}
+
+// qrcodeRequest 转换二维码参数
+func qrcodeRequest(ctx context.Context, m *pb.EncodeQRCodeRequest) *handler.QRCodeRequest {
+	req := handler.NewQRCodeRequest()
+	handler.ApplyDefaults(ctx, req)
+	req.Text = m.GetText()
+	if l := m.GetLevel(); l != pb.ErrorCorrection_ERROR_CORRECTION_UNSPECIFIED {
+		req.Level = strings.TrimPrefix(l.String(), "ERROR_CORRECTION_")
+	}
+	setInt(&req.Size, m.GetSize())
+	setString(&req.Color, m.GetColor())
+	setInt(&req.Margin, m.GetMargin())
+	req.OutputRequest = outputRequest(m.GetOutput())
+	return req
+}
+
+// Encode 生成二维码
+func (s *qrcodeService) Encode(ctx context.Context, m *pb.EncodeQRCodeRequest) (*pb.Image, error) {
+	return encode(ctx, "qrcode", qrcodeRequest(ctx, m))
+}
+
+// Decode 识别图片中的二维码，图片尺寸受与生成相同的宽高和像素数限制
+func (s *qrcodeService) Decode(ctx context.Context, m *pb.DecodeQRCodeRequest) (*pb.DecodeQRCodeResponse, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(m.GetImage()))
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "decode image: %v", err)
+	}
+	if cfg.Width > s.limits.MaxWidth || cfg.Height > s.limits.MaxHeight || cfg.Width*cfg.Height > s.limits.MaxArea {
+		return nil, status.Errorf(codes.InvalidArgument, "image %dx%d exceeds limits", cfg.Width, cfg.Height)
+	}
+
+	var text string
+	err = handler.LimitRender(ctx, func() error {
+		img, _, err := image.Decode(bytes.NewReader(m.GetImage()))
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "decode image: %v", err)
+		}
+		bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "decode image: %v", err)
+		}
+		hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_TRY_HARDER: true}
+		res, err := qrcode.NewQRCodeReader().Decode(bmp, hints)
+		var notFound gozxing.NotFoundException
+		if errors.As(err, &notFound) {
+			return status.Error(codes.NotFound, "no QR code found")
+		}
+		if err != nil {
+			return status.Errorf(codes.InvalidArgument, "decode QR code: %v", err)
+		}
+		text = res.GetText()
+		return nil
+	})
+	if err != nil {
+		return nil, toStatus(ctx, err)
+	}
+	return &pb.DecodeQRCodeResponse{Text: text}, nil
+}
+
+// barcodeService 实现 BarcodeService
+type barcodeService struct {
+	pb.UnimplementedBarcodeServiceServer
+}
+
+// barcodeRequest 转换条码参数
+func barcodeRequest(ctx context.Context, m *pb.EncodeBarcodeRequest) *handler.BarcodeRequest {
+	req := handler.NewBarcodeRequest()
+	handler.ApplyDefaults(ctx, req)
+	req.Text = m.GetText()
+	setInt(&req.Width, m.GetWidth())
+	setInt(&req.Height, m.GetHeight())
+	setString(&req.Color, m.GetColor())
+	setString(&req.Background, m.GetBackground())
+	req.OutputRequest = outputRequest(m.GetOutput())
+	return req
+}
+
+// Encode 生成条码
+func (s *barcodeService) Encode(ctx context.Context, m *pb.EncodeBarcodeRequest) (*pb.Image, error) {
+	return encode(ctx, "barcode", barcodeRequest(ctx, m))
+}
+
+// textImageService 实现 TextImageService
+type textImageService struct {
+	pb.UnimplementedTextImageServiceServer
+}
+
+// textImageRequest 转换文字图片参数
+func textImageRequest(ctx context.Context, m *pb.RenderTextImageRequest) *handler.ImageRequest {
+	req := handler.NewImageRequest()
+	handler.ApplyDefaults(ctx, req)
+	req.Text = m.GetText()
+	if m.TipText != nil {
+		req.TipText = m.GetTipText()
+	}
+	setInt(&req.Width, m.GetWidth())
+	setInt(&req.Height, m.GetHeight())
+	setString(&req.Font, m.GetFont())
+	req.Markup = m.GetMarkup()
+	if m.GetMode() == pb.TextImageMode_TEXT_IMAGE_MODE_ADDRESS {
+		req.Mode = "address"
+	}
+	setInt(&req.Group, m.GetGroup())
+	setInt(&req.Highlight, m.GetHighlight())
+	req.Fingerprint = m.GetFingerprint()
+	req.OutputRequest = outputRequest(m.GetOutput())
+	return req
+}
+
+// Render 生成文字图片
+func (s *textImageService) Render(ctx context.Context, m *pb.RenderTextImageRequest) (*pb.Image, error) {
+	return encode(ctx, "image", textImageRequest(ctx, m))
+}
+
+// batchService 实现 BatchService
+type batchService struct {
+	pb.UnimplementedBatchServiceServer
+}
+
+// Render 并发渲染任务，按任务顺序流式返回结果，单个任务失败不影响其他任务
+// 剩余的配额需要够所有任务使用，成功生成的任务各计一次
+func (s *batchService) Render(m *pb.BatchRequest, stream pb.BatchService_RenderServer) error {
+	jobs := m.GetJobs()
+	if len(jobs) == 0 || len(jobs) > maxBatchJobs {
+		return status.Errorf(codes.InvalidArgument, "jobs must contain 1 to %d jobs, got %d", maxBatchJobs, len(jobs))
+	}
+	ctx := stream.Context()
+	if err := handler.CheckQuota(ctx, len(jobs)); err != nil {
+		return toStatus(ctx, err)
+	}
+
+	render := func(ctx context.Context, i int) handler.BatchResult {
+		generator, req := batchRequest(ctx, jobs[i])
+		if req == nil {
+			return handler.BatchResult{Err: errcode.New(errcode.InvalidRequest, "job has no params")}
+		}
+		data, opts, err := handler.Encode(ctx, generator, req)
+		return handler.BatchResult{Data: data, Format: opts.Format, Err: err}
+	}
+	return handler.RenderBatch(ctx, len(jobs), render, func(i int, res handler.BatchResult) error {
+		out := &pb.BatchResult{Index: int32(i + 1), Name: jobs[i].GetName()}
+		if res.Err != nil {
+			out.Code = string(errcode.Of(res.Err))
+			out.Error = res.Err.Error()
+		} else {
+			out.Image = &pb.Image{ContentType: res.Format.ContentType(), Data: res.Data}
+		}
+		return stream.Send(out)
+	})
+}
+
+// batchRequest 返回任务的生成器名称和请求参数，任务没有参数时返回 nil
+func batchRequest(ctx context.Context, job *pb.BatchJob) (string, handler.Renderer) {
+	switch p := job.GetParams().(type) {
+	case *pb.BatchJob_Qrcode:
+		return "qrcode", qrcodeRequest(ctx, p.Qrcode)
+	case *pb.BatchJob_Barcode:
+		return "barcode", barcodeRequest(ctx, p.Barcode)
+	case *pb.BatchJob_Image:
+		return "image", textImageRequest(ctx, p.Image)
+	}
+	return "", nil
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/bitqiu/pix-gen/fonts"
+	"github.com/bitqiu/pix-gen/handler"
+	"github.com/bitqiu/pix-gen/pkg/captcha"
+	"github.com/bitqiu/pix-gen/pkg/config"
+	"github.com/bitqiu/pix-gen/pkg/ratelimit"
+	"github.com/bitqiu/pix-gen/rpc"
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+)
+
+// newLogger 根据日志配置创建输出到标准输出的 slog.Logger
+func newLogger(c config.Log) *slog.Logger {
+	opts := &slog.HandlerOptions{Level: c.SlogLevel()}
+	if c.Format == "text" {
+		return slog.New(slog.NewTextHandler(os.Stdout, opts))
+	}
+	return slog.New(slog.NewJSONHandler(os.Stdout, opts))
+}
+
+// runServe 启动 HTTP 服务
+func runServe(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", cfg.Server.Addr, "listen address, overrides server.addr in the config")
+	fs.Parse(args)
+
+	// 加载配置的模板目录下的海报模板
+	if dir := cfg.Templates.Dir; dir != "" {
+		if err := handler.LoadTemplates(dir); err != nil {
+			return err
+		}
+	}
+
+	// 访问日志输出为结构化日志，每个请求带有请求 ID
+	logger := newLogger(cfg.Log)
+	slog.SetDefault(logger)
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(handler.Logger(logger), handler.Localize(), handler.Recovery(logger), handler.Metrics())
+	// 只信任配置的代理发来的 X-Forwarded-For，未配置时使用连接的对端地址
+	if err := r.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		return err
+	}
+	corsConfig := cors.DefaultConfig()
+	corsConfig.AllowCredentials = cfg.CORS.AllowCredentials
+	if len(cfg.CORS.AllowOrigins) == 1 && cfg.CORS.AllowOrigins[0] == "*" {
+		corsConfig.AllowAllOrigins = true
+	} else {
+		corsConfig.AllowOrigins = cfg.CORS.AllowOrigins
+	}
+	r.Use(cors.New(corsConfig))
+
+	// 存活检查只要求进程能处理请求，就绪检查要求字体已解析且依赖可用
+	// /health 保留给已有的探针，等同于 /readyz
+	r.GET("/livez", handler.HandleLivez)
+	r.GET("/readyz", handler.HandleReadyz)
+	r.GET("/health", handler.HandleReadyz)
+
+	// 生成接口同时支持 GET 查询参数和 POST JSON 请求体，关闭的接口不注册路由
+	// 请求体不超过 limits.maxBodyBytes，启用限流时按客户端限制请求频率，启用签名时只接受签名的请求，启用认证时只接受租户的 API key
+	api := r.Group("/")
+	api.Use(handler.LimitBody())
+	// HTTP 和 gRPC 共用租户，同一个 API key 的配额合并计算
+	var tenants *handler.Tenants
+	if cfg.Auth.Enabled {
+		var err error
+		if tenants, err = handler.NewTenants(cfg.Auth.Tenants); err != nil {
+			return err
+		}
+	}
+	// 限流在认证之前，未知的 API key 按客户端 IP 限流；gRPC 与 HTTP 共用令牌桶
+	var limiter *handler.Limiter
+	if cfg.RateLimit.Enabled {
+		limiter = handler.NewLimiter(ratelimit.NewMemoryStore(), cfg.RateLimit, tenants)
+		api.Use(limiter.RateLimit())
+	}
+	// 用量接口只需要 API key，不要求签名
+	if tenants != nil {
+		api.GET("/usage", tenants.Authenticate(), handler.HandleUsage)
+	}
+	// 签名在认证之前校验，签名无效的请求不检查配额
+	if cfg.Signing.Enabled {
+		signer, err := cfg.Signing.Signer()
+		if err != nil {
+			return err
+		}
+		api.Use(handler.RequireSignature(signer))
+	}
+	if tenants != nil {
+		api.Use(tenants.Authenticate())
+	}
+	features := cfg.Features
+	if features.Captcha {
+		api.GET("/captcha", handler.HandleCaptcha)
+		api.POST("/captcha", handler.HandleCaptcha)
+	}
+	if features.QRCode {
+		api.GET("/qrcode", handler.HandleQrcode)
+		api.POST("/qrcode", handler.HandleQrcode)
+	}
+	if features.Image {
+		api.GET("/image", handler.HandleImage)
+		api.POST("/image", handler.HandleImage)
+	}
+	if features.Barcode {
+		api.GET("/barcode", handler.HandleBarcode)
+		api.POST("/barcode", handler.HandleBarcode)
+	}
+	if features.Avatar {
+		api.GET("/avatar", handler.HandleAvatar)
+		api.POST("/avatar", handler.HandleAvatar)
+	}
+	if features.Placeholder {
+		api.GET("/placeholder/:w/:h", handler.HandlePlaceholder)
+		api.POST("/placeholder/:w/:h", handler.HandlePlaceholder)
+	}
+	if features.Batch {
+		api.POST("/batch", handler.HandleBatch)
+	}
+	if features.Labels {
+		api.POST("/labels", handler.HandleLabels)
+	}
+	if features.Render {
+		api.POST("/render/:template", handler.HandleRender)
+	}
+	r.GET("/fonts", handler.HandleFonts)
+	r.GET("/cache", handler.HandleCacheStats)
+	if features.Metrics {
+		r.GET("/metrics", handler.HandleMetrics)
+	}
+	if features.Docs {
+		r.GET("/openapi.json", handler.HandleOpenAPI)
+		r.GET("/docs", handler.HandleDocs)
+	}
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           r,
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadTimeout),
+		ReadTimeout:       time.Duration(cfg.Server.ReadTimeout),
+		WriteTimeout:      time.Duration(cfg.Server.WriteTimeout),
+		IdleTimeout:       time.Duration(cfg.Server.IdleTimeout),
+	}
+
+	// gRPC 服务签发的验证码保存在内存中，就绪检查包含验证码存储
+	var grpcSrv *rpc.Server
+	if cfg.GRPC.Enabled {
+		store := captcha.NewMemoryStore()
+		handler.RegisterReadinessCheck("captchaStore", store.Ping)
+		var err error
+		if grpcSrv, err = rpc.NewServer(cfg, tenants, limiter, store, logger); err != nil {
+			return err
+		}
+	}
+	return serve(srv, grpcSrv, logger)
+}
+
+// serve 启动 HTTP 服务和启用时的 gRPC 服务，并在后台解析字体
+// 收到 SIGINT 或 SIGTERM 后停止就绪检查、不再接受新连接，并等待进行中的请求完成
+func serve(srv *http.Server, grpcSrv *rpc.Server, logger *slog.Logger) error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	errc := make(chan error, 3)
+	go func() {
+		var err error
+		if cfg.Server.TLSCert != "" {
+			err = srv.ListenAndServeTLS(cfg.Server.TLSCert, cfg.Server.TLSKey)
+		} else {
+			err = srv.ListenAndServe()
+		}
+		if !errors.Is(err, http.ErrServerClosed) {
+			errc <- err
+		}
+	}()
+	logger.Info("listening", slog.String("addr", srv.Addr))
+
+	if grpcSrv != nil {
+		lis, err := net.Listen("tcp", cfg.GRPC.Addr)
+		if err != nil {
+			srv.Close()
+			return fmt.Errorf("grpc: %w", err)
+		}
+		go func() {
+			if err := grpcSrv.Serve(lis); err != nil {
+				errc <- fmt.Errorf("grpc: %w", err)
+			}
+		}()
+		logger.Info("listening", slog.String("addr", cfg.GRPC.Addr), slog.String("protocol", "grpc"))
+	}
+
+	go func() {
+		start := time.Now()
+		if err := fonts.Load(cfg.Fonts.Dirs...); err != nil {
+			errc <- fmt.Errorf("load fonts: %w", err)
+			return
+		}
+		logger.Info("fonts loaded", slog.Int("fonts", len(fonts.List())), slog.String("duration", time.Since(start).String()))
+	}()
+
+	select {
+	case err := <-errc:
+		shutdown(srv, grpcSrv, logger)
+		return err
+	case <-ctx.Done():
+		stop()
+		// 先让 /readyz 返回 503，等负载均衡摘除实例后再关闭监听，期间仍正常处理请求
+		handler.Drain()
+		delay := time.Duration(cfg.Server.DrainDelay)
+		logger.Info("draining", slog.String("delay", delay.String()))
+		time.Sleep(delay)
+		logger.Info("shutting down", slog.String("timeout", time.Duration(cfg.Server.ShutdownTimeout).String()))
+		return shutdown(srv, grpcSrv, logger)
+	}
+}
+
+// shutdown 等待进行中的请求完成后关闭服务，超过 server.shutdownTimeout 时强制关闭连接
+func shutdown(srv *http.Server, grpcSrv *rpc.Server, logger *slog.Logger) error {
+	handler.Drain()
+	ctx := context.Background()
+	if d := time.Duration(cfg.Server.ShutdownTimeout); d > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
+	}
+	// gRPC 服务与 HTTP 服务同时停止，共用同一个超时
+	stopped := make(chan struct{})
+	go func() {
+		if grpcSrv != nil {
+			grpcSrv.Drain()
+			grpcSrv.GracefulStop()
+		}
+		close(stopped)
+	}()
+	err := srv.Shutdown(ctx)
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		if grpcSrv != nil {
+			grpcSrv.Stop()
+		}
+		if err == nil {
+			err = ctx.Err()
+		}
+	}
+	if err != nil {
+		srv.Close()
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	logger.Info("stopped")
+	return nil
+}